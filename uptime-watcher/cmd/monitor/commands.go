@@ -0,0 +1,220 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/auth"
+	"proyecto-leng-paradigmas/ejemplo/internal/db"
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+)
+
+// commands son los subcomandos de administracion disponibles.
+var commands = map[string]bool{
+	"user add": true, "user passwd": true, "user delete": true, "user list": true,
+	"token create": true, "token list": true, "token revoke": true,
+	"migrate status": true, "migrate up": true, "migrate down": true,
+}
+
+const commandsUsage = `uso:
+  monitor [flags]                               inicia el servidor
+  monitor user add -name <usuario> [-password <pw>]
+  monitor user passwd -name <usuario> [-password <pw>]
+  monitor user delete -name <usuario>
+  monitor user list
+  monitor token create -name <nombre> -scope read|admin
+  monitor token list
+  monitor token revoke -id <id>
+  monitor migrate status
+  monitor migrate up [-steps <n>]               aplica n migraciones (todas por defecto)
+  monitor migrate down [-steps <n>]             revierte n migraciones (1 por defecto)
+
+Sin -password la contraseña se lee de la entrada estandar.
+Todos los subcomandos aceptan -db <ruta>.`
+
+// runCommand ejecuta un subcomando de administracion y devuelve el codigo de
+// salida del proceso.
+func runCommand(args []string) int {
+	if len(args) < 2 {
+		fmt.Fprintln(os.Stderr, commandsUsage)
+		return 2
+	}
+	group, action := args[0], args[1]
+	if !commands[group+" "+action] {
+		fmt.Fprintf(os.Stderr, "subcomando desconocido %q\n%s\n", group+" "+action, commandsUsage)
+		return 2
+	}
+
+	fs := flag.NewFlagSet(group+" "+action, flag.ContinueOnError)
+	dbPath := fs.String("db", filepath.Join("data", "monitor.db"), "Ruta al archivo SQLite")
+	name := fs.String("name", "", "Usuario o nombre del token")
+	password := fs.String("password", "", "Contraseña (si se omite se lee de stdin)")
+	scope := fs.String("scope", string(model.ScopeRead), "Scope del token: read o admin")
+	id := fs.String("id", "", "Id del token")
+	stepCount := fs.Int("steps", 0, "Cantidad de migraciones a aplicar o revertir")
+	if err := fs.Parse(args[2:]); err != nil {
+		return 2
+	}
+
+	sqlDB, err := db.OpenSQLite(*dbPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "no se pudo abrir base de datos: %v\n", err)
+		return 1
+	}
+	defer sqlDB.Close()
+
+	if err := dispatch(context.Background(), sqlDB, group, action, commandArgs{
+		name:     *name,
+		password: *password,
+		scope:    model.TokenScope(*scope),
+		id:       *id,
+		steps:    *stepCount,
+	}); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+type commandArgs struct {
+	name     string
+	password string
+	scope    model.TokenScope
+	id       string
+	steps    int
+}
+
+func dispatch(ctx context.Context, sqlDB *sql.DB, group, action string, args commandArgs) error {
+	out := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	defer out.Flush()
+	if group == "migrate" {
+		return migrateCommand(ctx, sqlDB, action, args.steps, out)
+	}
+
+	// user y token necesitan el esquema al dia, igual que el servidor
+	if _, err := db.Migrate(ctx, sqlDB); err != nil {
+		return err
+	}
+	manager := auth.NewManager(db.NewAuthRepository(sqlDB))
+
+	switch group + " " + action {
+	case "user add":
+		password, err := readPassword(args.password)
+		if err != nil {
+			return err
+		}
+		user, err := manager.CreateUser(ctx, args.name, password)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "usuario %s creado\n", user.Username)
+	case "user passwd":
+		password, err := readPassword(args.password)
+		if err != nil {
+			return err
+		}
+		if err := manager.SetPassword(ctx, args.name, password); err != nil {
+			return notFound(err, "usuario", args.name)
+		}
+		fmt.Fprintf(out, "contraseña de %s actualizada\n", args.name)
+	case "user delete":
+		if err := manager.DeleteUser(ctx, args.name); err != nil {
+			return notFound(err, "usuario", args.name)
+		}
+		fmt.Fprintf(out, "usuario %s eliminado\n", args.name)
+	case "user list":
+		users, err := manager.ListUsers(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, "USUARIO\tCREADO")
+		for _, u := range users {
+			fmt.Fprintf(out, "%s\t%s\n", u.Username, u.CreatedAt.Format("2006-01-02 15:04"))
+		}
+	case "token create":
+		secret, token, err := manager.CreateToken(ctx, args.name, args.scope)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "token %s (%s) creado con id %s\n", token.Name, token.Scope, token.ID)
+		fmt.Fprintf(out, "guardarlo ahora, no se vuelve a mostrar:\n%s\n", secret)
+	case "token list":
+		tokens, err := manager.ListTokens(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, "ID\tNOMBRE\tSCOPE\tCREADO")
+		for _, t := range tokens {
+			fmt.Fprintf(out, "%s\t%s\t%s\t%s\n", t.ID, t.Name, t.Scope, t.CreatedAt.Format("2006-01-02 15:04"))
+		}
+	case "token revoke":
+		if err := manager.RevokeToken(ctx, args.id); err != nil {
+			return notFound(err, "token", args.id)
+		}
+		fmt.Fprintf(out, "token %s revocado\n", args.id)
+	}
+	return nil
+}
+
+func migrateCommand(ctx context.Context, sqlDB *sql.DB, action string, n int, out io.Writer) error {
+	var (
+		done []db.Migration
+		err  error
+	)
+	switch action {
+	case "status":
+		statuses, err := db.MigrationsStatus(ctx, sqlDB)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, "VERSION\tNOMBRE\tAPLICADA")
+		for _, st := range statuses {
+			applied := "pendiente"
+			if st.AppliedAt != nil {
+				applied = st.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(out, "%d\t%s\t%s\n", st.Version, st.Name, applied)
+		}
+		return nil
+	case "up":
+		done, err = db.MigrateUp(ctx, sqlDB, n)
+	case "down":
+		done, err = db.MigrateDown(ctx, sqlDB, n)
+	}
+	for _, m := range done {
+		fmt.Fprintf(out, "%s %d_%s\n", action, m.Version, m.Name)
+	}
+	if err == nil && len(done) == 0 {
+		fmt.Fprintln(out, "nada que migrar")
+	}
+	return err
+}
+
+// readPassword usa el valor del flag o lee una linea de stdin.
+func readPassword(flagValue string) (string, error) {
+	if flagValue != "" {
+		return flagValue, nil
+	}
+	fmt.Fprint(os.Stderr, "Contraseña: ")
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("no se pudo leer la contraseña: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func notFound(err error, kind, name string) error {
+	if errors.Is(err, db.ErrNotFound) {
+		return fmt.Errorf("%s %q no existe", kind, name)
+	}
+	return err
+}
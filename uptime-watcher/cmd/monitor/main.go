@@ -5,28 +5,48 @@ import (
 	"database/sql"
 	"errors"
 	"flag"
+	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
+	// las zonas horarias de los cron funcionan aunque el host no tenga zoneinfo
+	_ "time/tzdata"
 
+	"proyecto-leng-paradigmas/ejemplo/internal/alert"
 	"proyecto-leng-paradigmas/ejemplo/internal/api"
+	"proyecto-leng-paradigmas/ejemplo/internal/auth"
 	"proyecto-leng-paradigmas/ejemplo/internal/check"
+	_ "proyecto-leng-paradigmas/ejemplo/internal/check/all"
 	"proyecto-leng-paradigmas/ejemplo/internal/config"
 	"proyecto-leng-paradigmas/ejemplo/internal/db"
+	"proyecto-leng-paradigmas/ejemplo/internal/incident"
+	"proyecto-leng-paradigmas/ejemplo/internal/maintenance"
+	"proyecto-leng-paradigmas/ejemplo/internal/metrics"
 	"proyecto-leng-paradigmas/ejemplo/internal/scheduler"
 	"proyecto-leng-paradigmas/ejemplo/internal/service"
+	"proyecto-leng-paradigmas/ejemplo/internal/statuspage"
 	"proyecto-leng-paradigmas/ejemplo/internal/store"
 	"proyecto-leng-paradigmas/ejemplo/internal/ui"
 )
 
 func main() {
+	if len(os.Args) > 1 && !strings.HasPrefix(os.Args[1], "-") {
+		os.Exit(runCommand(os.Args[1:]))
+	}
+
 	addr := flag.String("addr", ":8080", "Direccion y puerto para la API")
 	dbPath := flag.String("db", filepath.Join("data", "monitor.db"), "Ruta al archivo SQLite")
 	seedPath := flag.String("seed", "", "Archivo JSON para poblar targets si la base esta vacia")
+	autoMigrate := flag.Bool("auto-migrate", true, "Aplicar las migraciones pendientes al iniciar")
+	concurrency := flag.Int("concurrency", scheduler.DefaultConcurrency, "Maximo de chequeos simultaneos")
+	jitter := flag.Duration("jitter", 0, "Demora aleatoria maxima agregada a cada chequeo regular")
+	incidentAfter := flag.Int("incident-after", incident.DefaultThreshold, "Fallos consecutivos que abren un incidente")
 	flag.Parse()
 
 	mainLogger := log.New(os.Stdout, "[monitor] ", log.LstdFlags)
@@ -41,11 +61,17 @@ func main() {
 		}
 	}(sqlDB)
 
-	repo, err := db.NewTargetRepository(sqlDB)
-	if err != nil {
-		log.Fatalf("no se pudo inicializar repositorio: %v", err)
+	if err := prepareSchema(context.Background(), sqlDB, *autoMigrate, mainLogger); err != nil {
+		log.Fatalf("esquema de base de datos: %v", err)
 	}
 
+	repo := db.NewTargetRepository(sqlDB)
+	results := db.NewResultRepository(sqlDB)
+	incidents := db.NewIncidentRepository(sqlDB)
+	alertRepo := db.NewAlertRepository(sqlDB)
+	maintenanceRepo := db.NewMaintenanceRepository(sqlDB)
+	authRepo := db.NewAuthRepository(sqlDB)
+
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
@@ -54,35 +80,84 @@ func main() {
 	}
 
 	st := store.New(nil)
+	st.SetRepository(results)
 	runner := check.NewRunner()
 	sched := scheduler.New(runner, st, mainLogger)
+	windows := maintenance.NewSchedule(maintenanceRepo)
+	if err := windows.Load(ctx); err != nil {
+		log.Fatalf("no se pudieron cargar ventanas de mantenimiento: %v", err)
+	}
+	sched.SetMaintenance(windows)
+	sched.SetConcurrency(*concurrency)
+	sched.SetJitter(*jitter)
+	tracker := incident.NewTracker(incidents, *incidentAfter, mainLogger)
+	if err := tracker.Load(ctx); err != nil {
+		log.Fatalf("no se pudieron cargar incidentes abiertos: %v", err)
+	}
+	sched.AddObserver(tracker)
+	notifier := alert.NewNotifier(mainLogger)
+	alerts := alert.NewEngine(alertRepo, notifier, mainLogger)
+	if err := alerts.Load(ctx); err != nil {
+		log.Fatalf("no se pudieron cargar reglas de alerta: %v", err)
+	}
+	sched.AddObserver(alerts)
+	collector := metrics.NewCollector(st, sched)
+	sched.AddObserver(collector)
 	svc := service.NewTargetService(repo, st, sched)
+	svc.SetIncidents(tracker)
 
 	if err := svc.Bootstrap(ctx); err != nil {
 		log.Fatalf("no se pudieron cargar los targets: %v", err)
 	}
+	if err := st.RefreshStats(ctx); err != nil {
+		mainLogger.Printf("no se pudieron calcular estadisticas: %v", err)
+	}
+	go st.RunStats(ctx, store.DefaultStatsInterval, mainLogger)
 
 	sched.Start(ctx)
+	notifier.Start(ctx)
+
+	authn := auth.NewManager(authRepo)
+	if ok, err := authn.HasUsers(ctx); err != nil {
+		log.Fatalf("no se pudieron consultar usuarios: %v", err)
+	} else if !ok {
+		mainLogger.Printf("no hay usuarios: crear uno con 'monitor user add -name admin' para acceder al dashboard")
+	}
 
-	apiServer := api.New(svc)
-	frontend, err := ui.New(st, svc)
+	public := statuspage.NewBuilder(st, results, tracker)
+	apiServer := api.New(api.Services{
+		Targets:     svc,
+		Incidents:   tracker,
+		Alerts:      alerts,
+		Metrics:     collector,
+		Maintenance: windows,
+		Public:      public,
+		Auth:        authn,
+	})
+	frontend, err := ui.New(st, svc, windows)
 	if err != nil {
 		log.Fatalf("no se pudo inicializar frontend: %v", err)
 	}
 
-	mux := http.NewServeMux()
-	mux.HandleFunc("/ui/targets/create", frontend.HandleCreate)
-	mux.HandleFunc("/ui/targets/update", frontend.HandleUpdate)
-	mux.HandleFunc("/ui/targets/delete", frontend.HandleDelete)
-	mux.Handle("/api/", apiServer.Handler())
-	mux.Handle("/healthz", apiServer.Handler())
-	mux.Handle("/", frontend)
+	statusPage, err := ui.NewStatusPage(public)
+	if err != nil {
+		log.Fatalf("no se pudo inicializar pagina de estado: %v", err)
+	}
+
+	login, err := ui.NewLogin(authn)
+	if err != nil {
+		log.Fatalf("no se pudo inicializar login: %v", err)
+	}
+
+	mux := routes(authn, apiServer.Handler(), statusPage, login, frontend)
 
 	server := &http.Server{
 		Addr:         *addr,
 		Handler:      mux,
 		ReadTimeout:  10 * time.Second,
 		WriteTimeout: 10 * time.Second,
+		// las conexiones largas (SSE) terminan junto con la señal de cierre
+		BaseContext: func(net.Listener) context.Context { return ctx },
 	}
 
 	go func() {
@@ -101,9 +176,31 @@ func main() {
 	}
 
 	sched.Wait()
+	notifier.Wait()
 	mainLogger.Println("monitor finalizado")
 }
 
+// prepareSchema aplica las migraciones pendientes o, con autoMigrate en
+// false, falla si hay alguna para que el operador las aplique con
+// "monitor migrate up".
+func prepareSchema(ctx context.Context, sqlDB *sql.DB, autoMigrate bool, logger *log.Logger) error {
+	if !autoMigrate {
+		pending, err := db.PendingMigrations(ctx, sqlDB)
+		if err != nil {
+			return err
+		}
+		if pending > 0 {
+			return fmt.Errorf("hay %d migraciones pendientes: ejecutar 'monitor migrate up'", pending)
+		}
+		return nil
+	}
+	applied, err := db.Migrate(ctx, sqlDB)
+	for _, m := range applied {
+		logger.Printf("migracion aplicada: %d_%s", m.Version, m.Name)
+	}
+	return err
+}
+
 func maybeSeed(ctx context.Context, repo *db.TargetRepository, seedPath string, logger *log.Logger) error {
 	if seedPath == "" {
 		return nil
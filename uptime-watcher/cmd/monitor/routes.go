@@ -0,0 +1,31 @@
+package main
+
+import (
+	"net/http"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/auth"
+	"proyecto-leng-paradigmas/ejemplo/internal/ui"
+)
+
+// routes arma el mux del servidor. El dashboard requiere sesion y la API un
+// token o sesion; /healthz, /login y la pagina de estado publica quedan
+// abiertos.
+func routes(authn *auth.Manager, api, statusPage http.Handler, login *ui.Login, frontend *ui.Frontend) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle("/ui/targets/create", authn.RequireUser(http.HandlerFunc(frontend.HandleCreate)))
+	mux.Handle("/ui/targets/update", authn.RequireUser(http.HandlerFunc(frontend.HandleUpdate)))
+	mux.Handle("/ui/targets/delete", authn.RequireUser(http.HandlerFunc(frontend.HandleDelete)))
+	mux.Handle("/ui/targets/pause", authn.RequireUser(http.HandlerFunc(frontend.HandlePause)))
+	mux.Handle("/ui/targets/resume", authn.RequireUser(http.HandlerFunc(frontend.HandleResume)))
+	mux.Handle("/ui/maintenance/create", authn.RequireUser(http.HandlerFunc(frontend.HandleMaintenanceCreate)))
+	mux.Handle("/ui/maintenance/delete", authn.RequireUser(http.HandlerFunc(frontend.HandleMaintenanceDelete)))
+	mux.Handle("/api/", authn.RequireAPI(api))
+	mux.Handle("/api/public/status", api)
+	mux.Handle("/healthz", api)
+	mux.Handle("/metrics", authn.RequireAPI(api))
+	mux.Handle("/login", login)
+	mux.HandleFunc("/logout", login.HandleLogout)
+	mux.Handle("/status", statusPage)
+	mux.Handle("/", authn.RequireUser(frontend))
+	return mux
+}
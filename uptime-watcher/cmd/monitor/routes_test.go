@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/auth"
+	"proyecto-leng-paradigmas/ejemplo/internal/db"
+	"proyecto-leng-paradigmas/ejemplo/internal/store"
+	"proyecto-leng-paradigmas/ejemplo/internal/ui"
+)
+
+// TestRoutesAuth verifica que rutas quedan abiertas y cuales piden sesion o
+// token. La API y la pagina de estado son stubs: solo importa si la
+// solicitud llega a ellas.
+func TestRoutesAuth(t *testing.T) {
+	sqlDB, err := db.OpenSQLite(filepath.Join(t.TempDir(), "monitor.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqlDB.Close()
+	ctx := context.Background()
+	if _, err := db.Migrate(ctx, sqlDB); err != nil {
+		t.Fatal(err)
+	}
+	authn := auth.NewManager(db.NewAuthRepository(sqlDB))
+	if _, err := authn.CreateUser(ctx, "ana", "password-ana"); err != nil {
+		t.Fatal(err)
+	}
+	session, _, err := authn.Login(ctx, "ana", "password-ana")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reached := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	login, err := ui.NewLogin(authn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	frontend, err := ui.New(store.New(nil), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := routes(authn, reached, reached, login, frontend)
+
+	tests := []struct {
+		method, path string
+		session      bool
+		want         int
+	}{
+		{http.MethodGet, "/healthz", false, http.StatusTeapot},
+		{http.MethodGet, "/api/public/status", false, http.StatusTeapot},
+		{http.MethodGet, "/status", false, http.StatusTeapot},
+		{http.MethodGet, "/login", false, http.StatusOK},
+		{http.MethodGet, "/metrics", false, http.StatusUnauthorized},
+		{http.MethodGet, "/api/targets", false, http.StatusUnauthorized},
+		{http.MethodGet, "/api/events", false, http.StatusUnauthorized},
+		{http.MethodGet, "/api/tokens", false, http.StatusUnauthorized},
+		{http.MethodGet, "/", false, http.StatusSeeOther},
+		{http.MethodPost, "/ui/targets/delete", false, http.StatusSeeOther},
+		{http.MethodGet, "/metrics", true, http.StatusTeapot},
+		{http.MethodGet, "/api/targets", true, http.StatusTeapot},
+		{http.MethodPost, "/api/targets", true, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		if tt.session {
+			req.AddCookie(&http.Cookie{Name: auth.SessionCookie, Value: session})
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s %s (sesion %v): codigo %d, se esperaba %d", tt.method, tt.path, tt.session, rec.Code, tt.want)
+		}
+	}
+}
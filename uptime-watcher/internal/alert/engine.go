@@ -0,0 +1,346 @@
+// Package alert evalua reglas de alerta sobre los resultados de los chequeos
+// y notifica a los canales webhook configurados.
+package alert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/check"
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+)
+
+// Logger define interfaz minima para registrar eventos.
+type Logger interface {
+	Printf(format string, v ...any)
+}
+
+// Repository es la persistencia de reglas y canales.
+type Repository interface {
+	ListRules(ctx context.Context) ([]model.AlertRule, error)
+	SaveRule(ctx context.Context, rule model.AlertRule) error
+	DeleteRule(ctx context.Context, id string) error
+	ListChannels(ctx context.Context) ([]model.AlertChannel, error)
+	SaveChannel(ctx context.Context, ch model.AlertChannel) error
+	DeleteChannel(ctx context.Context, id string) error
+}
+
+// ruleState guarda la evaluacion en curso de una regla para un target.
+type ruleState struct {
+	failures  int
+	slowSince time.Time
+	firing    bool
+}
+
+type stateKey struct {
+	ruleID   string
+	targetID string
+}
+
+// Engine mantiene las reglas en memoria, las evalua con cada resultado e
+// implementa scheduler.Observer.
+type Engine struct {
+	repo     Repository
+	notifier *Notifier
+	logger   Logger
+
+	mu       sync.Mutex
+	rules    []model.AlertRule
+	channels map[string]model.AlertChannel
+	states   map[stateKey]*ruleState
+}
+
+// NewEngine crea un Engine vacio; Load trae las reglas persistidas.
+func NewEngine(repo Repository, notifier *Notifier, logger Logger) *Engine {
+	if logger == nil {
+		logger = noopLogger{}
+	}
+	return &Engine{
+		repo:     repo,
+		notifier: notifier,
+		logger:   logger,
+		channels: make(map[string]model.AlertChannel),
+		states:   make(map[stateKey]*ruleState),
+	}
+}
+
+// Load recarga reglas y canales desde el repositorio.
+func (e *Engine) Load(ctx context.Context) error {
+	rules, err := e.repo.ListRules(ctx)
+	if err != nil {
+		return err
+	}
+	channels, err := e.repo.ListChannels(ctx)
+	if err != nil {
+		return err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.rules = rules
+	e.channels = make(map[string]model.AlertChannel, len(channels))
+	for _, ch := range channels {
+		e.channels[ch.ID] = ch
+	}
+	// se descarta el estado de reglas que ya no existen
+	for key := range e.states {
+		if !containsRule(rules, key.ruleID) {
+			delete(e.states, key)
+		}
+	}
+	return nil
+}
+
+// Observe implementa scheduler.Observer.
+func (e *Engine) Observe(ctx context.Context, target model.Target, result model.CheckResult) {
+	if result.Maintenance {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, rule := range e.rules {
+		if !rule.Enabled || (rule.TargetID != "" && rule.TargetID != target.ID) {
+			continue
+		}
+		key := stateKey{ruleID: rule.ID, targetID: target.ID}
+		st, ok := e.states[key]
+		if !ok {
+			st = &ruleState{}
+			e.states[key] = st
+		}
+		if msg, fire := evaluate(rule, st, result); fire {
+			// las opciones pueden contener credenciales y no salen del monitor
+			public := target
+			public.Options = nil
+			e.dispatchLocked(rule, model.AlertEvent{
+				RuleID:              rule.ID,
+				RuleName:            rule.Name,
+				Kind:                rule.Kind,
+				Target:              public,
+				Message:             msg,
+				CheckedAt:           result.CheckedAt,
+				Latency:             result.Duration,
+				ConsecutiveFailures: st.failures,
+			})
+		}
+	}
+}
+
+// evaluate actualiza el estado de la regla con un resultado y decide si hay
+// que notificar. Cada regla se dispara una vez por episodio.
+func evaluate(rule model.AlertRule, st *ruleState, result model.CheckResult) (string, bool) {
+	threshold := rule.Threshold
+	if threshold < 1 {
+		threshold = 1
+	}
+	switch rule.Kind {
+	case model.AlertDown:
+		if result.Success {
+			st.failures, st.firing = 0, false
+			return "", false
+		}
+		st.failures++
+		if st.failures >= threshold && !st.firing {
+			st.firing = true
+			return fmt.Sprintf("caido tras %d chequeos fallidos: %s", st.failures, result.Message), true
+		}
+	case model.AlertRecovery:
+		if !result.Success {
+			st.failures++
+			return "", false
+		}
+		failures := st.failures
+		st.failures = 0
+		if failures >= threshold {
+			return fmt.Sprintf("recuperado tras %d chequeos fallidos", failures), true
+		}
+	case model.AlertLatency:
+		if !result.Success || result.Duration <= rule.Latency {
+			st.slowSince, st.firing = time.Time{}, false
+			return "", false
+		}
+		if st.slowSince.IsZero() {
+			st.slowSince = result.CheckedAt
+		}
+		if !st.firing && result.CheckedAt.Sub(st.slowSince) >= rule.Window {
+			st.firing = true
+			return fmt.Sprintf("latencia %s sobre %s durante %s", result.Duration.Round(time.Millisecond), rule.Latency, rule.Window), true
+		}
+	}
+	return "", false
+}
+
+func (e *Engine) dispatchLocked(rule model.AlertRule, event model.AlertEvent) {
+	e.logger.Printf("alerta %s (%s) para %s: %s", rule.Name, rule.Kind, event.Target.ID, event.Message)
+	for _, id := range rule.ChannelIDs {
+		ch, ok := e.channels[id]
+		if !ok || !ch.Enabled {
+			continue
+		}
+		e.notifier.Enqueue(ch, event)
+	}
+}
+
+// Forget descarta el estado de un target eliminado.
+func (e *Engine) Forget(targetID string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for key := range e.states {
+		if key.targetID == targetID {
+			delete(e.states, key)
+		}
+	}
+}
+
+// Rules devuelve las reglas cargadas.
+func (e *Engine) Rules() []model.AlertRule {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]model.AlertRule(nil), e.rules...)
+}
+
+// Channels devuelve los canales cargados. Los valores de los headers suelen
+// ser credenciales del webhook y se devuelven enmascarados con check.Redacted.
+func (e *Engine) Channels(ctx context.Context) ([]model.AlertChannel, error) {
+	channels, err := e.repo.ListChannels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range channels {
+		channels[i] = redactChannel(channels[i])
+	}
+	return channels, nil
+}
+
+// SaveRule valida, persiste y activa una regla. Sin id se genera uno nuevo.
+func (e *Engine) SaveRule(ctx context.Context, rule model.AlertRule) (model.AlertRule, error) {
+	if rule.ID == "" {
+		rule.ID = uuid.NewString()
+	}
+	if err := e.validateRule(rule); err != nil {
+		return model.AlertRule{}, err
+	}
+	if err := e.repo.SaveRule(ctx, rule); err != nil {
+		return model.AlertRule{}, err
+	}
+	return rule, e.Load(ctx)
+}
+
+// DeleteRule elimina una regla.
+func (e *Engine) DeleteRule(ctx context.Context, id string) error {
+	if err := e.repo.DeleteRule(ctx, id); err != nil {
+		return err
+	}
+	return e.Load(ctx)
+}
+
+// SaveChannel valida y persiste un canal. Sin id se genera uno nuevo. Al
+// editar, Headers nil conserva los headers guardados y un valor enmascarado
+// conserva el del header del mismo nombre; la respuesta vuelve enmascarada.
+func (e *Engine) SaveChannel(ctx context.Context, ch model.AlertChannel) (model.AlertChannel, error) {
+	if ch.ID == "" {
+		ch.ID = uuid.NewString()
+	} else {
+		e.mu.Lock()
+		stored := e.channels[ch.ID]
+		e.mu.Unlock()
+		ch.Headers = keepHeaders(ch.Headers, stored.Headers)
+	}
+	if ch.Name == "" {
+		return model.AlertChannel{}, errors.New("nombre requerido")
+	}
+	u, err := url.Parse(ch.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return model.AlertChannel{}, errors.New("url de webhook invalida")
+	}
+	if err := e.repo.SaveChannel(ctx, ch); err != nil {
+		return model.AlertChannel{}, err
+	}
+	return redactChannel(ch), e.Load(ctx)
+}
+
+func redactChannel(ch model.AlertChannel) model.AlertChannel {
+	if len(ch.Headers) == 0 {
+		return ch
+	}
+	masked := make(map[string]string, len(ch.Headers))
+	for name := range ch.Headers {
+		masked[name] = check.Redacted
+	}
+	ch.Headers = masked
+	return ch
+}
+
+// keepHeaders completa los headers enmascarados de next con los guardados.
+func keepHeaders(next, stored map[string]string) map[string]string {
+	if next == nil {
+		return stored
+	}
+	out := make(map[string]string, len(next))
+	for name, value := range next {
+		if value == check.Redacted {
+			previous, ok := stored[name]
+			if !ok {
+				continue
+			}
+			value = previous
+		}
+		out[name] = value
+	}
+	return out
+}
+
+// DeleteChannel elimina un canal.
+func (e *Engine) DeleteChannel(ctx context.Context, id string) error {
+	if err := e.repo.DeleteChannel(ctx, id); err != nil {
+		return err
+	}
+	return e.Load(ctx)
+}
+
+func (e *Engine) validateRule(rule model.AlertRule) error {
+	if rule.Name == "" {
+		return errors.New("nombre requerido")
+	}
+	switch rule.Kind {
+	case model.AlertDown, model.AlertRecovery:
+		if rule.Threshold < 0 {
+			return errors.New("threshold no puede ser negativo")
+		}
+	case model.AlertLatency:
+		if rule.Latency <= 0 {
+			return errors.New("latency debe ser mayor a 0")
+		}
+		if rule.Window < 0 {
+			return errors.New("window no puede ser negativo")
+		}
+	default:
+		return fmt.Errorf("tipo de regla desconocido: %s", rule.Kind)
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, id := range rule.ChannelIDs {
+		if _, ok := e.channels[id]; !ok {
+			return fmt.Errorf("canal desconocido: %s", id)
+		}
+	}
+	return nil
+}
+
+func containsRule(rules []model.AlertRule, id string) bool {
+	for _, r := range rules {
+		if r.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+type noopLogger struct{}
+
+func (noopLogger) Printf(string, ...any) {}
@@ -0,0 +1,294 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/check"
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+)
+
+func TestRedactChannel(t *testing.T) {
+	ch := model.AlertChannel{ID: "c", Headers: map[string]string{"Authorization": "Bearer s3cr3t"}}
+	got := redactChannel(ch)
+	if got.Headers["Authorization"] != check.Redacted {
+		t.Errorf("headers = %v, se esperaba el valor enmascarado", got.Headers)
+	}
+	if ch.Headers["Authorization"] != "Bearer s3cr3t" {
+		t.Error("redactChannel modifico el canal original")
+	}
+}
+
+func TestKeepHeaders(t *testing.T) {
+	stored := map[string]string{"Authorization": "Bearer s3cr3t", "X-Team": "ops"}
+	tests := []struct {
+		name string
+		next map[string]string
+		want map[string]string
+	}{
+		{"sin headers conserva", nil, stored},
+		{"vacio borra", map[string]string{}, map[string]string{}},
+		{"enmascarado conserva", map[string]string{"Authorization": check.Redacted, "X-Team": "sre"},
+			map[string]string{"Authorization": "Bearer s3cr3t", "X-Team": "sre"}},
+		{"enmascarado desconocido se descarta", map[string]string{"X-Otro": check.Redacted}, map[string]string{}},
+	}
+	for _, tt := range tests {
+		if got := keepHeaders(tt.next, stored); !maps.Equal(got, tt.want) {
+			t.Errorf("%s: keepHeaders = %v, se esperaba %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+var epoch = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// memRepo es un Repository en memoria.
+type memRepo struct {
+	rules    []model.AlertRule
+	channels []model.AlertChannel
+}
+
+func (r *memRepo) ListRules(context.Context) ([]model.AlertRule, error) { return r.rules, nil }
+func (r *memRepo) SaveRule(context.Context, model.AlertRule) error      { return nil }
+func (r *memRepo) DeleteRule(context.Context, string) error             { return nil }
+func (r *memRepo) ListChannels(context.Context) ([]model.AlertChannel, error) {
+	return r.channels, nil
+}
+func (r *memRepo) SaveChannel(context.Context, model.AlertChannel) error { return nil }
+func (r *memRepo) DeleteChannel(context.Context, string) error           { return nil }
+
+// webhook es un servidor que registra los eventos recibidos. Responde con
+// los codigos de fail mientras queden y luego con 204.
+type webhook struct {
+	*httptest.Server
+	mu      sync.Mutex
+	events  []model.AlertEvent
+	headers []http.Header
+	times   []time.Time
+	fail    []int
+}
+
+func newWebhook(t *testing.T, fail ...int) *webhook {
+	wh := &webhook{fail: fail}
+	wh.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event model.AlertEvent
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("payload invalido: %v", err)
+		}
+		wh.mu.Lock()
+		defer wh.mu.Unlock()
+		wh.events = append(wh.events, event)
+		wh.headers = append(wh.headers, r.Header.Clone())
+		wh.times = append(wh.times, time.Now())
+		if len(wh.fail) > 0 {
+			code := wh.fail[0]
+			wh.fail = wh.fail[1:]
+			w.WriteHeader(code)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(wh.Close)
+	return wh
+}
+
+// take devuelve los eventos recibidos desde la ultima llamada.
+func (wh *webhook) take() []model.AlertEvent {
+	wh.mu.Lock()
+	defer wh.mu.Unlock()
+	events := wh.events
+	wh.events = nil
+	return events
+}
+
+// testEngine arma un Engine con las reglas dadas y un canal "hook" hacia el
+// webhook. Las entregas no pasan por los workers del Notifier: observe las
+// envia enseguida, asi cada paso del test sabe que llego al webhook.
+type testEngine struct {
+	t      *testing.T
+	engine *Engine
+	hook   *webhook
+}
+
+func newTestEngine(t *testing.T, rules ...model.AlertRule) *testEngine {
+	t.Helper()
+	hook := newWebhook(t)
+	for i := range rules {
+		rules[i].Enabled = true
+		rules[i].ChannelIDs = []string{"hook"}
+	}
+	repo := &memRepo{
+		rules:    rules,
+		channels: []model.AlertChannel{{ID: "hook", URL: hook.URL, Headers: map[string]string{"X-Token": "s3cr3t"}, Enabled: true}},
+	}
+	engine := NewEngine(repo, NewNotifier(nil), nil)
+	if err := engine.Load(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return &testEngine{t: t, engine: engine, hook: hook}
+}
+
+// observe evalua un resultado de target y entrega las alertas que genere.
+func (te *testEngine) observe(target model.Target, result model.CheckResult) []model.AlertEvent {
+	te.t.Helper()
+	result.TargetID = target.ID
+	te.engine.Observe(context.Background(), target, result)
+	n := te.engine.notifier
+	for {
+		select {
+		case d := <-n.queue:
+			n.deliver(context.Background(), d)
+		default:
+			return te.hook.take()
+		}
+	}
+}
+
+func up(at time.Duration, latency time.Duration) model.CheckResult {
+	return model.CheckResult{CheckedAt: epoch.Add(at), Duration: latency, Success: true}
+}
+
+func down(at time.Duration) model.CheckResult {
+	return model.CheckResult{CheckedAt: epoch.Add(at), Success: false, Message: "connection refused"}
+}
+
+var web = model.Target{ID: "web", Name: "Web", Kind: model.TargetHTTP, Options: map[string]string{"auth_token": "s3cr3t"}}
+
+// fired resume que pasos de una secuencia generaron alertas.
+func fired(t *testing.T, te *testEngine, target model.Target, results []model.CheckResult) ([]int, []model.AlertEvent) {
+	t.Helper()
+	var steps []int
+	var all []model.AlertEvent
+	for i, res := range results {
+		events := te.observe(target, res)
+		for range events {
+			steps = append(steps, i)
+		}
+		all = append(all, events...)
+	}
+	return steps, all
+}
+
+func TestDownFiresOncePerEpisode(t *testing.T) {
+	te := newTestEngine(t, model.AlertRule{ID: "down", Name: "caido", Kind: model.AlertDown, Threshold: 3})
+	results := []model.CheckResult{
+		down(0), down(1), down(2), // 2: alcanza el umbral
+		down(3), down(4), // sigue caido: no se repite
+		up(5, 0),
+		down(6), down(7), down(8), // 8: nuevo episodio
+	}
+	steps, events := fired(t, te, web, results)
+	if !slices.Equal(steps, []int{2, 8}) {
+		t.Fatalf("alertas en los pasos %v, se esperaba [2 8]", steps)
+	}
+	event := events[0]
+	if event.Kind != model.AlertDown || event.RuleID != "down" || event.Target.ID != "web" || event.ConsecutiveFailures != 3 {
+		t.Errorf("evento = %+v", event)
+	}
+	if !strings.Contains(event.Message, "3 chequeos fallidos") || !strings.Contains(event.Message, "connection refused") {
+		t.Errorf("mensaje = %q", event.Message)
+	}
+	if event.Target.Options != nil {
+		t.Errorf("el evento expone las opciones del target: %v", event.Target.Options)
+	}
+	if got := te.hook.headers[0].Get("X-Token"); got != "s3cr3t" {
+		t.Errorf("header del canal = %q, se esperaba el valor guardado", got)
+	}
+}
+
+func TestRecoveryOnlyAfterDown(t *testing.T) {
+	te := newTestEngine(t, model.AlertRule{ID: "rec", Name: "recuperado", Kind: model.AlertRecovery, Threshold: 2})
+	results := []model.CheckResult{
+		up(0, 0),          // sin caida previa
+		down(1), up(2, 0), // un fallo no alcanza el umbral
+		down(3), down(4), up(5, 0), // 5: recupera tras 2 fallos
+		up(6, 0), // ya recuperado
+	}
+	steps, events := fired(t, te, web, results)
+	if !slices.Equal(steps, []int{5}) {
+		t.Fatalf("alertas en los pasos %v, se esperaba [5]", steps)
+	}
+	if events[0].Kind != model.AlertRecovery || !strings.Contains(events[0].Message, "2 chequeos fallidos") {
+		t.Errorf("evento = %+v", events[0])
+	}
+}
+
+func TestLatencyOverWindow(t *testing.T) {
+	te := newTestEngine(t, model.AlertRule{ID: "lat", Name: "lento", Kind: model.AlertLatency, Latency: 100 * time.Millisecond, Window: time.Minute})
+	slow := 300 * time.Millisecond
+	s := time.Second
+	results := []model.CheckResult{
+		up(0, slow), up(30*s, slow), // lento, pero menos de Window
+		up(60*s, slow),                   // 2: lento durante 1m
+		up(90*s, slow),                   // sigue lento: no se repite
+		up(120*s, 50*time.Millisecond),   // rapido: reinicia la ventana
+		up(150*s, slow), up(200*s, slow), // 50s lento
+		down(205 * s),                    // un fallo no cuenta como lento y reinicia
+		up(210*s, slow), up(260*s, slow), // 50s desde el reinicio
+		up(270*s, slow),                 // 10: 1m desde 210s
+		up(280*s, 100*time.Millisecond), // igual al limite no es lento
+	}
+	steps, events := fired(t, te, web, results)
+	if !slices.Equal(steps, []int{2, 10}) {
+		t.Fatalf("alertas en los pasos %v, se esperaba [2 10]", steps)
+	}
+	if events[0].Latency != slow || !strings.Contains(events[0].Message, "latencia 300ms") {
+		t.Errorf("evento = %+v", events[0])
+	}
+}
+
+func TestMaintenanceResultsIgnored(t *testing.T) {
+	te := newTestEngine(t,
+		model.AlertRule{ID: "down", Name: "caido", Kind: model.AlertDown, Threshold: 2},
+		model.AlertRule{ID: "rec", Name: "recuperado", Kind: model.AlertRecovery, Threshold: 2},
+	)
+	maint := func(res model.CheckResult) model.CheckResult {
+		res.Maintenance = true
+		return res
+	}
+	results := []model.CheckResult{
+		maint(down(0)), maint(down(1)), maint(down(2)), // en mantenimiento no cuentan
+		down(3),         // primer fallo real
+		maint(up(4, 0)), // un exito en mantenimiento no reinicia
+		down(5),         // 5: segundo fallo real, down
+		maint(up(6, 0)), // ni recupera
+		up(7, 0),        // 7: recovery
+	}
+	steps, events := fired(t, te, web, results)
+	if !slices.Equal(steps, []int{5, 7}) {
+		t.Fatalf("alertas en los pasos %v, se esperaba [5 7]", steps)
+	}
+	if events[0].Kind != model.AlertDown || events[1].Kind != model.AlertRecovery {
+		t.Errorf("eventos %s y %s, se esperaba down y recovery", events[0].Kind, events[1].Kind)
+	}
+}
+
+func TestRuleScope(t *testing.T) {
+	te := newTestEngine(t,
+		model.AlertRule{ID: "solo-api", Name: "api", Kind: model.AlertDown, TargetID: "api", Threshold: 1},
+		model.AlertRule{ID: "todos", Name: "todos", Kind: model.AlertDown, Threshold: 1},
+	)
+	api := model.Target{ID: "api", Name: "API", Kind: model.TargetHTTP}
+	rules := func(events []model.AlertEvent) []string {
+		var ids []string
+		for _, e := range events {
+			ids = append(ids, e.RuleID)
+		}
+		slices.Sort(ids)
+		return ids
+	}
+	if got := rules(te.observe(web, down(0))); !slices.Equal(got, []string{"todos"}) {
+		t.Errorf("web: reglas %v, se esperaba [todos]", got)
+	}
+	// el estado es por target: la caida de web no afecta a api
+	if got := rules(te.observe(api, down(0))); !slices.Equal(got, []string{"solo-api", "todos"}) {
+		t.Errorf("api: reglas %v, se esperaba [solo-api todos]", got)
+	}
+}
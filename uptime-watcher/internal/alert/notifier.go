@@ -0,0 +1,131 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+)
+
+// Valores por defecto de la politica de reintentos.
+const (
+	DefaultAttempts = 4
+	DefaultBackoff  = time.Second
+	queueSize       = 256
+	dispatchWorkers = 4
+)
+
+type delivery struct {
+	channel model.AlertChannel
+	event   model.AlertEvent
+}
+
+// Notifier entrega eventos a webhooks con reintentos y backoff exponencial.
+// Los envios se encolan para no bloquear al scheduler.
+type Notifier struct {
+	Client   *http.Client
+	Attempts int
+	Backoff  time.Duration
+
+	logger Logger
+	queue  chan delivery
+	wg     sync.WaitGroup
+}
+
+// NewNotifier crea un Notifier con la politica por defecto.
+func NewNotifier(logger Logger) *Notifier {
+	if logger == nil {
+		logger = noopLogger{}
+	}
+	return &Notifier{
+		Client:   &http.Client{Timeout: 10 * time.Second},
+		Attempts: DefaultAttempts,
+		Backoff:  DefaultBackoff,
+		logger:   logger,
+		queue:    make(chan delivery, queueSize),
+	}
+}
+
+// Start lanza los workers de envio hasta que ctx se cancele.
+func (n *Notifier) Start(ctx context.Context) {
+	for i := 0; i < dispatchWorkers; i++ {
+		n.wg.Add(1)
+		go func() {
+			defer n.wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case d := <-n.queue:
+					n.deliver(ctx, d)
+				}
+			}
+		}()
+	}
+}
+
+// Wait bloquea hasta que los workers terminen.
+func (n *Notifier) Wait() {
+	n.wg.Wait()
+}
+
+// Enqueue agenda el envio de un evento. Si la cola esta llena el evento se
+// descarta para no frenar los chequeos.
+func (n *Notifier) Enqueue(channel model.AlertChannel, event model.AlertEvent) {
+	select {
+	case n.queue <- delivery{channel: channel, event: event}:
+	default:
+		n.logger.Printf("alerta %s descartada: cola de envio llena", event.RuleID)
+	}
+}
+
+func (n *Notifier) deliver(ctx context.Context, d delivery) {
+	backoff := n.Backoff
+	for attempt := 1; attempt <= n.Attempts; attempt++ {
+		err := n.Send(ctx, d.channel, d.event)
+		if err == nil {
+			return
+		}
+		if attempt == n.Attempts {
+			n.logger.Printf("canal %s: alerta %s no entregada tras %d intentos: %v", d.channel.ID, d.event.RuleID, attempt, err)
+			return
+		}
+		n.logger.Printf("canal %s: intento %d fallido: %v (reintento en %s)", d.channel.ID, attempt, err, backoff)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// Send realiza un unico POST del evento al webhook del canal.
+func (n *Notifier) Send(ctx context.Context, channel model.AlertChannel, event model.AlertEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, channel.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range channel.Headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook respondio %s", resp.Status)
+	}
+	return nil
+}
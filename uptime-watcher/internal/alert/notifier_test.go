@@ -0,0 +1,90 @@
+package alert
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+)
+
+func newTestNotifier(attempts int, backoff time.Duration) *Notifier {
+	n := NewNotifier(nil)
+	n.Attempts = attempts
+	n.Backoff = backoff
+	return n
+}
+
+func TestNotifierRetriesWithBackoff(t *testing.T) {
+	const backoff = 20 * time.Millisecond
+	hook := newWebhook(t, http.StatusInternalServerError, http.StatusBadGateway)
+	n := newTestNotifier(4, backoff)
+	n.deliver(context.Background(), delivery{
+		channel: model.AlertChannel{ID: "hook", URL: hook.URL},
+		event:   model.AlertEvent{RuleID: "down", Kind: model.AlertDown},
+	})
+
+	// dos fallos y un exito: no hay cuarto intento
+	if got := len(hook.take()); got != 3 {
+		t.Fatalf("%d intentos, se esperaban 3", got)
+	}
+	// el backoff se duplica en cada reintento
+	for i, want := range []time.Duration{backoff, 2 * backoff} {
+		if gap := hook.times[i+1].Sub(hook.times[i]); gap < want {
+			t.Errorf("espera antes del intento %d = %s, se esperaba al menos %s", i+2, gap, want)
+		}
+	}
+}
+
+func TestNotifierGivesUp(t *testing.T) {
+	hook := newWebhook(t, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError)
+	n := newTestNotifier(3, time.Millisecond)
+	n.deliver(context.Background(), delivery{channel: model.AlertChannel{ID: "hook", URL: hook.URL}})
+	if got := len(hook.take()); got != 3 {
+		t.Fatalf("%d intentos, se esperaban Attempts = 3", got)
+	}
+}
+
+func TestNotifierStopsOnCancel(t *testing.T) {
+	hook := newWebhook(t, http.StatusInternalServerError, http.StatusInternalServerError)
+	n := newTestNotifier(4, time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		n.deliver(ctx, delivery{channel: model.AlertChannel{ID: "hook", URL: hook.URL}})
+		close(done)
+	}()
+	// tras el primer intento queda esperando el backoff de una hora
+	for deadline := time.Now().Add(5 * time.Second); len(hook.take()) == 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("el primer intento no llego al webhook")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("deliver no termino al cancelar el contexto")
+	}
+}
+
+func TestNotifierStartDelivers(t *testing.T) {
+	hook := newWebhook(t)
+	n := newTestNotifier(1, time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	n.Start(ctx)
+	n.Enqueue(model.AlertChannel{ID: "hook", URL: hook.URL}, model.AlertEvent{RuleID: "down"})
+	deadline := time.Now().Add(5 * time.Second)
+	var events []model.AlertEvent
+	for len(events) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+		events = hook.take()
+	}
+	cancel()
+	n.Wait()
+	if len(events) != 1 || events[0].RuleID != "down" {
+		t.Fatalf("eventos = %+v, se esperaba la alerta encolada", events)
+	}
+}
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/db"
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+)
+
+type ruleRequest struct {
+	Name       string   `json:"name"`
+	Kind       string   `json:"kind"`
+	TargetID   string   `json:"target_id"`
+	Threshold  int      `json:"threshold"`
+	Latency    string   `json:"latency"`
+	Window     string   `json:"window"`
+	ChannelIDs []string `json:"channel_ids"`
+	Enabled    *bool    `json:"enabled"`
+}
+
+type channelRequest struct {
+	Name    string            `json:"name"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers"`
+	Enabled *bool             `json:"enabled"`
+}
+
+func (s *Server) handleAlertRules(w http.ResponseWriter, r *http.Request) {
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/alerts/rules"), "/")
+	switch {
+	case id == "" && r.Method == http.MethodGet:
+		rules := s.alerts.Rules()
+		if rules == nil {
+			rules = []model.AlertRule{}
+		}
+		writeJSON(w, http.StatusOK, rules)
+	case id == "" && r.Method == http.MethodPost:
+		s.saveRule(w, r, "", http.StatusCreated)
+	case id != "" && (r.Method == http.MethodPut || r.Method == http.MethodPatch):
+		s.saveRule(w, r, id, http.StatusOK)
+	case id != "" && r.Method == http.MethodDelete:
+		if err := s.alerts.DeleteRule(r.Context(), id); err != nil {
+			writeError(w, errorStatus(err), err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) saveRule(w http.ResponseWriter, r *http.Request, id string, code int) {
+	var req ruleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "json invalido: "+err.Error())
+		return
+	}
+	rule := model.AlertRule{
+		ID:         id,
+		Name:       strings.TrimSpace(req.Name),
+		Kind:       model.AlertKind(strings.ToLower(strings.TrimSpace(req.Kind))),
+		TargetID:   strings.TrimSpace(req.TargetID),
+		Threshold:  req.Threshold,
+		ChannelIDs: req.ChannelIDs,
+		Enabled:    req.Enabled == nil || *req.Enabled,
+	}
+	var err error
+	if rule.Latency, err = parseOptionalDuration(req.Latency); err != nil {
+		writeError(w, http.StatusBadRequest, "latency invalida: "+err.Error())
+		return
+	}
+	if rule.Window, err = parseOptionalDuration(req.Window); err != nil {
+		writeError(w, http.StatusBadRequest, "window invalida: "+err.Error())
+		return
+	}
+	saved, err := s.alerts.SaveRule(r.Context(), rule)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, code, saved)
+}
+
+func (s *Server) handleAlertChannels(w http.ResponseWriter, r *http.Request) {
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/alerts/channels"), "/")
+	switch {
+	case id == "" && r.Method == http.MethodGet:
+		channels, err := s.alerts.Channels(r.Context())
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if channels == nil {
+			channels = []model.AlertChannel{}
+		}
+		writeJSON(w, http.StatusOK, channels)
+	case id == "" && r.Method == http.MethodPost:
+		s.saveChannel(w, r, "", http.StatusCreated)
+	case id != "" && (r.Method == http.MethodPut || r.Method == http.MethodPatch):
+		s.saveChannel(w, r, id, http.StatusOK)
+	case id != "" && r.Method == http.MethodDelete:
+		if err := s.alerts.DeleteChannel(r.Context(), id); err != nil {
+			writeError(w, errorStatus(err), err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) saveChannel(w http.ResponseWriter, r *http.Request, id string, code int) {
+	var req channelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "json invalido: "+err.Error())
+		return
+	}
+	saved, err := s.alerts.SaveChannel(r.Context(), model.AlertChannel{
+		ID:      id,
+		Name:    strings.TrimSpace(req.Name),
+		URL:     strings.TrimSpace(req.URL),
+		Headers: req.Headers,
+		Enabled: req.Enabled == nil || *req.Enabled,
+	})
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, code, saved)
+}
+
+func parseOptionalDuration(raw string) (time.Duration, error) {
+	if raw == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(raw)
+}
+
+// errorStatus traduce errores del repositorio a codigos HTTP.
+func errorStatus(err error) int {
+	if errors.Is(err, db.ErrNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/alert"
+	"proyecto-leng-paradigmas/ejemplo/internal/check"
+	_ "proyecto-leng-paradigmas/ejemplo/internal/check/all"
+	"proyecto-leng-paradigmas/ejemplo/internal/db"
+	"proyecto-leng-paradigmas/ejemplo/internal/incident"
+	"proyecto-leng-paradigmas/ejemplo/internal/maintenance"
+	"proyecto-leng-paradigmas/ejemplo/internal/metrics"
+	"proyecto-leng-paradigmas/ejemplo/internal/scheduler"
+	"proyecto-leng-paradigmas/ejemplo/internal/service"
+	"proyecto-leng-paradigmas/ejemplo/internal/statuspage"
+	"proyecto-leng-paradigmas/ejemplo/internal/store"
+)
+
+// testAPI es un Server sobre una base temporal, con el scheduler sin arrancar:
+// los cambios se persisten pero no se corren chequeos. La autenticacion se
+// prueba en internal/auth y cmd/monitor, asi que aqui se llama al Handler
+// directamente.
+type testAPI struct {
+	*Server
+	store     *store.Store
+	sched     *scheduler.Scheduler
+	results   *db.ResultRepository
+	incidents *incident.Tracker
+}
+
+func newTestAPI(t *testing.T) *testAPI {
+	t.Helper()
+	ctx := context.Background()
+	sqlDB, err := db.OpenSQLite(filepath.Join(t.TempDir(), "api.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	if _, err := db.Migrate(ctx, sqlDB); err != nil {
+		t.Fatal(err)
+	}
+
+	results := db.NewResultRepository(sqlDB)
+	st := store.New(nil)
+	st.SetRepository(results)
+	sched := scheduler.New(check.NewRunner(), st, nil)
+	tracker := incident.NewTracker(db.NewIncidentRepository(sqlDB), incident.DefaultThreshold, nil)
+	svc := service.NewTargetService(db.NewTargetRepository(sqlDB), st, sched)
+	svc.SetIncidents(tracker)
+	server := New(Services{
+		Targets:     svc,
+		Incidents:   tracker,
+		Alerts:      alert.NewEngine(db.NewAlertRepository(sqlDB), alert.NewNotifier(nil), nil),
+		Metrics:     metrics.NewCollector(st, sched),
+		Maintenance: maintenance.NewSchedule(db.NewMaintenanceRepository(sqlDB)),
+		Public:      statuspage.NewBuilder(st, results, tracker),
+	})
+	return &testAPI{Server: server, store: st, sched: sched, results: results, incidents: tracker}
+}
+
+// do envia una solicitud al Handler; body se codifica como JSON si no es nil.
+func (a *testAPI) do(t *testing.T, method, path string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader = strings.NewReader(string(raw))
+	}
+	req := httptest.NewRequest(method, path, reader)
+	rec := httptest.NewRecorder()
+	a.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+// decode lee la respuesta JSON en v, fallando si el codigo no es want.
+func decode(t *testing.T, rec *httptest.ResponseRecorder, want int, v any) {
+	t.Helper()
+	if rec.Code != want {
+		t.Fatalf("codigo %d, se esperaba %d: %s", rec.Code, want, rec.Body.String())
+	}
+	if v == nil {
+		return
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		t.Fatalf("respuesta invalida %q: %v", rec.Body.String(), err)
+	}
+}
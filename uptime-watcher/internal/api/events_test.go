@@ -0,0 +1,90 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/check"
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+	"proyecto-leng-paradigmas/ejemplo/internal/store"
+)
+
+func TestEventsRedactSecrets(t *testing.T) {
+	a := newTestAPI(t)
+	ctx := context.Background()
+	target, err := a.svc.CreateTarget(ctx, model.Target{
+		Name: "web", Kind: model.TargetHTTP, URL: "https://example.com", Frequency: time.Minute, Timeout: time.Second,
+		Options: map[string]string{"auth_type": "bearer", "auth_token": "s3cr3t-token", "headers": "X-Api-Key: hunter2"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// handleEvents necesita una conexion real para quitar el WriteTimeout
+	srv := httptest.NewServer(a.Handler())
+	defer srv.Close()
+	reqCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, srv.URL+"/api/events", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+
+	// la suscripcion ya existe cuando llegan los headers
+	if err := a.store.Record(ctx, model.CheckResult{TargetID: target.ID, CheckedAt: time.Now(), Success: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	// un resultado y la transicion de unknown a up
+	lines := bufio.NewScanner(resp.Body)
+	var events []store.Event
+	for len(events) < 2 && lines.Scan() {
+		data, ok := strings.CutPrefix(lines.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		for _, secret := range []string{"s3cr3t-token", "hunter2"} {
+			if strings.Contains(data, secret) {
+				t.Errorf("el evento expone %q: %s", secret, data)
+			}
+		}
+		var ev store.Event
+		if err := json.Unmarshal([]byte(data), &ev); err != nil {
+			t.Fatalf("evento invalido %q: %v", data, err)
+		}
+		events = append(events, ev)
+	}
+	if len(events) != 2 {
+		t.Fatalf("se recibieron %d eventos, se esperaban 2: %v", len(events), lines.Err())
+	}
+	for _, ev := range events {
+		opts := ev.Status.Target.Options
+		if opts["auth_token"] != check.Redacted || opts["headers"] != check.Redacted {
+			t.Errorf("evento %s con opciones %v, se esperaban los secretos como %s", ev.Type, opts, check.Redacted)
+		}
+		if opts["auth_type"] != "bearer" {
+			t.Errorf("evento %s: auth_type = %q, las opciones no secretas se conservan", ev.Type, opts["auth_type"])
+		}
+	}
+
+	// redactar el evento no toca el target que usa el chequeo
+	for _, st := range a.store.Status() {
+		if st.Target.ID == target.ID && st.Target.Options["auth_token"] != "s3cr3t-token" {
+			t.Errorf("el store quedo con auth_token = %q", st.Target.Options["auth_token"])
+		}
+	}
+}
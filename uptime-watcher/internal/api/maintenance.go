@@ -0,0 +1,93 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+)
+
+type maintenanceRequest struct {
+	Name      string   `json:"name"`
+	TargetIDs []string `json:"target_ids"`
+	Groups    []string `json:"groups"`
+	Start     string   `json:"start"`
+	Duration  string   `json:"duration"`
+	Repeat    string   `json:"repeat"`
+}
+
+// maintenanceView agrega a la ventana su estado respecto del momento actual.
+type maintenanceView struct {
+	model.MaintenanceWindow
+	Active    bool       `json:"active"`
+	NextStart *time.Time `json:"next_start,omitempty"`
+}
+
+func (s *Server) handleMaintenance(w http.ResponseWriter, r *http.Request) {
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/maintenance"), "/")
+	switch {
+	case id == "" && r.Method == http.MethodGet:
+		now := time.Now()
+		views := []maintenanceView{}
+		for _, mw := range s.maintenance.Windows() {
+			view := maintenanceView{MaintenanceWindow: mw, Active: mw.ActiveAt(now)}
+			if next, ok := mw.NextStart(now); ok {
+				view.NextStart = &next
+			}
+			views = append(views, view)
+		}
+		writeJSON(w, http.StatusOK, views)
+	case id == "" && r.Method == http.MethodPost:
+		s.saveMaintenance(w, r, "", http.StatusCreated)
+	case id != "" && (r.Method == http.MethodPut || r.Method == http.MethodPatch):
+		s.saveMaintenance(w, r, id, http.StatusOK)
+	case id != "" && r.Method == http.MethodDelete:
+		if err := s.maintenance.Delete(r.Context(), id); err != nil {
+			writeError(w, errorStatus(err), err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) saveMaintenance(w http.ResponseWriter, r *http.Request, id string, code int) {
+	var req maintenanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "json invalido: "+err.Error())
+		return
+	}
+	start, err := parseTime(req.Start)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "start invalido: "+err.Error())
+		return
+	}
+	duration, err := parseOptionalDuration(req.Duration)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "duration invalida: "+err.Error())
+		return
+	}
+	window := model.MaintenanceWindow{
+		ID:        id,
+		Name:      strings.TrimSpace(req.Name),
+		TargetIDs: req.TargetIDs,
+		Groups:    req.Groups,
+		Start:     start,
+		Duration:  duration,
+		Repeat:    model.MaintenanceRepeat(strings.ToLower(strings.TrimSpace(req.Repeat))),
+	}
+	// con id se edita una ventana existente: un id desconocido es 404
+	save := s.maintenance.Save
+	if id != "" {
+		save = s.maintenance.Update
+	}
+	saved, err := save(r.Context(), window)
+	if err != nil {
+		writeError(w, errorStatus(err), err.Error())
+		return
+	}
+	writeJSON(w, code, saved)
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestMaintenanceUpdate(t *testing.T) {
+	a := newTestAPI(t)
+	window := map[string]string{"name": "deploy", "start": "2026-01-01T03:00:00Z", "duration": "30m", "repeat": "daily"}
+
+	var created maintenanceView
+	decode(t, a.do(t, http.MethodPost, "/api/maintenance", window), http.StatusCreated, &created)
+	if created.ID == "" {
+		t.Fatal("la ventana creada no tiene id")
+	}
+
+	// un id desconocido no crea una ventana nueva
+	decode(t, a.do(t, http.MethodPut, "/api/maintenance/no-existe", window), http.StatusNotFound, nil)
+	decode(t, a.do(t, http.MethodPatch, "/api/maintenance/no-existe", window), http.StatusNotFound, nil)
+
+	window["name"] = "deploy semanal"
+	window["repeat"] = "weekly"
+	var updated maintenanceView
+	decode(t, a.do(t, http.MethodPut, "/api/maintenance/"+created.ID, window), http.StatusOK, &updated)
+	if updated.ID != created.ID || updated.Name != "deploy semanal" {
+		t.Fatalf("ventana actualizada = %+v", updated.MaintenanceWindow)
+	}
+
+	// una ventana invalida sigue siendo 400 aunque el id exista
+	window["duration"] = "0s"
+	decode(t, a.do(t, http.MethodPut, "/api/maintenance/"+created.ID, window), http.StatusBadRequest, nil)
+
+	var listed []maintenanceView
+	decode(t, a.do(t, http.MethodGet, "/api/maintenance", nil), http.StatusOK, &listed)
+	if len(listed) != 1 || listed[0].Name != "deploy semanal" || listed[0].Repeat != "weekly" {
+		t.Fatalf("ventanas = %+v, se esperaba solo la actualizada", listed)
+	}
+}
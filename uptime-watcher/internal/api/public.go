@@ -0,0 +1,20 @@
+package api
+
+import (
+	"net/http"
+)
+
+// handlePublicStatus expone la pagina de estado publica en JSON.
+func (s *Server) handlePublicStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	page, err := s.public.Page(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "no se pudo armar la pagina de estado")
+		return
+	}
+	w.Header().Set("Cache-Control", "public, max-age=60")
+	writeJSON(w, http.StatusOK, page)
+}
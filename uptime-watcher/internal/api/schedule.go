@@ -0,0 +1,42 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/service"
+)
+
+const maxPreviewRuns = 50
+
+type schedulePreview struct {
+	Schedule string      `json:"schedule"`
+	Timezone string      `json:"timezone,omitempty"`
+	Next     []time.Time `json:"next"`
+}
+
+// handleSchedulePreview atiende GET /api/schedule/preview?schedule=&timezone=&n=
+// con las proximas ejecuciones de un cron, para validarlo antes de guardarlo.
+func (s *Server) handleSchedulePreview(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	q := r.URL.Query()
+	n := service.PreviewRuns
+	if raw := q.Get("n"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < 1 || v > maxPreviewRuns {
+			writeError(w, http.StatusBadRequest, "n debe estar entre 1 y "+strconv.Itoa(maxPreviewRuns))
+			return
+		}
+		n = v
+	}
+	runs, err := service.NextRuns(q.Get("schedule"), q.Get("timezone"), n)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, schedulePreview{Schedule: q.Get("schedule"), Timezone: q.Get("timezone"), Next: runs})
+}
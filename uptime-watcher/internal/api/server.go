@@ -3,26 +3,59 @@ package api
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
+	"proyecto-leng-paradigmas/ejemplo/internal/alert"
+	"proyecto-leng-paradigmas/ejemplo/internal/auth"
+	"proyecto-leng-paradigmas/ejemplo/internal/check"
 	"proyecto-leng-paradigmas/ejemplo/internal/db"
+	"proyecto-leng-paradigmas/ejemplo/internal/incident"
+	"proyecto-leng-paradigmas/ejemplo/internal/maintenance"
+	"proyecto-leng-paradigmas/ejemplo/internal/metrics"
 	"proyecto-leng-paradigmas/ejemplo/internal/model"
 	"proyecto-leng-paradigmas/ejemplo/internal/service"
+	"proyecto-leng-paradigmas/ejemplo/internal/statuspage"
+	"proyecto-leng-paradigmas/ejemplo/internal/store"
 )
 
+// Services agrupa las dependencias expuestas por la API.
+type Services struct {
+	Targets     *service.TargetService
+	Incidents   *incident.Tracker
+	Alerts      *alert.Engine
+	Metrics     *metrics.Collector
+	Maintenance *maintenance.Schedule
+	Public      *statuspage.Builder
+	Auth        *auth.Manager
+}
+
 // Server expone endpoints HTTP para consultar y administrar el monitor.
 type Server struct {
-	svc *service.TargetService
-	mux *http.ServeMux
+	svc         *service.TargetService
+	incidents   *incident.Tracker
+	alerts      *alert.Engine
+	metrics     *metrics.Collector
+	maintenance *maintenance.Schedule
+	public      *statuspage.Builder
+	auth        *auth.Manager
+	mux         *http.ServeMux
 }
 
 // New crea un servidor API y registra los handlers necesarios.
-func New(service *service.TargetService) *Server {
+func New(services Services) *Server {
 	s := &Server{
-		svc: service,
-		mux: http.NewServeMux(),
+		svc:         services.Targets,
+		incidents:   services.Incidents,
+		alerts:      services.Alerts,
+		metrics:     services.Metrics,
+		maintenance: services.Maintenance,
+		public:      services.Public,
+		auth:        services.Auth,
+		mux:         http.NewServeMux(),
 	}
 	s.routes()
 	return s
@@ -39,13 +72,27 @@ func (s *Server) routes() {
 	s.mux.HandleFunc("/api/status", s.handleStatus)
 	s.mux.HandleFunc("/api/history", s.handleHistory)
 	s.mux.HandleFunc("/api/refresh", s.handleRefresh)
+	s.mux.HandleFunc("/api/kinds", s.handleKinds)
+	s.mux.HandleFunc("/api/schedule/preview", s.handleSchedulePreview)
+	s.mux.HandleFunc("/api/incidents", s.handleIncidents)
+	s.mux.HandleFunc("/api/events", s.handleEvents)
+	s.mux.HandleFunc("/api/alerts/rules", s.handleAlertRules)
+	s.mux.HandleFunc("/api/alerts/rules/", s.handleAlertRules)
+	s.mux.HandleFunc("/api/alerts/channels", s.handleAlertChannels)
+	s.mux.HandleFunc("/api/alerts/channels/", s.handleAlertChannels)
+	s.mux.HandleFunc("/api/maintenance", s.handleMaintenance)
+	s.mux.HandleFunc("/api/maintenance/", s.handleMaintenance)
+	s.mux.HandleFunc("/api/tokens", s.handleTokens)
+	s.mux.HandleFunc("/api/tokens/", s.handleTokens)
+	s.mux.HandleFunc("/api/public/status", s.handlePublicStatus)
 	s.mux.HandleFunc("/healthz", s.handleHealth)
+	s.mux.HandleFunc("/metrics", s.handleMetrics)
 }
 
 func (s *Server) handleTargets(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodGet:
-		writeJSON(w, http.StatusOK, s.svc.ListTargets())
+		writeJSON(w, http.StatusOK, s.svc.ListTargets(targetFilter(r)))
 	case http.MethodPost:
 		s.createTarget(w, r)
 	default:
@@ -60,6 +107,10 @@ func (s *Server) handleTargetByID(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "missing id", http.StatusBadRequest)
 		return
 	}
+	if base, action, ok := strings.Cut(id, "/"); ok {
+		s.targetAction(w, r, base, action)
+		return
+	}
 
 	switch r.Method {
 	case http.MethodPut, http.MethodPatch:
@@ -114,6 +165,32 @@ func (s *Server) updateTarget(w http.ResponseWriter, r *http.Request, id string)
 	writeJSON(w, http.StatusOK, res)
 }
 
+// targetAction atiende POST /api/targets/{id}/pause y /resume.
+func (s *Server) targetAction(w http.ResponseWriter, r *http.Request, id, action string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var (
+		target model.Target
+		err    error
+	)
+	switch action {
+	case "pause":
+		target, err = s.svc.PauseTarget(r.Context(), id)
+	case "resume":
+		target, err = s.svc.ResumeTarget(r.Context(), id)
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		writeError(w, errorStatus(err), err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, target)
+}
+
 func (s *Server) deleteTarget(w http.ResponseWriter, r *http.Request, id string) {
 	err := s.svc.DeleteTarget(r.Context(), id)
 	if err != nil {
@@ -132,7 +209,7 @@ func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
-	writeJSON(w, http.StatusOK, s.svc.Status())
+	writeJSON(w, http.StatusOK, s.svc.Status(targetFilter(r)))
 }
 
 func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
@@ -141,24 +218,80 @@ func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	id := r.URL.Query().Get("id")
-	if id == "" {
+	filter := targetFilter(r)
+	if id == "" && filter.Empty() {
 		http.Error(w, "missing id", http.StatusBadRequest)
 		return
 	}
-	var limit int
-	if raw := r.URL.Query().Get("limit"); raw != "" {
-		if v, err := strconv.Atoi(raw); err == nil {
-			limit = v
-		}
+	limit, err := parseLimit(r.URL.Query().Get("limit"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "limit invalido: "+err.Error())
+		return
 	}
-	results, err := s.svc.History(id, limit)
+	from, err := parseTime(r.URL.Query().Get("from"))
 	if err != nil {
-		writeError(w, http.StatusNotFound, err.Error())
+		writeError(w, http.StatusBadRequest, "from invalido: "+err.Error())
 		return
 	}
+	to, err := parseTime(r.URL.Query().Get("to"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "to invalido: "+err.Error())
+		return
+	}
+	var results []model.CheckResult
+	if id != "" {
+		results, err = s.svc.History(r.Context(), id, from, to, limit)
+	} else {
+		results, err = s.svc.HistoryFor(r.Context(), filter, from, to, limit)
+	}
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, store.ErrTargetNotFound) {
+			status = http.StatusNotFound
+		}
+		writeError(w, status, err.Error())
+		return
+	}
+	if results == nil {
+		results = []model.CheckResult{}
+	}
 	writeJSON(w, http.StatusOK, results)
 }
 
+func (s *Server) handleIncidents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	query := r.URL.Query()
+	filter := db.IncidentFilter{
+		TargetID: query.Get("id"),
+		OnlyOpen: query.Get("open") == "true",
+	}
+	var err error
+	if filter.Limit, err = parseLimit(query.Get("limit")); err != nil {
+		writeError(w, http.StatusBadRequest, "limit invalido: "+err.Error())
+		return
+	}
+	if filter.From, err = parseTime(query.Get("from")); err != nil {
+		writeError(w, http.StatusBadRequest, "from invalido: "+err.Error())
+		return
+	}
+	if filter.To, err = parseTime(query.Get("to")); err != nil {
+		writeError(w, http.StatusBadRequest, "to invalido: "+err.Error())
+		return
+	}
+	incidents, err := s.incidents.List(r.Context(), filter)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if incidents == nil {
+		incidents = []model.Incident{}
+	}
+	writeJSON(w, http.StatusOK, incidents)
+}
+
 func (s *Server) handleRefresh(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
@@ -176,6 +309,23 @@ func (s *Server) handleRefresh(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusAccepted, map[string]string{"status": "triggered"})
 }
 
+func (s *Server) handleKinds(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeJSON(w, http.StatusOK, check.Specs())
+}
+
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", metrics.ContentType)
+	_, _ = s.metrics.WriteTo(w)
+}
+
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
@@ -193,6 +343,17 @@ type targetRequest struct {
 	Port      int    `json:"port"`
 	Frequency string `json:"frequency"`
 	Timeout   string `json:"timeout"`
+	Schedule  string `json:"schedule"`
+	Timezone  string `json:"timezone"`
+
+	Retries       int    `json:"retries"`
+	RetryInterval string `json:"retry_interval"`
+
+	Group  string   `json:"group"`
+	Tags   []string `json:"tags"`
+	Public bool     `json:"public"`
+
+	Options map[string]string `json:"options"`
 }
 
 func requestToTarget(req targetRequest, pathID string) (model.Target, error) {
@@ -203,10 +364,19 @@ func requestToTarget(req targetRequest, pathID string) (model.Target, error) {
 	if id == "" && pathID != "" {
 		return model.Target{}, errors.New("id requerido")
 	}
-	freq, timeout, err := service.ParseDurations(req.Frequency, req.Timeout)
+	freqStr := req.Frequency
+	if freqStr == "" && strings.TrimSpace(req.Schedule) != "" {
+		// con cron la frecuencia no aplica
+		freqStr = "0s"
+	}
+	freq, timeout, err := service.ParseDurations(freqStr, req.Timeout)
 	if err != nil {
 		return model.Target{}, err
 	}
+	retryInterval, err := parseOptionalDuration(req.RetryInterval)
+	if err != nil {
+		return model.Target{}, fmt.Errorf("retry_interval invalido: %w", err)
+	}
 	target := model.Target{
 		ID:        id,
 		Name:      strings.TrimSpace(req.Name),
@@ -216,10 +386,51 @@ func requestToTarget(req targetRequest, pathID string) (model.Target, error) {
 		Port:      req.Port,
 		Frequency: freq,
 		Timeout:   timeout,
+		Schedule:  req.Schedule,
+		Timezone:  req.Timezone,
+		Options:   req.Options,
+
+		Retries:       req.Retries,
+		RetryInterval: retryInterval,
+		Group:         req.Group,
+		Tags:          req.Tags,
+		Public:        req.Public,
 	}
 	return target, nil
 }
 
+// targetFilter lee los parametros group y tag (repetible o separado por comas).
+func targetFilter(r *http.Request) model.TargetFilter {
+	q := r.URL.Query()
+	return model.TargetFilter{
+		Group: strings.TrimSpace(q.Get("group")),
+		Tags:  service.ParseTags(strings.Join(q["tag"], ",")),
+	}
+}
+
+// parseTime acepta fechas RFC3339; un string vacio equivale a sin limite.
+func parseTime(raw string) (time.Time, error) {
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, raw)
+}
+
+// parseLimit acepta un entero no negativo; vacio o 0 equivalen a sin limite.
+func parseLimit(raw string) (int, error) {
+	if raw == "" {
+		return 0, nil
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, err
+	}
+	if v < 0 {
+		return 0, errors.New("debe ser mayor o igual a 0")
+	}
+	return v, nil
+}
+
 func writeJSON(w http.ResponseWriter, code int, payload any) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(code)
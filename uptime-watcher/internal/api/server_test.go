@@ -0,0 +1,96 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/incident"
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+)
+
+func TestQueryLimit(t *testing.T) {
+	a := newTestAPI(t)
+	ctx := context.Background()
+	target, err := a.svc.CreateTarget(ctx, model.Target{Name: "web", Kind: model.TargetHTTP, URL: "https://example.com", Frequency: time.Minute, Timeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range 3 {
+		if err := a.store.Record(ctx, model.CheckResult{TargetID: target.ID, CheckedAt: start.Add(time.Duration(i) * time.Minute), Success: true}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		limit string
+		code  int
+		count int
+	}{
+		{"", http.StatusOK, 3},
+		{"0", http.StatusOK, 3},
+		{"2", http.StatusOK, 2},
+		{"-1", http.StatusBadRequest, 0},
+		{"diez", http.StatusBadRequest, 0},
+		{"1.5", http.StatusBadRequest, 0},
+	}
+	for _, tt := range tests {
+		path := "/api/history?id=" + target.ID + "&limit=" + tt.limit
+		rec := a.do(t, http.MethodGet, path, nil)
+		if tt.code != http.StatusOK {
+			decode(t, rec, tt.code, nil)
+			continue
+		}
+		var results []model.CheckResult
+		decode(t, rec, http.StatusOK, &results)
+		if len(results) != tt.count {
+			t.Errorf("%s: %d resultados, se esperaban %d", path, len(results), tt.count)
+		}
+	}
+	// los incidentes validan limit igual que el historial
+	for _, tt := range tests {
+		decode(t, a.do(t, http.MethodGet, "/api/incidents?limit="+tt.limit, nil), tt.code, nil)
+	}
+}
+
+func TestPauseResolvesOpenIncident(t *testing.T) {
+	a := newTestAPI(t)
+	ctx := context.Background()
+	target, err := a.svc.CreateTarget(ctx, model.Target{Name: "web", Kind: model.TargetHTTP, URL: "https://example.com", Frequency: time.Minute, Timeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now().Add(-time.Hour)
+	for i := range incident.DefaultThreshold {
+		a.incidents.Observe(ctx, target, model.CheckResult{TargetID: target.ID, CheckedAt: start.Add(time.Duration(i) * time.Minute), Message: "caido"})
+	}
+	var open []model.Incident
+	decode(t, a.do(t, http.MethodGet, "/api/incidents?open=true", nil), http.StatusOK, &open)
+	if len(open) != 1 {
+		t.Fatalf("incidentes abiertos = %+v, se esperaba uno", open)
+	}
+
+	var paused model.Target
+	decode(t, a.do(t, http.MethodPost, "/api/targets/"+target.ID+"/pause", nil), http.StatusOK, &paused)
+	if !paused.Paused {
+		t.Fatal("el target no quedo pausado")
+	}
+	decode(t, a.do(t, http.MethodGet, "/api/incidents?open=true", nil), http.StatusOK, &open)
+	if len(open) != 0 {
+		t.Fatalf("incidentes abiertos tras pausar = %+v, se esperaba ninguno", open)
+	}
+	var all []model.Incident
+	decode(t, a.do(t, http.MethodGet, "/api/incidents?id="+target.ID, nil), http.StatusOK, &all)
+	if len(all) != 1 || all[0].ResolvedAt == nil {
+		t.Fatalf("incidentes = %+v, se esperaba el mismo incidente resuelto", all)
+	}
+
+	// reanudar no reabre nada
+	decode(t, a.do(t, http.MethodPost, "/api/targets/"+target.ID+"/resume", nil), http.StatusOK, nil)
+	decode(t, a.do(t, http.MethodGet, "/api/incidents?open=true", nil), http.StatusOK, &open)
+	if len(open) != 0 {
+		t.Fatalf("incidentes abiertos tras reanudar = %+v", open)
+	}
+}
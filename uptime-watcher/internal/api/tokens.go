@@ -0,0 +1,64 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/auth"
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+)
+
+type tokenRequest struct {
+	Name  string `json:"name"`
+	Scope string `json:"scope"`
+}
+
+// createdToken incluye el valor secreto, que solo se muestra al crearlo.
+type createdToken struct {
+	model.APIToken
+	Token string `json:"token"`
+}
+
+// handleTokens administra los tokens de API; requiere scope admin incluso
+// para listarlos.
+func (s *Server) handleTokens(w http.ResponseWriter, r *http.Request) {
+	if p, ok := auth.FromContext(r.Context()); ok && p.Scope != model.ScopeAdmin {
+		writeError(w, http.StatusForbidden, "se requiere un token admin")
+		return
+	}
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/tokens"), "/")
+	switch {
+	case id == "" && r.Method == http.MethodGet:
+		tokens, err := s.auth.ListTokens(r.Context())
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if tokens == nil {
+			tokens = []model.APIToken{}
+		}
+		writeJSON(w, http.StatusOK, tokens)
+	case id == "" && r.Method == http.MethodPost:
+		var req tokenRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "json invalido: "+err.Error())
+			return
+		}
+		scope := model.TokenScope(strings.ToLower(strings.TrimSpace(req.Scope)))
+		secret, token, err := s.auth.CreateToken(r.Context(), req.Name, scope)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusCreated, createdToken{APIToken: token, Token: secret})
+	case id != "" && r.Method == http.MethodDelete:
+		if err := s.auth.RevokeToken(r.Context(), id); err != nil {
+			writeError(w, errorStatus(err), err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
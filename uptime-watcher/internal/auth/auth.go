@@ -0,0 +1,306 @@
+// Package auth gestiona usuarios, sesiones del dashboard y tokens de API.
+// Las passwords se guardan con PBKDF2-SHA256; sesiones y tokens son valores
+// aleatorios de los que solo se persiste el hash SHA-256.
+package auth
+
+import (
+	"context"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/clock"
+	"proyecto-leng-paradigmas/ejemplo/internal/db"
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+)
+
+const (
+	// DefaultSessionTTL es la duracion de una sesion del dashboard.
+	DefaultSessionTTL = 7 * 24 * time.Hour
+
+	// pbkdf2Iterations sigue la recomendacion de OWASP para PBKDF2-SHA256.
+	pbkdf2Iterations = 600_000
+	saltLength       = 16
+	keyLength        = 32
+
+	minPasswordLength = 8
+	maxUsernameLength = 64
+
+	// tokenPrefix identifica los tokens de API en logs y gestores de secretos.
+	tokenPrefix = "uw_"
+)
+
+// ErrInvalidCredentials se retorna cuando usuario, password, sesion o token
+// no son validos. No distingue la causa para no filtrar que usuarios existen.
+var ErrInvalidCredentials = errors.New("credenciales invalidas")
+
+// Repository es la persistencia de usuarios, sesiones y tokens.
+type Repository interface {
+	CreateUser(ctx context.Context, user model.User, passwordHash string) error
+	UserByName(ctx context.Context, username string) (model.User, string, error)
+	ListUsers(ctx context.Context) ([]model.User, error)
+	SetPassword(ctx context.Context, username, passwordHash string) error
+	DeleteUser(ctx context.Context, username string) error
+	CountUsers(ctx context.Context) (int, error)
+	CreateSession(ctx context.Context, tokenHash, userID string, expiresAt time.Time) error
+	SessionUser(ctx context.Context, tokenHash string, now time.Time) (model.User, error)
+	DeleteSession(ctx context.Context, tokenHash string) error
+	DeleteExpiredSessions(ctx context.Context, now time.Time) error
+	CreateToken(ctx context.Context, token model.APIToken, tokenHash string) error
+	TokenByHash(ctx context.Context, tokenHash string) (model.APIToken, error)
+	ListTokens(ctx context.Context) ([]model.APIToken, error)
+	DeleteToken(ctx context.Context, id string) error
+}
+
+// Manager autentica usuarios y tokens.
+type Manager struct {
+	repo       Repository
+	sessionTTL time.Duration
+	clock      clock.Clock
+
+	// dummyHash se usa con usuarios inexistentes para que el login tarde lo
+	// mismo que con una password incorrecta.
+	dummyOnce sync.Once
+	dummyHash string
+}
+
+// NewManager crea un Manager con la duracion de sesion por defecto.
+func NewManager(repo Repository) *Manager {
+	return &Manager{repo: repo, sessionTTL: DefaultSessionTTL, clock: clock.Real}
+}
+
+// SetClock reemplaza el reloj con el que vencen las sesiones. Debe llamarse
+// antes de usar el Manager.
+func (m *Manager) SetClock(c clock.Clock) {
+	m.clock = clock.Or(c)
+}
+
+// HashPassword deriva el hash de una password con una sal aleatoria. El
+// resultado tiene la forma pbkdf2-sha256$<iteraciones>$<sal>$<hash>.
+func HashPassword(password string) (string, error) {
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("no se pudo generar sal: %w", err)
+	}
+	key, err := pbkdf2.Key(sha256.New, password, salt, pbkdf2Iterations, keyLength)
+	if err != nil {
+		return "", fmt.Errorf("no se pudo derivar password: %w", err)
+	}
+	enc := base64.RawStdEncoding
+	return fmt.Sprintf("pbkdf2-sha256$%d$%s$%s", pbkdf2Iterations, enc.EncodeToString(salt), enc.EncodeToString(key)), nil
+}
+
+// CheckPassword compara una password con un hash de HashPassword en tiempo
+// constante.
+func CheckPassword(encoded, password string) bool {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 4 || parts[0] != "pbkdf2-sha256" {
+		return false
+	}
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil || iterations <= 0 {
+		return false
+	}
+	enc := base64.RawStdEncoding
+	salt, err := enc.DecodeString(parts[2])
+	if err != nil {
+		return false
+	}
+	want, err := enc.DecodeString(parts[3])
+	if err != nil {
+		return false
+	}
+	got, err := pbkdf2.Key(sha256.New, password, salt, iterations, len(want))
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare(got, want) == 1
+}
+
+// CreateUser valida y crea un usuario.
+func (m *Manager) CreateUser(ctx context.Context, username, password string) (model.User, error) {
+	username = strings.TrimSpace(username)
+	if err := validateUsername(username); err != nil {
+		return model.User{}, err
+	}
+	if err := validatePassword(password); err != nil {
+		return model.User{}, err
+	}
+	hash, err := HashPassword(password)
+	if err != nil {
+		return model.User{}, err
+	}
+	user := model.User{ID: uuid.NewString(), Username: username, CreatedAt: m.clock.Now().UTC()}
+	if err := m.repo.CreateUser(ctx, user, hash); err != nil {
+		return model.User{}, err
+	}
+	return user, nil
+}
+
+// SetPassword cambia la password de un usuario y cierra sus sesiones.
+func (m *Manager) SetPassword(ctx context.Context, username, password string) error {
+	if err := validatePassword(password); err != nil {
+		return err
+	}
+	hash, err := HashPassword(password)
+	if err != nil {
+		return err
+	}
+	return m.repo.SetPassword(ctx, strings.TrimSpace(username), hash)
+}
+
+// DeleteUser elimina un usuario.
+func (m *Manager) DeleteUser(ctx context.Context, username string) error {
+	return m.repo.DeleteUser(ctx, strings.TrimSpace(username))
+}
+
+// ListUsers devuelve los usuarios registrados.
+func (m *Manager) ListUsers(ctx context.Context) ([]model.User, error) {
+	return m.repo.ListUsers(ctx)
+}
+
+// HasUsers indica si existe al menos un usuario.
+func (m *Manager) HasUsers(ctx context.Context) (bool, error) {
+	n, err := m.repo.CountUsers(ctx)
+	return n > 0, err
+}
+
+// Login verifica las credenciales y abre una sesion. Devuelve el valor de la
+// cookie y su vencimiento.
+func (m *Manager) Login(ctx context.Context, username, password string) (string, time.Time, error) {
+	user, hash, err := m.repo.UserByName(ctx, strings.TrimSpace(username))
+	if errors.Is(err, db.ErrNotFound) {
+		m.dummyOnce.Do(func() { m.dummyHash, _ = HashPassword("password-inexistente") })
+		CheckPassword(m.dummyHash, password)
+		return "", time.Time{}, ErrInvalidCredentials
+	}
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if !CheckPassword(hash, password) {
+		return "", time.Time{}, ErrInvalidCredentials
+	}
+
+	now := m.clock.Now()
+	if err := m.repo.DeleteExpiredSessions(ctx, now); err != nil {
+		return "", time.Time{}, err
+	}
+	session, err := randomToken("")
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	expires := now.Add(m.sessionTTL)
+	if err := m.repo.CreateSession(ctx, hashToken(session), user.ID, expires); err != nil {
+		return "", time.Time{}, err
+	}
+	return session, expires, nil
+}
+
+// SessionUser devuelve el usuario de una sesion vigente.
+func (m *Manager) SessionUser(ctx context.Context, session string) (model.User, error) {
+	if session == "" {
+		return model.User{}, ErrInvalidCredentials
+	}
+	user, err := m.repo.SessionUser(ctx, hashToken(session), m.clock.Now())
+	if errors.Is(err, db.ErrNotFound) {
+		return model.User{}, ErrInvalidCredentials
+	}
+	return user, err
+}
+
+// Logout cierra una sesion.
+func (m *Manager) Logout(ctx context.Context, session string) error {
+	if session == "" {
+		return nil
+	}
+	return m.repo.DeleteSession(ctx, hashToken(session))
+}
+
+// CreateToken genera un token de API. El valor secreto se devuelve solo aqui.
+func (m *Manager) CreateToken(ctx context.Context, name string, scope model.TokenScope) (string, model.APIToken, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", model.APIToken{}, errors.New("nombre de token requerido")
+	}
+	if scope != model.ScopeRead && scope != model.ScopeAdmin {
+		return "", model.APIToken{}, fmt.Errorf("scope invalido %q (usar read o admin)", scope)
+	}
+	secret, err := randomToken(tokenPrefix)
+	if err != nil {
+		return "", model.APIToken{}, err
+	}
+	token := model.APIToken{ID: uuid.NewString(), Name: name, Scope: scope, CreatedAt: m.clock.Now().UTC()}
+	if err := m.repo.CreateToken(ctx, token, hashToken(secret)); err != nil {
+		return "", model.APIToken{}, err
+	}
+	return secret, token, nil
+}
+
+// VerifyToken devuelve el token correspondiente a un valor secreto.
+func (m *Manager) VerifyToken(ctx context.Context, secret string) (model.APIToken, error) {
+	if !strings.HasPrefix(secret, tokenPrefix) {
+		return model.APIToken{}, ErrInvalidCredentials
+	}
+	token, err := m.repo.TokenByHash(ctx, hashToken(secret))
+	if errors.Is(err, db.ErrNotFound) {
+		return model.APIToken{}, ErrInvalidCredentials
+	}
+	return token, err
+}
+
+// ListTokens devuelve los tokens sin su valor secreto.
+func (m *Manager) ListTokens(ctx context.Context) ([]model.APIToken, error) {
+	return m.repo.ListTokens(ctx)
+}
+
+// RevokeToken elimina un token.
+func (m *Manager) RevokeToken(ctx context.Context, id string) error {
+	return m.repo.DeleteToken(ctx, id)
+}
+
+func validateUsername(username string) error {
+	if username == "" {
+		return errors.New("nombre de usuario requerido")
+	}
+	if len(username) > maxUsernameLength {
+		return fmt.Errorf("nombre de usuario demasiado largo (max %d)", maxUsernameLength)
+	}
+	if strings.ContainsAny(username, " \t\r\n") {
+		return errors.New("el nombre de usuario no puede contener espacios")
+	}
+	return nil
+}
+
+func validatePassword(password string) error {
+	if len(password) < minPasswordLength {
+		return fmt.Errorf("la password debe tener al menos %d caracteres", minPasswordLength)
+	}
+	return nil
+}
+
+// randomToken genera 32 bytes aleatorios codificados en base64 URL.
+func randomToken(prefix string) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("no se pudo generar token: %w", err)
+	}
+	return prefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken es el hash con el que se guardan sesiones y tokens. Al ser
+// valores aleatorios de 256 bits no necesitan una derivacion lenta.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
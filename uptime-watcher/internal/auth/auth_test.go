@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/clock"
+	"proyecto-leng-paradigmas/ejemplo/internal/db"
+)
+
+var epoch = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// newTestManager devuelve un Manager sobre una base temporal, con un reloj
+// manual y el usuario "ana" con password "password-ana".
+func newTestManager(t *testing.T) (*Manager, *clock.Fake) {
+	t.Helper()
+	sqlDB, err := db.OpenSQLite(filepath.Join(t.TempDir(), "auth.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	if _, err := db.Migrate(context.Background(), sqlDB); err != nil {
+		t.Fatal(err)
+	}
+	fake := clock.NewFake(epoch)
+	m := NewManager(db.NewAuthRepository(sqlDB))
+	m.SetClock(fake)
+	if _, err := m.CreateUser(context.Background(), "ana", "password-ana"); err != nil {
+		t.Fatal(err)
+	}
+	return m, fake
+}
+
+func TestHashPassword(t *testing.T) {
+	hash, err := HashPassword("correcta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !CheckPassword(hash, "correcta") {
+		t.Fatal("CheckPassword rechazo la password correcta")
+	}
+	if CheckPassword(hash, "incorrecta") || CheckPassword(hash, "") {
+		t.Fatal("CheckPassword acepto una password incorrecta")
+	}
+	other, err := HashPassword("correcta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == hash {
+		t.Fatal("dos hashes de la misma password son iguales: falta la sal")
+	}
+
+	parts := strings.Split(hash, "$")
+	malformed := map[string]string{
+		"vacio":              "",
+		"sin separadores":    "correcta",
+		"faltan partes":      strings.Join(parts[:3], "$"),
+		"sobran partes":      hash + "$x",
+		"otro algoritmo":     strings.Join(append([]string{"bcrypt"}, parts[1:]...), "$"),
+		"iteraciones texto":  strings.Join([]string{parts[0], "mil", parts[2], parts[3]}, "$"),
+		"iteraciones cero":   strings.Join([]string{parts[0], "0", parts[2], parts[3]}, "$"),
+		"sal invalida":       strings.Join([]string{parts[0], parts[1], "%%%", parts[3]}, "$"),
+		"hash invalido":      strings.Join([]string{parts[0], parts[1], parts[2], "%%%"}, "$"),
+		"hash de otra clave": strings.Join([]string{parts[0], parts[1], parts[2], strings.Split(other, "$")[3]}, "$"),
+	}
+	for name, encoded := range malformed {
+		if CheckPassword(encoded, "correcta") {
+			t.Errorf("%s: CheckPassword acepto %q", name, encoded)
+		}
+	}
+}
+
+func TestLogin(t *testing.T) {
+	m, _ := newTestManager(t)
+	ctx := context.Background()
+	for _, tt := range []struct{ user, password string }{
+		{"ana", "incorrecta"},
+		{"nadie", "password-ana"},
+	} {
+		if _, _, err := m.Login(ctx, tt.user, tt.password); !errors.Is(err, ErrInvalidCredentials) {
+			t.Errorf("Login(%q, %q) = %v, se esperaba ErrInvalidCredentials", tt.user, tt.password, err)
+		}
+	}
+
+	session, _, err := m.Login(ctx, " ana ", "password-ana")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user, err := m.SessionUser(ctx, session); err != nil || user.Username != "ana" {
+		t.Fatalf("SessionUser = %v, %v", user, err)
+	}
+	if err := m.Logout(ctx, session); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.SessionUser(ctx, session); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("SessionUser tras Logout = %v, se esperaba ErrInvalidCredentials", err)
+	}
+}
+
+func TestSessionExpiry(t *testing.T) {
+	m, fake := newTestManager(t)
+	ctx := context.Background()
+	session, expires, err := m.Login(ctx, "ana", "password-ana")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := epoch.Add(DefaultSessionTTL); !expires.Equal(want) {
+		t.Fatalf("vencimiento %s, se esperaba %s", expires, want)
+	}
+
+	fake.Advance(DefaultSessionTTL - time.Second)
+	if _, err := m.SessionUser(ctx, session); err != nil {
+		t.Fatalf("SessionUser un segundo antes de vencer = %v", err)
+	}
+	fake.Advance(time.Second)
+	if _, err := m.SessionUser(ctx, session); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("SessionUser al vencer = %v, se esperaba ErrInvalidCredentials", err)
+	}
+	if _, err := m.SessionUser(ctx, ""); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("SessionUser sin sesion = %v, se esperaba ErrInvalidCredentials", err)
+	}
+}
@@ -0,0 +1,157 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+)
+
+// SessionCookie es el nombre de la cookie de sesion del dashboard.
+const SessionCookie = "monitor_session"
+
+type principalKey struct{}
+
+// Principal identifica a quien hizo una solicitud autenticada: un usuario con
+// sesion o un token de API.
+type Principal struct {
+	User  *model.User
+	Token *model.APIToken
+	Scope model.TokenScope
+}
+
+// Name devuelve el usuario o el nombre del token.
+func (p Principal) Name() string {
+	switch {
+	case p.User != nil:
+		return p.User.Username
+	case p.Token != nil:
+		return "token:" + p.Token.Name
+	default:
+		return ""
+	}
+}
+
+// FromContext devuelve el Principal guardado por los middlewares.
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
+
+func withPrincipal(r *http.Request, p Principal) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), principalKey{}, p))
+}
+
+// SetSessionCookie guarda la sesion en el navegador. La cookie es HttpOnly y
+// SameSite=Lax; se marca Secure si la conexion es TLS.
+func SetSessionCookie(w http.ResponseWriter, r *http.Request, session string, expires time.Time) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookie,
+		Value:    session,
+		Path:     "/",
+		Expires:  expires,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// ClearSessionCookie borra la cookie de sesion.
+func ClearSessionCookie(w http.ResponseWriter, r *http.Request) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookie,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// SessionValue devuelve el valor de la cookie de sesion, o "" si no esta.
+func SessionValue(r *http.Request) string {
+	c, err := r.Cookie(SessionCookie)
+	if err != nil {
+		return ""
+	}
+	return c.Value
+}
+
+// RequireUser protege el dashboard: sin una sesion valida redirige a /login
+// conservando la pagina pedida.
+func (m *Manager) RequireUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, err := m.SessionUser(r.Context(), SessionValue(r))
+		if errors.Is(err, ErrInvalidCredentials) {
+			target := "/login"
+			if r.Method == http.MethodGet && r.URL.RequestURI() != "/" {
+				target += "?" + url.Values{"next": {r.URL.RequestURI()}}.Encode()
+			}
+			http.Redirect(w, r, target, http.StatusSeeOther)
+			return
+		}
+		if err != nil {
+			http.Error(w, "error verificando sesion", http.StatusInternalServerError)
+			return
+		}
+		next.ServeHTTP(w, withPrincipal(r, Principal{User: &user, Scope: model.ScopeAdmin}))
+	})
+}
+
+// RequireAPI protege la API. Acepta "Authorization: Bearer <token>" con el
+// scope del token, o la cookie del dashboard con scope de solo lectura: asi el
+// navegador puede leer eventos y estado, y las escrituras requieren un token
+// admin. Ningun scope lee credenciales: las opciones secretas de los targets
+// y los headers de los canales de alerta se devuelven enmascarados.
+func (m *Manager) RequireAPI(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			principal Principal
+			err       error
+		)
+		if header := r.Header.Get("Authorization"); header != "" {
+			scheme, secret, _ := strings.Cut(header, " ")
+			if !strings.EqualFold(scheme, "Bearer") {
+				unauthorized(w, "esquema de autorizacion no soportado")
+				return
+			}
+			var token model.APIToken
+			token, err = m.VerifyToken(r.Context(), strings.TrimSpace(secret))
+			principal = Principal{Token: &token, Scope: token.Scope}
+		} else {
+			var user model.User
+			user, err = m.SessionUser(r.Context(), SessionValue(r))
+			principal = Principal{User: &user, Scope: model.ScopeRead}
+		}
+		if errors.Is(err, ErrInvalidCredentials) {
+			unauthorized(w, "token o sesion invalidos")
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "error verificando credenciales")
+			return
+		}
+		if !principal.Scope.Allows(r.Method) {
+			writeError(w, http.StatusForbidden, "el scope "+string(principal.Scope)+" no permite "+r.Method)
+			return
+		}
+		next.ServeHTTP(w, withPrincipal(r, principal))
+	})
+}
+
+func unauthorized(w http.ResponseWriter, msg string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="monitor"`)
+	writeError(w, http.StatusUnauthorized, msg)
+}
+
+func writeError(w http.ResponseWriter, code int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
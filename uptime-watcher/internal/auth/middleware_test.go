@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+)
+
+// whoami responde con el nombre del Principal que dejaron los middlewares.
+var whoami = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	p, _ := FromContext(r.Context())
+	w.Write([]byte(p.Name()))
+})
+
+func TestRequireAPI(t *testing.T) {
+	m, _ := newTestManager(t)
+	ctx := context.Background()
+	session, _, err := m.Login(ctx, "ana", "password-ana")
+	if err != nil {
+		t.Fatal(err)
+	}
+	read, _, err := m.CreateToken(ctx, "lector", model.ScopeRead)
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin, _, err := m.CreateToken(ctx, "ci", model.ScopeAdmin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		method        string
+		authorization string
+		cookie        string
+		want          int
+		principal     string
+	}{
+		{"anonimo", http.MethodGet, "", "", http.StatusUnauthorized, ""},
+		{"token desconocido", http.MethodGet, "Bearer uw_otro", "", http.StatusUnauthorized, ""},
+		{"token sin prefijo", http.MethodGet, "Bearer " + session, "", http.StatusUnauthorized, ""},
+		{"otro esquema", http.MethodGet, "Basic YW5hOng=", "", http.StatusUnauthorized, ""},
+		{"sesion vencida o falsa", http.MethodGet, "", "falsa", http.StatusUnauthorized, ""},
+		{"token read lee", http.MethodGet, "Bearer " + read, "", http.StatusOK, "token:lector"},
+		{"token read no escribe", http.MethodPost, "Bearer " + read, "", http.StatusForbidden, ""},
+		{"token read no borra", http.MethodDelete, "bearer " + read, "", http.StatusForbidden, ""},
+		{"token admin escribe", http.MethodPost, "Bearer " + admin, "", http.StatusOK, "token:ci"},
+		{"sesion lee", http.MethodGet, "", session, http.StatusOK, "ana"},
+		{"sesion no escribe", http.MethodPost, "", session, http.StatusForbidden, ""},
+		{"sesion no borra", http.MethodDelete, "", session, http.StatusForbidden, ""},
+		// con Authorization la cookie se ignora
+		{"token read con sesion", http.MethodPost, "Bearer " + read, session, http.StatusForbidden, ""},
+	}
+	handler := m.RequireAPI(whoami)
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, "/api/targets", nil)
+		if tt.authorization != "" {
+			req.Header.Set("Authorization", tt.authorization)
+		}
+		if tt.cookie != "" {
+			req.AddCookie(&http.Cookie{Name: SessionCookie, Value: tt.cookie})
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s: codigo %d, se esperaba %d", tt.name, rec.Code, tt.want)
+			continue
+		}
+		if tt.want == http.StatusOK && rec.Body.String() != tt.principal {
+			t.Errorf("%s: principal %q, se esperaba %q", tt.name, rec.Body.String(), tt.principal)
+		}
+		if tt.want == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("%s: 401 sin WWW-Authenticate", tt.name)
+		}
+	}
+}
+
+func TestRequireUser(t *testing.T) {
+	m, _ := newTestManager(t)
+	session, _, err := m.Login(context.Background(), "ana", "password-ana")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		method, target, cookie string
+		want                   int
+		location               string
+	}{
+		{http.MethodGet, "/", "", http.StatusSeeOther, "/login"},
+		{http.MethodGet, "/?group=web", "", http.StatusSeeOther, "/login?next=%2F%3Fgroup%3Dweb"},
+		{http.MethodGet, "/", "falsa", http.StatusSeeOther, "/login"},
+		// un POST no se puede repetir tras el login: no se conserva
+		{http.MethodPost, "/ui/targets/create", "", http.StatusSeeOther, "/login"},
+		{http.MethodGet, "/", session, http.StatusOK, ""},
+	}
+	handler := m.RequireUser(whoami)
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.target, nil)
+		if tt.cookie != "" {
+			req.AddCookie(&http.Cookie{Name: SessionCookie, Value: tt.cookie})
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.want || rec.Header().Get("Location") != tt.location {
+			t.Errorf("%s %s: codigo %d, Location %q; se esperaba %d, %q", tt.method, tt.target, rec.Code, rec.Header().Get("Location"), tt.want, tt.location)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: SessionCookie, Value: session})
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Body.String() != "ana" {
+		t.Fatalf("principal %q, se esperaba ana", rec.Body.String())
+	}
+}
@@ -0,0 +1,10 @@
+// Package all registra todos los tipos de chequeo disponibles. Para agregar un
+// tipo nuevo basta con crear su paquete e importarlo aqui.
+package all
+
+import (
+	_ "proyecto-leng-paradigmas/ejemplo/internal/check/dnscheck"
+	_ "proyecto-leng-paradigmas/ejemplo/internal/check/httpcheck"
+	_ "proyecto-leng-paradigmas/ejemplo/internal/check/tcpcheck"
+	_ "proyecto-leng-paradigmas/ejemplo/internal/check/tlscheck"
+)
@@ -3,100 +3,58 @@ package check
 import (
 	"context"
 	"fmt"
-	"net"
-	"net/http"
 	"time"
 
+	"proyecto-leng-paradigmas/ejemplo/internal/clock"
 	"proyecto-leng-paradigmas/ejemplo/internal/model"
 )
 
-// Runner ejecuta chequeos segun el tipo del target.
+// Checker implementa un tipo de chequeo. Cada tipo vive en su propio paquete y
+// se registra con Register, de modo que config, API, UI y validacion lo
+// reconocen sin cambios adicionales.
+type Checker interface {
+	// Spec describe el tipo y los campos que necesita.
+	Spec() Spec
+	// Validate revisa los campos propios del tipo.
+	Validate(target model.Target) error
+	// Check ejecuta el chequeo y retorna su resultado.
+	Check(ctx context.Context, target model.Target) model.CheckResult
+}
+
+// Runner ejecuta chequeos delegando en el Checker registrado para cada tipo.
+// Si Clock no es nil, CheckedAt de cada resultado se toma de ese reloj en
+// lugar del que usa el Checker.
 type Runner struct {
-	HTTPClient *http.Client
+	Registry *Registry
+	Clock    clock.Clock
 }
 
-// NewRunner crea un Runner con clientes por defecto.
+// NewRunner crea un Runner que usa el registro por defecto.
 func NewRunner() *Runner {
-	return &Runner{
-		HTTPClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
-	}
+	return &Runner{Registry: DefaultRegistry}
 }
 
 // Run ejecuta el chequeo apropiado y retorna un CheckResult.
 func (r *Runner) Run(ctx context.Context, target model.Target) model.CheckResult {
-	switch target.Kind {
-	case model.TargetHTTP:
-		return r.checkHTTP(ctx, target)
-	case model.TargetTCP:
-		return r.checkTCP(ctx, target)
-	default:
-		return model.CheckResult{
-			TargetID:  target.ID,
-			CheckedAt: time.Now(),
-			Success:   false,
-			Message:   fmt.Sprintf("tipo de target desconocido: %s", target.Kind),
-		}
-	}
-}
-
-func (r *Runner) checkHTTP(ctx context.Context, target model.Target) model.CheckResult {
-	start := time.Now()
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.URL, nil)
-	if err != nil {
-		return model.CheckResult{
-			TargetID:  target.ID,
-			CheckedAt: time.Now(),
-			Duration:  time.Since(start),
-			Success:   false,
-			Message:   fmt.Sprintf("no se pudo crear request: %v", err),
-		}
+	var result model.CheckResult
+	if checker, ok := r.Registry.Lookup(target.Kind); ok {
+		result = checker.Check(ctx, target)
+	} else {
+		result = Failure(target, time.Now(), fmt.Sprintf("tipo de target desconocido: %s", target.Kind))
 	}
-	resp, err := r.HTTPClient.Do(req)
-	if err != nil {
-		return model.CheckResult{
-			TargetID:  target.ID,
-			CheckedAt: time.Now(),
-			Duration:  time.Since(start),
-			Success:   false,
-			Message:   fmt.Sprintf("error HTTP: %v", err),
-		}
-	}
-	defer resp.Body.Close()
-
-	success := resp.StatusCode >= 200 && resp.StatusCode < 400
-	return model.CheckResult{
-		TargetID:   target.ID,
-		CheckedAt:  time.Now(),
-		Duration:   time.Since(start),
-		Success:    success,
-		Message:    resp.Status,
-		StatusCode: resp.StatusCode,
+	if r.Clock != nil {
+		result.CheckedAt = r.Clock.Now()
 	}
+	return result
 }
 
-func (r *Runner) checkTCP(ctx context.Context, target model.Target) model.CheckResult {
-	start := time.Now()
-	address := fmt.Sprintf("%s:%d", target.Host, target.Port)
-
-	var d net.Dialer
-	conn, err := d.DialContext(ctx, "tcp", address)
-	if err != nil {
-		return model.CheckResult{
-			TargetID:  target.ID,
-			CheckedAt: time.Now(),
-			Duration:  time.Since(start),
-			Success:   false,
-			Message:   fmt.Sprintf("conexion fallida: %v", err),
-		}
-	}
-	conn.Close()
+// Failure arma un resultado fallido midiendo la duracion desde start.
+func Failure(target model.Target, start time.Time, msg string) model.CheckResult {
 	return model.CheckResult{
 		TargetID:  target.ID,
 		CheckedAt: time.Now(),
 		Duration:  time.Since(start),
-		Success:   true,
-		Message:   "tcp ok",
+		Success:   false,
+		Message:   msg,
 	}
 }
@@ -0,0 +1,224 @@
+// Package dnscheck implementa chequeos de resolucion DNS.
+package dnscheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/check"
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+)
+
+// Kind identifica los targets DNS.
+const Kind model.TargetKind = "dns"
+
+// Opciones propias del tipo dns.
+const (
+	OptResolver   = "resolver"
+	OptRecordType = "record_type"
+	OptExpect     = "expect"
+)
+
+// Tipos de registro soportados.
+var recordTypes = []string{"A", "AAAA", "CNAME", "MX", "TXT", "SRV"}
+
+func init() {
+	check.Register(New())
+}
+
+// Checker resuelve Target.Host contra el resolver configurado y, opcionalmente,
+// verifica que la respuesta contenga los valores esperados.
+type Checker struct {
+	// Dial abre la conexion hacia el resolver. Permite reemplazar la red por
+	// un servidor en memoria.
+	Dial func(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+// New crea un Checker que usa la red real.
+func New() *Checker {
+	var d net.Dialer
+	return &Checker{Dial: d.DialContext}
+}
+
+// Spec implementa check.Checker.
+func (c *Checker) Spec() check.Spec {
+	return check.Spec{
+		Kind:  Kind,
+		Label: "DNS",
+		Fields: []check.Field{
+			{Name: check.FieldHost, Label: "Nombre", Placeholder: "example.com"},
+			{Name: OptRecordType, Label: "Registro", Type: check.InputSelect, Choices: recordTypes},
+			{Name: OptResolver, Label: "Resolver (opcional)", Placeholder: "1.1.1.1:53"},
+			{Name: OptExpect, Label: "Valores esperados", Placeholder: "93.184.215.14, otro"},
+		},
+	}
+}
+
+// Validate implementa check.Checker.
+func (c *Checker) Validate(target model.Target) error {
+	if target.Host == "" {
+		return errors.New("host requerido para targets dns")
+	}
+	if _, err := recordType(target); err != nil {
+		return err
+	}
+	if raw := target.Options[OptResolver]; raw != "" {
+		if _, err := resolverAddress(raw); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Check implementa check.Checker.
+func (c *Checker) Check(ctx context.Context, target model.Target) model.CheckResult {
+	start := time.Now()
+	rtype, err := recordType(target)
+	if err != nil {
+		return check.Failure(target, start, err.Error())
+	}
+	resolver, err := c.resolver(target.Options[OptResolver])
+	if err != nil {
+		return check.Failure(target, start, err.Error())
+	}
+
+	answers, err := lookup(ctx, resolver, rtype, target.Host)
+	if err != nil {
+		return check.Failure(target, start, fmt.Sprintf("error DNS: %v", err))
+	}
+	if len(answers) == 0 {
+		return check.Failure(target, start, fmt.Sprintf("sin registros %s para %s", rtype, target.Host))
+	}
+	if missing := missingValues(answers, splitExpect(target.Options[OptExpect])); len(missing) > 0 {
+		return check.Failure(target, start, fmt.Sprintf("respuesta %s sin %s (obtenido: %s)", rtype, strings.Join(missing, ", "), strings.Join(answers, ", ")))
+	}
+	return model.CheckResult{
+		TargetID:  target.ID,
+		CheckedAt: time.Now(),
+		Duration:  time.Since(start),
+		Success:   true,
+		Message:   fmt.Sprintf("%s %s", rtype, strings.Join(answers, ", ")),
+	}
+}
+
+func (c *Checker) resolver(raw string) (*net.Resolver, error) {
+	if raw == "" {
+		return net.DefaultResolver, nil
+	}
+	address, err := resolverAddress(raw)
+	if err != nil {
+		return nil, err
+	}
+	dial := c.Dial
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return dial(ctx, network, address)
+		},
+	}, nil
+}
+
+func lookup(ctx context.Context, r *net.Resolver, rtype, host string) ([]string, error) {
+	var answers []string
+	switch rtype {
+	case "A", "AAAA":
+		network := "ip4"
+		if rtype == "AAAA" {
+			network = "ip6"
+		}
+		ips, err := r.LookupIP(ctx, network, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, ip := range ips {
+			answers = append(answers, ip.String())
+		}
+	case "CNAME":
+		cname, err := r.LookupCNAME(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		answers = append(answers, normalize(cname))
+	case "MX":
+		mxs, err := r.LookupMX(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, mx := range mxs {
+			answers = append(answers, normalize(mx.Host))
+		}
+	case "TXT":
+		txts, err := r.LookupTXT(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		answers = append(answers, txts...)
+	case "SRV":
+		_, srvs, err := r.LookupSRV(ctx, "", "", host)
+		if err != nil {
+			return nil, err
+		}
+		for _, srv := range srvs {
+			answers = append(answers, net.JoinHostPort(normalize(srv.Target), strconv.Itoa(int(srv.Port))))
+		}
+	}
+	return answers, nil
+}
+
+func recordType(target model.Target) (string, error) {
+	rtype := strings.ToUpper(strings.TrimSpace(target.Options[OptRecordType]))
+	if rtype == "" {
+		return "A", nil
+	}
+	for _, known := range recordTypes {
+		if rtype == known {
+			return rtype, nil
+		}
+	}
+	return "", fmt.Errorf("tipo de registro no soportado: %s", rtype)
+}
+
+// resolverAddress agrega el puerto 53 cuando no se indica.
+func resolverAddress(raw string) (string, error) {
+	if _, _, err := net.SplitHostPort(raw); err == nil {
+		return raw, nil
+	}
+	if net.ParseIP(strings.Trim(raw, "[]")) == nil && strings.Contains(raw, ":") {
+		return "", fmt.Errorf("resolver invalido: %s", raw)
+	}
+	return net.JoinHostPort(strings.Trim(raw, "[]"), "53"), nil
+}
+
+func splitExpect(raw string) []string {
+	var out []string
+	for _, part := range strings.Split(raw, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// missingValues devuelve los valores esperados que no aparecen en la respuesta.
+func missingValues(answers, expected []string) []string {
+	got := make(map[string]bool, len(answers))
+	for _, a := range answers {
+		got[normalize(a)] = true
+	}
+	var missing []string
+	for _, e := range expected {
+		if !got[normalize(e)] {
+			missing = append(missing, e)
+		}
+	}
+	return missing
+}
+
+func normalize(value string) string {
+	return strings.ToLower(strings.TrimSuffix(value, "."))
+}
@@ -0,0 +1,274 @@
+package dnscheck
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+)
+
+// Tipos de registro en el formato de red.
+const (
+	typeA     = 1
+	typeCNAME = 5
+	typeMX    = 15
+	typeTXT   = 16
+	typeAAAA  = 28
+	typeSRV   = 33
+)
+
+// record es una respuesta del servidor de prueba.
+type record struct {
+	rtype uint16
+	data  []byte
+}
+
+// zone responde por nombre (sin punto final) y tipo; un nombre ausente da
+// NXDOMAIN.
+type zone map[string][]record
+
+// fakeServer atiende consultas DNS sobre conexiones en memoria con el
+// formato de TCP (largo de 2 bytes + mensaje).
+type fakeServer struct {
+	zone zone
+}
+
+func (s *fakeServer) dial(ctx context.Context, network, address string) (net.Conn, error) {
+	client, server := net.Pipe()
+	go s.serve(server)
+	return client, nil
+}
+
+func (s *fakeServer) serve(conn net.Conn) {
+	defer conn.Close()
+	for {
+		var size [2]byte
+		if _, err := io.ReadFull(conn, size[:]); err != nil {
+			return
+		}
+		query := make([]byte, binary.BigEndian.Uint16(size[:]))
+		if _, err := io.ReadFull(conn, query); err != nil {
+			return
+		}
+		resp, err := s.answer(query)
+		if err != nil {
+			return
+		}
+		out := binary.BigEndian.AppendUint16(nil, uint16(len(resp)))
+		if _, err := conn.Write(append(out, resp...)); err != nil {
+			return
+		}
+	}
+}
+
+// answer arma la respuesta a una consulta con una sola pregunta.
+func (s *fakeServer) answer(query []byte) ([]byte, error) {
+	if len(query) < 12 {
+		return nil, errors.New("consulta corta")
+	}
+	labels, end, err := readName(query, 12)
+	if err != nil || end+4 > len(query) {
+		return nil, errors.New("pregunta invalida")
+	}
+	question := query[12 : end+4]
+	qtype := binary.BigEndian.Uint16(query[end:])
+	name := strings.ToLower(strings.Join(labels, "."))
+
+	records, known := s.zone[name]
+	var answers []record
+	for _, r := range records {
+		// como un servidor real, el CNAME responde cualquier tipo
+		if r.rtype == qtype || r.rtype == typeCNAME {
+			answers = append(answers, r)
+		}
+	}
+
+	flags := uint16(0x8580) // respuesta, autoritativa, recursion pedida y disponible
+	if !known {
+		flags |= 3 // NXDOMAIN
+	}
+	msg := binary.BigEndian.AppendUint16(nil, binary.BigEndian.Uint16(query))
+	msg = binary.BigEndian.AppendUint16(msg, flags)
+	msg = binary.BigEndian.AppendUint16(msg, 1)
+	msg = binary.BigEndian.AppendUint16(msg, uint16(len(answers)))
+	msg = append(msg, 0, 0, 0, 0)
+	msg = append(msg, question...)
+	for _, r := range answers {
+		msg = append(msg, 0xc0, 12) // puntero al nombre de la pregunta
+		msg = binary.BigEndian.AppendUint16(msg, r.rtype)
+		msg = binary.BigEndian.AppendUint16(msg, 1)
+		msg = binary.BigEndian.AppendUint32(msg, 60)
+		msg = binary.BigEndian.AppendUint16(msg, uint16(len(r.data)))
+		msg = append(msg, r.data...)
+	}
+	return msg, nil
+}
+
+func readName(msg []byte, off int) ([]string, int, error) {
+	var labels []string
+	for off < len(msg) {
+		n := int(msg[off])
+		off++
+		if n == 0 {
+			return labels, off, nil
+		}
+		if off+n > len(msg) {
+			break
+		}
+		labels = append(labels, string(msg[off:off+n]))
+		off += n
+	}
+	return nil, 0, errors.New("nombre truncado")
+}
+
+func encodeName(name string) []byte {
+	var out []byte
+	for _, label := range strings.Split(strings.TrimSuffix(name, "."), ".") {
+		out = append(out, byte(len(label)))
+		out = append(out, label...)
+	}
+	return append(out, 0)
+}
+
+func aRecord(ip string) record {
+	return record{typeA, net.ParseIP(ip).To4()}
+}
+
+func aaaaRecord(ip string) record {
+	return record{typeAAAA, net.ParseIP(ip).To16()}
+}
+
+func cnameRecord(target string) record {
+	return record{typeCNAME, encodeName(target)}
+}
+
+func mxRecord(pref uint16, host string) record {
+	return record{typeMX, append(binary.BigEndian.AppendUint16(nil, pref), encodeName(host)...)}
+}
+
+func txtRecord(text string) record {
+	return record{typeTXT, append([]byte{byte(len(text))}, text...)}
+}
+
+func srvRecord(port uint16, target string) record {
+	data := binary.BigEndian.AppendUint16(nil, 10) // prioridad
+	data = binary.BigEndian.AppendUint16(data, 5)  // peso
+	data = binary.BigEndian.AppendUint16(data, port)
+	return record{typeSRV, append(data, encodeName(target)...)}
+}
+
+var testZone = zone{
+	"web.monitor.test":           {aRecord("192.0.2.10"), aRecord("192.0.2.11")},
+	"v6.monitor.test":            {aaaaRecord("2001:db8::1")},
+	"www.monitor.test":           {cnameRecord("web.monitor.test")},
+	"monitor.test":               {mxRecord(10, "mx1.monitor.test"), mxRecord(20, "mx2.monitor.test"), txtRecord("v=spf1 -all")},
+	"_sip._tcp.monitor.test":     {srvRecord(5060, "sip.monitor.test")},
+	"empty.monitor.test":         {},
+	"txt-multi.monitor.test":     {txtRecord("uno"), txtRecord("dos")},
+	"mx-single.monitor.test":     {mxRecord(0, "Mail.Monitor.Test.")},
+	"srv-wrongport.monitor.test": {srvRecord(5061, "sip.monitor.test")},
+}
+
+func dnsTarget(host, rtype, expect string) model.Target {
+	return model.Target{
+		ID:      "dns",
+		Kind:    Kind,
+		Host:    host,
+		Timeout: 2 * time.Second,
+		Options: map[string]string{
+			OptResolver:   "192.0.2.53",
+			OptRecordType: rtype,
+			OptExpect:     expect,
+		},
+	}
+}
+
+func TestCheck(t *testing.T) {
+	srv := &fakeServer{zone: testZone}
+	checker := &Checker{Dial: srv.dial}
+
+	tests := []struct {
+		name    string
+		target  model.Target
+		success bool
+		message string
+	}{
+		{"A coincide", dnsTarget("web.monitor.test", "A", "192.0.2.11"), true, "A 192.0.2.10, 192.0.2.11"},
+		{"A sin valor esperado", dnsTarget("web.monitor.test", "A", "192.0.2.99"), false, "respuesta A sin 192.0.2.99"},
+		{"A sin expect", dnsTarget("web.monitor.test", "", ""), true, "A 192.0.2.10"},
+		{"AAAA coincide", dnsTarget("v6.monitor.test", "AAAA", "2001:db8::1"), true, "AAAA 2001:db8::1"},
+		{"CNAME coincide", dnsTarget("www.monitor.test", "CNAME", "web.monitor.test."), true, "CNAME web.monitor.test"},
+		{"CNAME distinto", dnsTarget("www.monitor.test", "CNAME", "otro.monitor.test"), false, "respuesta CNAME sin otro.monitor.test"},
+		{"MX coincide", dnsTarget("monitor.test", "MX", "mx1.monitor.test, MX2.monitor.test"), true, "MX mx1.monitor.test, mx2.monitor.test"},
+		{"MX normaliza mayusculas", dnsTarget("mx-single.monitor.test", "MX", "mail.monitor.test"), true, "MX mail.monitor.test"},
+		{"MX faltante", dnsTarget("monitor.test", "MX", "mx3.monitor.test"), false, "respuesta MX sin mx3.monitor.test"},
+		{"TXT coincide", dnsTarget("monitor.test", "TXT", "v=spf1 -all"), true, "TXT v=spf1 -all"},
+		{"TXT varios", dnsTarget("txt-multi.monitor.test", "TXT", "dos"), true, "TXT uno, dos"},
+		{"TXT distinto", dnsTarget("monitor.test", "TXT", "v=spf1 ~all"), false, "respuesta TXT sin v=spf1 ~all"},
+		{"SRV coincide", dnsTarget("_sip._tcp.monitor.test", "SRV", "sip.monitor.test:5060"), true, "SRV sip.monitor.test:5060"},
+		{"SRV puerto distinto", dnsTarget("srv-wrongport.monitor.test", "SRV", "sip.monitor.test:5060"), false, "respuesta SRV sin sip.monitor.test:5060"},
+		{"sin registros", dnsTarget("empty.monitor.test", "A", ""), false, "error DNS"},
+		{"nombre inexistente", dnsTarget("nada.monitor.test", "A", ""), false, "error DNS"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checker.Validate(tt.target); err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), tt.target.Timeout)
+			defer cancel()
+			res := checker.Check(ctx, tt.target)
+			if res.Success != tt.success {
+				t.Fatalf("Success = %v, se esperaba %v (mensaje %q)", res.Success, tt.success, res.Message)
+			}
+			if !strings.Contains(res.Message, tt.message) {
+				t.Errorf("mensaje %q, se esperaba que contenga %q", res.Message, tt.message)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	checker := New()
+	tests := []struct {
+		name   string
+		target model.Target
+		ok     bool
+	}{
+		{"valido", dnsTarget("monitor.test", "MX", ""), true},
+		{"sin host", dnsTarget("", "A", ""), false},
+		{"tipo desconocido", dnsTarget("monitor.test", "PTR", ""), false},
+		{"resolver invalido", func() model.Target {
+			t := dnsTarget("monitor.test", "A", "")
+			t.Options[OptResolver] = "no:es:ip"
+			return t
+		}(), false},
+	}
+	for _, tt := range tests {
+		if err := checker.Validate(tt.target); (err == nil) != tt.ok {
+			t.Errorf("%s: Validate = %v", tt.name, err)
+		}
+	}
+}
+
+func TestResolverAddress(t *testing.T) {
+	tests := map[string]string{
+		"1.1.1.1":         "1.1.1.1:53",
+		"1.1.1.1:5353":    "1.1.1.1:5353",
+		"2001:db8::53":    "[2001:db8::53]:53",
+		"[2001:db8::53]":  "[2001:db8::53]:53",
+		"dns.example:853": "dns.example:853",
+	}
+	for raw, want := range tests {
+		got, err := resolverAddress(raw)
+		if err != nil || got != want {
+			t.Errorf("resolverAddress(%q) = %q, %v; se esperaba %q", raw, got, err, want)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package httpcheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Opciones de asercion sobre la respuesta.
+const (
+	OptExpectedStatus  = "expected_status"
+	OptBodyContains    = "body_contains"
+	OptBodyNotContains = "body_not_contains"
+	OptBodyRegex       = "body_regex"
+	OptJSONPath        = "json_path"
+	OptMaxResponseTime = "max_response_time"
+	OptRequiredHeaders = "required_headers"
+)
+
+// maxBodyBytes limita la lectura del body.
+const maxBodyBytes = 1 << 20
+
+type statusRange struct{ from, to int }
+
+type headerAssertion struct {
+	name  string
+	value string
+}
+
+// assertions agrupa las verificaciones configuradas sobre un target http.
+type assertions struct {
+	statuses        []statusRange
+	bodyContains    string
+	bodyNotContains string
+	bodyRegex       *regexp.Regexp
+	jsonPaths       []jsonAssertion
+	maxResponseTime time.Duration
+	headers         []headerAssertion
+}
+
+// parseAssertions interpreta las opciones del target. Es usada tanto en
+// Validate como en Check para que ambos acepten exactamente lo mismo.
+func parseAssertions(opts map[string]string) (assertions, error) {
+	var a assertions
+	var err error
+	if a.statuses, err = parseStatuses(opts[OptExpectedStatus]); err != nil {
+		return a, err
+	}
+	a.bodyContains = opts[OptBodyContains]
+	a.bodyNotContains = opts[OptBodyNotContains]
+	if raw := opts[OptBodyRegex]; raw != "" {
+		if a.bodyRegex, err = regexp.Compile(raw); err != nil {
+			return a, fmt.Errorf("body_regex invalida: %w", err)
+		}
+	}
+	for _, line := range lines(opts[OptJSONPath]) {
+		ja, err := parseJSONAssertion(line)
+		if err != nil {
+			return a, fmt.Errorf("json_path: %w", err)
+		}
+		a.jsonPaths = append(a.jsonPaths, ja)
+	}
+	if raw := opts[OptMaxResponseTime]; raw != "" {
+		if a.maxResponseTime, err = time.ParseDuration(raw); err != nil || a.maxResponseTime <= 0 {
+			return a, fmt.Errorf("max_response_time invalido: %s", raw)
+		}
+	}
+	for _, line := range lines(opts[OptRequiredHeaders]) {
+		name, value, _ := strings.Cut(line, ":")
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return a, fmt.Errorf("required_headers invalido: %q", line)
+		}
+		a.headers = append(a.headers, headerAssertion{name: name, value: strings.TrimSpace(value)})
+	}
+	return a, nil
+}
+
+// parseStatuses acepta listas como "200,204" o rangos como "200-299".
+func parseStatuses(raw string) ([]statusRange, error) {
+	if strings.TrimSpace(raw) == "" {
+		return []statusRange{{200, 399}}, nil
+	}
+	var out []statusRange
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		lo, hi, isRange := strings.Cut(part, "-")
+		from, err := strconv.Atoi(strings.TrimSpace(lo))
+		if err != nil {
+			return nil, fmt.Errorf("expected_status invalido: %q", part)
+		}
+		to := from
+		if isRange {
+			if to, err = strconv.Atoi(strings.TrimSpace(hi)); err != nil || to < from {
+				return nil, fmt.Errorf("expected_status invalido: %q", part)
+			}
+		}
+		out = append(out, statusRange{from, to})
+	}
+	return out, nil
+}
+
+func lines(raw string) []string {
+	var out []string
+	for _, line := range strings.Split(raw, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			out = append(out, line)
+		}
+	}
+	return out
+}
+
+// check devuelve el primer error de asercion encontrado o nil.
+func (a assertions) check(resp *http.Response, body []byte, elapsed time.Duration) error {
+	if !a.statusAccepted(resp.StatusCode) {
+		return fmt.Errorf("asercion status: se obtuvo %d, se esperaba %s", resp.StatusCode, a.describeStatuses())
+	}
+	if a.maxResponseTime > 0 && elapsed > a.maxResponseTime {
+		return fmt.Errorf("asercion tiempo de respuesta: %s supera %s", elapsed.Round(time.Microsecond), a.maxResponseTime)
+	}
+	for _, h := range a.headers {
+		got := resp.Header.Values(h.name)
+		if len(got) == 0 {
+			return fmt.Errorf("asercion header: falta %s", h.name)
+		}
+		if h.value != "" && !containsFold(got, h.value) {
+			return fmt.Errorf("asercion header: %s es %q, se esperaba %q", h.name, strings.Join(got, ", "), h.value)
+		}
+	}
+	if a.bodyContains != "" && !strings.Contains(string(body), a.bodyContains) {
+		return fmt.Errorf("asercion body: no contiene %q", a.bodyContains)
+	}
+	if a.bodyNotContains != "" && strings.Contains(string(body), a.bodyNotContains) {
+		return fmt.Errorf("asercion body: contiene %q", a.bodyNotContains)
+	}
+	if a.bodyRegex != nil && !a.bodyRegex.Match(body) {
+		return fmt.Errorf("asercion body: no coincide con /%s/", a.bodyRegex)
+	}
+	if len(a.jsonPaths) > 0 {
+		var doc any
+		if err := json.Unmarshal(body, &doc); err != nil {
+			return fmt.Errorf("asercion json: body no es JSON valido: %v", err)
+		}
+		for _, ja := range a.jsonPaths {
+			if err := ja.evaluate(doc); err != nil {
+				return fmt.Errorf("asercion json: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+func (a assertions) statusAccepted(code int) bool {
+	for _, r := range a.statuses {
+		if code >= r.from && code <= r.to {
+			return true
+		}
+	}
+	return false
+}
+
+func (a assertions) describeStatuses() string {
+	parts := make([]string, 0, len(a.statuses))
+	for _, r := range a.statuses {
+		if r.from == r.to {
+			parts = append(parts, strconv.Itoa(r.from))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", r.from, r.to))
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+func containsFold(values []string, want string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, want) || strings.Contains(strings.ToLower(v), strings.ToLower(want)) {
+			return true
+		}
+	}
+	return false
+}
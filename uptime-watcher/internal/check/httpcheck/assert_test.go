@@ -0,0 +1,123 @@
+package httpcheck
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseStatuses(t *testing.T) {
+	tests := []struct {
+		raw      string
+		accepted []int
+		rejected []int
+	}{
+		{"", []int{200, 301, 399}, []int{199, 400, 500}},
+		{"200", []int{200}, []int{201, 204}},
+		{"200,204", []int{200, 204}, []int{201}},
+		{"200-299, 418", []int{200, 250, 299, 418}, []int{300, 417}},
+	}
+	for _, tt := range tests {
+		statuses, err := parseStatuses(tt.raw)
+		if err != nil {
+			t.Errorf("%q: %v", tt.raw, err)
+			continue
+		}
+		a := assertions{statuses: statuses}
+		for _, code := range tt.accepted {
+			if !a.statusAccepted(code) {
+				t.Errorf("%q: rechazo %d", tt.raw, code)
+			}
+		}
+		for _, code := range tt.rejected {
+			if a.statusAccepted(code) {
+				t.Errorf("%q: acepto %d", tt.raw, code)
+			}
+		}
+	}
+	for _, raw := range []string{"abc", "299-200", "200-x"} {
+		if _, err := parseStatuses(raw); err == nil {
+			t.Errorf("%q: se esperaba un error", raw)
+		}
+	}
+}
+
+func TestParseAssertionsErrors(t *testing.T) {
+	tests := []struct {
+		opts map[string]string
+		want string
+	}{
+		{map[string]string{OptBodyRegex: "("}, "body_regex invalida"},
+		{map[string]string{OptMaxResponseTime: "rapido"}, "max_response_time invalido"},
+		{map[string]string{OptMaxResponseTime: "-1s"}, "max_response_time invalido"},
+		{map[string]string{OptRequiredHeaders: ": valor"}, "required_headers invalido"},
+		{map[string]string{OptJSONPath: "$.a ==="}, "json_path"},
+	}
+	for _, tt := range tests {
+		_, err := parseAssertions(tt.opts)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%v: error = %v, se esperaba %q", tt.opts, err, tt.want)
+		}
+	}
+}
+
+func TestAssertionsCheck(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: 200,
+		Header: http.Header{
+			"Content-Type":  {"application/json; charset=utf-8"},
+			"Cache-Control": {"no-cache", "private"},
+		},
+	}
+	body := []byte(`{"status": "ok", "build": "v1.4.2"}`)
+	tests := []struct {
+		name    string
+		opts    map[string]string
+		elapsed time.Duration
+		want    string // fragmento del error; vacio si pasa
+	}{
+		{"sin aserciones", nil, 0, ""},
+		{"status fuera de la lista", map[string]string{OptExpectedStatus: "201,204"}, 0, "se esperaba 201,204"},
+		{"status en rango", map[string]string{OptExpectedStatus: "200-299"}, 0, ""},
+		{"body contiene", map[string]string{OptBodyContains: `"ok"`}, 0, ""},
+		{"body no contiene", map[string]string{OptBodyContains: "error"}, 0, `no contiene "error"`},
+		{"body contiene prohibido", map[string]string{OptBodyNotContains: "ok"}, 0, `contiene "ok"`},
+		{"regex coincide", map[string]string{OptBodyRegex: `"build": "v1\.\d+\.\d+"`}, 0, ""},
+		{"regex no coincide", map[string]string{OptBodyRegex: `v2\.`}, 0, "no coincide"},
+		{"header presente", map[string]string{OptRequiredHeaders: "Content-Type"}, 0, ""},
+		{"header con valor parcial", map[string]string{OptRequiredHeaders: "content-type: application/json"}, 0, ""},
+		{"header con varios valores", map[string]string{OptRequiredHeaders: "Cache-Control: PRIVATE"}, 0, ""},
+		{"header faltante", map[string]string{OptRequiredHeaders: "Content-Type\nX-Request-Id"}, 0, "falta X-Request-Id"},
+		{"header con otro valor", map[string]string{OptRequiredHeaders: "Content-Type: text/html"}, 0, "se esperaba \"text/html\""},
+		{"json", map[string]string{OptJSONPath: "$.status == \"ok\"\n$.build"}, 0, ""},
+		{"json falla", map[string]string{OptJSONPath: `$.status == "down"`}, 0, "asercion json"},
+		{"tiempo de respuesta", map[string]string{OptMaxResponseTime: "100ms"}, 50 * time.Millisecond, ""},
+		{"tiempo de respuesta excedido", map[string]string{OptMaxResponseTime: "100ms"}, 150 * time.Millisecond, "supera 100ms"},
+	}
+	for _, tt := range tests {
+		a, err := parseAssertions(tt.opts)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		err = a.check(resp, body, tt.elapsed)
+		switch {
+		case tt.want == "" && err != nil:
+			t.Errorf("%s: %v", tt.name, err)
+		case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+			t.Errorf("%s: error = %v, se esperaba %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestAssertionsCheckInvalidJSON(t *testing.T) {
+	a, err := parseAssertions(map[string]string{OptJSONPath: "$.status"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = a.check(&http.Response{StatusCode: 200, Header: http.Header{}}, []byte("<html>"), 0)
+	if err == nil || !strings.Contains(err.Error(), "no es JSON valido") {
+		t.Fatalf("error = %v", err)
+	}
+}
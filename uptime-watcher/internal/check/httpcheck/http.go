@@ -0,0 +1,158 @@
+// Package httpcheck implementa chequeos HTTP(S).
+package httpcheck
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/check"
+	"proyecto-leng-paradigmas/ejemplo/internal/check/tlscheck"
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+)
+
+// OptTLSMinDays activa la validacion de vencimiento del certificado en URLs
+// https: el chequeo falla si quedan menos dias que el valor indicado.
+const OptTLSMinDays = "tls_min_days"
+
+func init() {
+	check.Register(New())
+}
+
+// Checker envia la peticion configurada (por defecto un GET) y evalua las
+// aserciones. Sin aserciones se considera exito cualquier codigo 2xx o 3xx.
+type Checker struct {
+	Client *http.Client
+}
+
+// New crea un Checker con un cliente por defecto. El cliente no reutiliza
+// conexiones: cada chequeo abre una nueva, asi el desglose de tiempos mide
+// DNS, conexion y TLS en lugar de registrarlos en 0 sobre una conexion del
+// pool.
+func New() *Checker {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DisableKeepAlives = true
+	return &Checker{
+		Client: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: transport,
+		},
+	}
+}
+
+// Spec implementa check.Checker.
+func (c *Checker) Spec() check.Spec {
+	return check.Spec{
+		Kind:  model.TargetHTTP,
+		Label: "HTTP",
+		Fields: []check.Field{
+			{Name: check.FieldURL, Label: "URL", Placeholder: "https://example.com/healthz"},
+			{Name: OptMethod, Label: "Metodo", Type: check.InputSelect, Choices: methods},
+			{Name: OptHeaders, Label: "Headers (uno por linea)", Placeholder: "X-Api-Key: secreto", Type: check.InputTextarea, Secret: true},
+			{Name: OptBody, Label: "Body", Placeholder: `{"ping": true}`, Type: check.InputTextarea},
+			{Name: OptAuthType, Label: "Autenticacion", Type: check.InputSelect, Choices: []string{AuthNone, AuthBasic, AuthBearer}},
+			{Name: OptAuthUser, Label: "Usuario (basic)"},
+			{Name: OptAuthPassword, Label: "Password (basic)", Type: check.InputPassword},
+			{Name: OptAuthToken, Label: "Token (bearer)", Type: check.InputPassword},
+			{Name: OptMaxRedirects, Label: "Maximo de redirects", Placeholder: "10 (0 = no seguir)", Type: check.InputNumber},
+			{Name: OptTLSMinDays, Label: "Dias minimos de certificado (opcional)", Placeholder: "14", Type: check.InputNumber},
+			{Name: OptExpectedStatus, Label: "Status aceptados", Placeholder: "200-299,301"},
+			{Name: OptMaxResponseTime, Label: "Tiempo maximo de respuesta", Placeholder: "500ms"},
+			{Name: OptBodyContains, Label: "Body debe contener", Placeholder: "ok"},
+			{Name: OptBodyNotContains, Label: "Body no debe contener", Placeholder: "error"},
+			{Name: OptBodyRegex, Label: "Body regex", Placeholder: `"status":\s*"up"`},
+			{Name: OptJSONPath, Label: "JSONPath (una por linea)", Placeholder: `$.status == "ok"`, Type: check.InputTextarea},
+			{Name: OptRequiredHeaders, Label: "Headers requeridos (uno por linea)", Placeholder: "Content-Type: application/json", Type: check.InputTextarea},
+		},
+	}
+}
+
+// Validate implementa check.Checker.
+func (c *Checker) Validate(target model.Target) error {
+	if target.URL == "" {
+		return errors.New("url requerida para targets http")
+	}
+	if _, err := tlscheck.MinDays(target.Options[OptTLSMinDays], 0); err != nil {
+		return err
+	}
+	if _, err := parseAssertions(target.Options); err != nil {
+		return err
+	}
+	if _, err := parseRequestConfig(target.Options); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Check implementa check.Checker.
+func (c *Checker) Check(ctx context.Context, target model.Target) model.CheckResult {
+	start := time.Now()
+	asserts, err := parseAssertions(target.Options)
+	if err != nil {
+		return check.Failure(target, start, err.Error())
+	}
+	reqCfg, err := parseRequestConfig(target.Options)
+	if err != nil {
+		return check.Failure(target, start, err.Error())
+	}
+	trace := &timingTrace{}
+	req, err := reqCfg.newRequest(trace.withContext(ctx), target)
+	if err != nil {
+		return check.Failure(target, start, fmt.Sprintf("no se pudo crear request: %v", err))
+	}
+	resp, err := reqCfg.client(c.Client).Do(req)
+	if err != nil {
+		result := check.Failure(target, start, fmt.Sprintf("error HTTP: %v", err))
+		var certErr *tls.CertificateVerificationError
+		if errors.As(err, &certErr) {
+			result.TLS = tlscheck.Inspect(certErr.UnverifiedCertificates, req.URL.Hostname(), nil, time.Now())
+			if msg, ok := tlscheck.Evaluate(result.TLS, 0); !ok {
+				result.Message = "error TLS: " + msg
+			}
+		}
+		return result
+	}
+	defer resp.Body.Close()
+
+	// el body se lee completo para medir la transferencia
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodyBytes))
+	if err != nil {
+		return check.Failure(target, start, fmt.Sprintf("error leyendo body: %v", err))
+	}
+	bodyDone := time.Now()
+	elapsed := bodyDone.Sub(start)
+
+	result := model.CheckResult{
+		TargetID:   target.ID,
+		CheckedAt:  time.Now(),
+		Duration:   elapsed,
+		Success:    true,
+		Message:    resp.Status,
+		StatusCode: resp.StatusCode,
+		Timing:     trace.timing(bodyDone),
+	}
+	if err := asserts.check(resp, body, elapsed); err != nil {
+		result.Success = false
+		result.Message = err.Error()
+	}
+	if resp.TLS != nil {
+		result.TLS = tlscheck.Inspect(resp.TLS.PeerCertificates, req.URL.Hostname(), nil, time.Now())
+		if len(resp.TLS.VerifiedChains) > 0 {
+			// el cliente ya valido la cadena, posiblemente con raices propias
+			result.TLS.ChainValid = true
+			result.TLS.ChainError = ""
+		}
+		if raw := target.Options[OptTLSMinDays]; raw != "" && result.Success {
+			minDays, _ := tlscheck.MinDays(raw, 0)
+			if msg, ok := tlscheck.Evaluate(result.TLS, minDays); !ok {
+				result.Success = false
+				result.Message = "error TLS: " + msg
+			}
+		}
+	}
+	return result
+}
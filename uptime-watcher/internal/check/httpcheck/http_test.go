@@ -0,0 +1,96 @@
+package httpcheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+)
+
+func httpTarget(url string) model.Target {
+	return model.Target{ID: "web", Kind: model.TargetHTTP, URL: url, Timeout: 5 * time.Second}
+}
+
+// localhost obliga a resolver el nombre, asi la fase DNS tambien se mide.
+func viaLocalhost(url string) string {
+	return strings.Replace(url, "127.0.0.1", "localhost", 1)
+}
+
+func TestTimingMeasuresFreshConnections(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	checker := New()
+	transport := checker.Client.Transport.(*http.Transport)
+	transport.TLSClientConfig = srv.Client().Transport.(*http.Transport).TLSClientConfig.Clone()
+	// el certificado de httptest es para example.com
+	transport.TLSClientConfig.ServerName = "example.com"
+
+	target := httpTarget(viaLocalhost(srv.URL))
+	for i := 0; i < 3; i++ {
+		res := checker.Check(context.Background(), target)
+		if !res.Success {
+			t.Fatalf("chequeo %d fallo: %s", i, res.Message)
+		}
+		if res.Timing == nil {
+			t.Fatalf("chequeo %d sin desglose", i)
+		}
+		// con conexiones reutilizadas estas fases quedarian en 0 desde el
+		// segundo chequeo
+		if res.Timing.DNS <= 0 || res.Timing.Connect <= 0 || res.Timing.TLS <= 0 {
+			t.Errorf("chequeo %d: dns=%s connect=%s tls=%s, se esperaban todas > 0", i, res.Timing.DNS, res.Timing.Connect, res.Timing.TLS)
+		}
+		if res.Timing.TTFB <= 0 {
+			t.Errorf("chequeo %d: ttfb=%s", i, res.Timing.TTFB)
+		}
+	}
+}
+
+func TestTimingAfterRedirect(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/a", func(w http.ResponseWriter, r *http.Request) { http.Redirect(w, r, "/b", http.StatusFound) })
+	mux.HandleFunc("/b", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("ok")) })
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	res := New().Check(context.Background(), httpTarget(viaLocalhost(srv.URL)+"/a"))
+	if !res.Success {
+		t.Fatalf("chequeo fallo: %s", res.Message)
+	}
+	if res.Timing.Connect <= 0 || res.Timing.TTFB <= 0 {
+		t.Errorf("connect=%s ttfb=%s, se esperaban > 0 para la ultima conexion", res.Timing.Connect, res.Timing.TTFB)
+	}
+	if res.Timing.TLS != 0 {
+		t.Errorf("tls=%s en una url http", res.Timing.TLS)
+	}
+}
+
+// TestTraceConcurrentCallbacks ejercita los callbacks desde varias goroutines,
+// como los intentos de conexion en paralelo; con -race detecta accesos sin
+// sincronizar.
+func TestTraceConcurrentCallbacks(t *testing.T) {
+	trace := &timingTrace{}
+	done := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		go func() {
+			defer func() { done <- struct{}{} }()
+			for j := 0; j < 100; j++ {
+				trace.mark(&trace.connectStart)
+				trace.connected("tcp", "127.0.0.1:1", nil)
+				trace.timing(time.Now())
+			}
+		}()
+	}
+	for i := 0; i < 4; i++ {
+		<-done
+	}
+	if timing := trace.timing(time.Now()); timing.Connect < 0 {
+		t.Errorf("connect=%s", timing.Connect)
+	}
+}
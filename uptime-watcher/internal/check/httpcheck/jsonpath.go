@@ -0,0 +1,187 @@
+package httpcheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// jsonAssertion es una expresion del estilo `$.status == "ok"`. Sin operador
+// solo se verifica que la ruta exista.
+type jsonAssertion struct {
+	raw      string
+	path     []pathStep
+	op       string
+	expected any
+}
+
+type pathStep struct {
+	key   string
+	index int
+	isIdx bool
+}
+
+// jsonOperators va de los mas largos a los mas cortos para que ">=" no se lea
+// como ">".
+var jsonOperators = []string{"==", "!=", ">=", "<=", ">", "<"}
+
+// parseJSONAssertion lee primero la ruta y despues toma el operador del texto
+// que sigue, asi una clave entre comillas puede contener caracteres de
+// operadores.
+func parseJSONAssertion(raw string) (jsonAssertion, error) {
+	a := jsonAssertion{raw: raw}
+	path, rest, err := parsePath(strings.TrimSpace(raw))
+	if err != nil {
+		return a, fmt.Errorf("ruta invalida en %q: %v", raw, err)
+	}
+	a.path = path
+	rest = strings.TrimSpace(rest)
+	if rest == "" {
+		return a, nil
+	}
+	for _, op := range jsonOperators {
+		if strings.HasPrefix(rest, op) {
+			a.op = op
+			break
+		}
+	}
+	if a.op == "" {
+		return a, fmt.Errorf("operador invalido en %q: se esperaba uno de %s", raw, strings.Join(jsonOperators, " "))
+	}
+	literal := strings.TrimSpace(rest[len(a.op):])
+	if err := json.Unmarshal([]byte(literal), &a.expected); err != nil {
+		return a, fmt.Errorf("valor invalido en %q: %v", raw, err)
+	}
+	return a, nil
+}
+
+// parsePath lee una ruta $.a.b, $.a[0], $.a[-1] o $['a b'] al comienzo de
+// expr y devuelve el texto que le sigue. Una clave con punto, espacios o
+// caracteres de operadores debe ir entre comillas.
+func parsePath(expr string) ([]pathStep, string, error) {
+	if !strings.HasPrefix(expr, "$") {
+		return nil, "", fmt.Errorf("debe comenzar con $")
+	}
+	rest := expr[1:]
+	var steps []pathStep
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[ \t=!<>")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, "", fmt.Errorf("clave vacia")
+			}
+			steps = append(steps, pathStep{key: rest[:end]})
+			rest = rest[end:]
+		case '[':
+			inner := strings.TrimLeft(rest[1:], " ")
+			if inner != "" && (inner[0] == '\'' || inner[0] == '"') {
+				end := strings.IndexByte(inner[1:], inner[0])
+				if end < 0 {
+					return nil, "", fmt.Errorf("falta cerrar la comilla")
+				}
+				key := inner[1 : end+1]
+				after := strings.TrimLeft(inner[end+2:], " ")
+				if !strings.HasPrefix(after, "]") {
+					return nil, "", fmt.Errorf("falta ]")
+				}
+				steps = append(steps, pathStep{key: key})
+				rest = after[1:]
+				continue
+			}
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, "", fmt.Errorf("falta ]")
+			}
+			raw := strings.TrimSpace(rest[1:end])
+			idx, err := strconv.Atoi(raw)
+			if err != nil {
+				return nil, "", fmt.Errorf("indice invalido %q", raw)
+			}
+			steps = append(steps, pathStep{index: idx, isIdx: true})
+			rest = rest[end+1:]
+		case ' ', '\t', '=', '!', '<', '>':
+			return steps, rest, nil
+		default:
+			return nil, "", fmt.Errorf("caracter inesperado %q", rest[0])
+		}
+	}
+	return steps, "", nil
+}
+
+// evaluate aplica la asercion sobre un documento ya decodificado.
+func (a jsonAssertion) evaluate(doc any) error {
+	value, ok := lookupPath(doc, a.path)
+	if !ok {
+		return fmt.Errorf("%s: ruta inexistente", a.raw)
+	}
+	if a.op == "" {
+		return nil
+	}
+	if !compare(value, a.op, a.expected) {
+		got, _ := json.Marshal(value)
+		return fmt.Errorf("%s: se obtuvo %s", a.raw, got)
+	}
+	return nil
+}
+
+func lookupPath(doc any, path []pathStep) (any, bool) {
+	current := doc
+	for _, step := range path {
+		if step.isIdx {
+			list, ok := current.([]any)
+			if !ok {
+				return nil, false
+			}
+			idx := step.index
+			if idx < 0 {
+				idx += len(list)
+			}
+			if idx < 0 || idx >= len(list) {
+				return nil, false
+			}
+			current = list[idx]
+			continue
+		}
+		obj, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		current, ok = obj[step.key]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func compare(got any, op string, expected any) bool {
+	switch op {
+	case "==":
+		return reflect.DeepEqual(got, expected)
+	case "!=":
+		return !reflect.DeepEqual(got, expected)
+	}
+	g, ok1 := got.(float64)
+	e, ok2 := expected.(float64)
+	if !ok1 || !ok2 {
+		return false
+	}
+	switch op {
+	case ">":
+		return g > e
+	case ">=":
+		return g >= e
+	case "<":
+		return g < e
+	case "<=":
+		return g <= e
+	}
+	return false
+}
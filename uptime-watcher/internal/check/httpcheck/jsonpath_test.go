@@ -0,0 +1,102 @@
+package httpcheck
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const jsonDoc = `{
+	"status": "ok",
+	"count": 3,
+	"ratio": 0.5,
+	"enabled": true,
+	"owner": null,
+	"items": [{"id": 1}, {"id": 2}, {"id": 3}],
+	"meta": {"version": "1.2"},
+	"a<b": 1,
+	"x==y": "igual",
+	"a.b": "punto",
+	"con espacio": "si"
+}`
+
+func TestJSONAssertions(t *testing.T) {
+	var doc any
+	if err := json.Unmarshal([]byte(jsonDoc), &doc); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		expr string
+		ok   bool
+	}{
+		// solo existencia
+		{"$.status", true},
+		{"$.missing", false},
+		{"$.owner", true},
+		// claves e indices
+		{`$.status == "ok"`, true},
+		{`$.status == "down"`, false},
+		{`$.meta.version == "1.2"`, true},
+		{"$.items[0].id == 1", true},
+		{"$.items[-1].id == 3", true},
+		{"$.items[-3].id == 1", true},
+		{"$.items[3]", false},
+		{"$.items[-4]", false},
+		{"$.status[0]", false},
+		{"$.items.id", false},
+		{`$['meta']["version"] == "1.2"`, true},
+		// claves entre comillas con caracteres de operadores, puntos o espacios
+		{"$['a<b'] == 1", true},
+		{"$['a<b'] > 1", false},
+		{`$["x==y"] == "igual"`, true},
+		{`$['a.b'] == "punto"`, true},
+		{`$[ 'con espacio' ] == "si"`, true},
+		// cada operador
+		{"$.count == 3", true},
+		{"$.count != 3", false},
+		{"$.count != 4", true},
+		{"$.count > 2", true},
+		{"$.count > 3", false},
+		{"$.count >= 3", true},
+		{"$.count < 4", true},
+		{"$.count < 3", false},
+		{"$.count <= 3", true},
+		{"$.ratio<=0.5", true},
+		{"$.enabled == true", true},
+		{"$.owner == null", true},
+		{`$.items[0] == {"id": 1}`, true},
+		// las comparaciones de orden solo aplican a numeros
+		{`$.status > 1`, false},
+	}
+	for _, tt := range tests {
+		a, err := parseJSONAssertion(tt.expr)
+		if err != nil {
+			t.Errorf("%s: %v", tt.expr, err)
+			continue
+		}
+		if err := a.evaluate(doc); (err == nil) != tt.ok {
+			t.Errorf("%s: evaluate = %v, se esperaba ok=%v", tt.expr, err, tt.ok)
+		}
+	}
+}
+
+func TestParseJSONAssertionErrors(t *testing.T) {
+	tests := []struct{ expr, want string }{
+		{"status == 1", "debe comenzar con $"},
+		{"$.", "clave vacia"},
+		{"$.items[0", "falta ]"},
+		{"$.items[x]", "indice invalido"},
+		{"$['abc] == 1", "falta cerrar la comilla"},
+		{"$['abc' == 1", "falta ]"},
+		{"$.count =~ 1", "operador invalido"},
+		{"$.count ~ 1", "operador invalido"},
+		{"$.count == ok", "valor invalido"},
+		{"$x", "caracter inesperado"},
+	}
+	for _, tt := range tests {
+		_, err := parseJSONAssertion(tt.expr)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error = %v, se esperaba %q", tt.expr, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package httpcheck
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+)
+
+// Opciones que configuran la peticion enviada.
+const (
+	OptMethod       = "method"
+	OptHeaders      = "headers"
+	OptBody         = "body"
+	OptAuthType     = "auth_type"
+	OptAuthUser     = "auth_user"
+	OptAuthPassword = "auth_password"
+	OptAuthToken    = "auth_token"
+	OptMaxRedirects = "max_redirects"
+)
+
+// Tipos de autenticacion soportados.
+const (
+	AuthNone   = "none"
+	AuthBasic  = "basic"
+	AuthBearer = "bearer"
+)
+
+var methods = []string{
+	http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+	http.MethodPatch, http.MethodDelete, http.MethodOptions,
+}
+
+// requestConfig describe la peticion a enviar segun las opciones del target.
+type requestConfig struct {
+	method       string
+	headers      http.Header
+	body         string
+	authType     string
+	user         string
+	password     string
+	token        string
+	maxRedirects int // -1 usa la politica por defecto de net/http
+}
+
+func parseRequestConfig(opts map[string]string) (requestConfig, error) {
+	cfg := requestConfig{
+		method:       strings.ToUpper(strings.TrimSpace(opts[OptMethod])),
+		headers:      make(http.Header),
+		body:         opts[OptBody],
+		authType:     strings.ToLower(strings.TrimSpace(opts[OptAuthType])),
+		user:         opts[OptAuthUser],
+		password:     opts[OptAuthPassword],
+		token:        opts[OptAuthToken],
+		maxRedirects: -1,
+	}
+	if cfg.method == "" {
+		cfg.method = http.MethodGet
+	}
+	if !validMethod(cfg.method) {
+		return cfg, fmt.Errorf("metodo HTTP no soportado: %s", cfg.method)
+	}
+	for _, line := range lines(opts[OptHeaders]) {
+		name, value, ok := strings.Cut(line, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return cfg, fmt.Errorf("header invalido: %q (formato Nombre: valor)", line)
+		}
+		cfg.headers.Add(name, strings.TrimSpace(value))
+	}
+	switch cfg.authType {
+	case "", AuthNone:
+		cfg.authType = AuthNone
+	case AuthBasic:
+		if cfg.user == "" {
+			return cfg, fmt.Errorf("auth basic requiere auth_user")
+		}
+	case AuthBearer:
+		if cfg.token == "" {
+			return cfg, fmt.Errorf("auth bearer requiere auth_token")
+		}
+	default:
+		return cfg, fmt.Errorf("auth_type desconocido: %s", cfg.authType)
+	}
+	if raw := strings.TrimSpace(opts[OptMaxRedirects]); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			return cfg, fmt.Errorf("max_redirects invalido: %s", raw)
+		}
+		cfg.maxRedirects = n
+	}
+	return cfg, nil
+}
+
+func validMethod(method string) bool {
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+func (cfg requestConfig) newRequest(ctx context.Context, target model.Target) (*http.Request, error) {
+	var body io.Reader
+	if cfg.body != "" {
+		body = strings.NewReader(cfg.body)
+	}
+	req, err := http.NewRequestWithContext(ctx, cfg.method, target.URL, body)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range cfg.headers {
+		req.Header[name] = append([]string(nil), values...)
+	}
+	if cfg.body != "" && req.Header.Get("Content-Type") == "" && looksLikeJSON(cfg.body) {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	switch cfg.authType {
+	case AuthBasic:
+		req.SetBasicAuth(cfg.user, cfg.password)
+	case AuthBearer:
+		req.Header.Set("Authorization", "Bearer "+cfg.token)
+	}
+	return req, nil
+}
+
+// client devuelve el cliente base o una copia con la politica de redirects
+// configurada.
+func (cfg requestConfig) client(base *http.Client) *http.Client {
+	if cfg.maxRedirects < 0 {
+		return base
+	}
+	c := *base
+	max := cfg.maxRedirects
+	c.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) > max {
+			// se devuelve la ultima respuesta para que las aserciones la evaluen
+			return http.ErrUseLastResponse
+		}
+		return nil
+	}
+	return &c
+}
+
+func looksLikeJSON(body string) bool {
+	trimmed := strings.TrimSpace(body)
+	return strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")
+}
@@ -0,0 +1,148 @@
+package httpcheck
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// received es lo que el servidor de prueba vio de la peticion.
+type received struct {
+	method, body, contentType, auth, apiKey string
+}
+
+func TestRequestOptions(t *testing.T) {
+	seen := make(chan received, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		seen <- received{
+			method:      r.Method,
+			body:        string(body),
+			contentType: r.Header.Get("Content-Type"),
+			auth:        r.Header.Get("Authorization"),
+			apiKey:      r.Header.Get("X-Api-Key"),
+		}
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name string
+		opts map[string]string
+		want received
+	}{
+		{"por defecto", nil, received{method: http.MethodGet}},
+		{"metodo en minusculas", map[string]string{OptMethod: "delete"}, received{method: http.MethodDelete}},
+		{
+			name: "body json",
+			opts: map[string]string{OptMethod: "POST", OptBody: `{"ping": true}`},
+			want: received{method: http.MethodPost, body: `{"ping": true}`, contentType: "application/json"},
+		},
+		{
+			name: "content-type explicito",
+			opts: map[string]string{OptMethod: "PUT", OptBody: "a=1", OptHeaders: "Content-Type: application/x-www-form-urlencoded"},
+			want: received{method: http.MethodPut, body: "a=1", contentType: "application/x-www-form-urlencoded"},
+		},
+		{
+			name: "headers",
+			opts: map[string]string{OptHeaders: "X-Api-Key:  k1 \n\n"},
+			want: received{method: http.MethodGet, apiKey: "k1"},
+		},
+		{
+			name: "basic",
+			opts: map[string]string{OptAuthType: "Basic", OptAuthUser: "ana", OptAuthPassword: "hunter2"},
+			// base64("ana:hunter2")
+			want: received{method: http.MethodGet, auth: "Basic YW5hOmh1bnRlcjI="},
+		},
+		{
+			name: "bearer",
+			opts: map[string]string{OptAuthType: AuthBearer, OptAuthToken: "t0k"},
+			want: received{method: http.MethodGet, auth: "Bearer t0k"},
+		},
+		{
+			name: "bearer reemplaza un header Authorization",
+			opts: map[string]string{OptHeaders: "Authorization: viejo", OptAuthType: AuthBearer, OptAuthToken: "t0k"},
+			want: received{method: http.MethodGet, auth: "Bearer t0k"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := httpTarget(srv.URL)
+			target.Options = tt.opts
+			res := New().Check(context.Background(), target)
+			if !res.Success {
+				t.Fatalf("chequeo fallo: %s", res.Message)
+			}
+			if got := <-seen; got != tt.want {
+				t.Fatalf("peticion = %+v, se esperaba %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaxRedirects(t *testing.T) {
+	var hits [3]atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/0", func(w http.ResponseWriter, r *http.Request) {
+		hits[0].Add(1)
+		http.Redirect(w, r, "/1", http.StatusFound)
+	})
+	mux.HandleFunc("/1", func(w http.ResponseWriter, r *http.Request) {
+		hits[1].Add(1)
+		http.Redirect(w, r, "/2", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/2", func(w http.ResponseWriter, r *http.Request) { hits[2].Add(1) })
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	tests := []struct {
+		max    string
+		status int
+		hits   [3]int32
+	}{
+		{"", http.StatusOK, [3]int32{1, 1, 1}},
+		{"0", http.StatusFound, [3]int32{1, 0, 0}},
+		{"1", http.StatusMovedPermanently, [3]int32{1, 1, 0}},
+		{"2", http.StatusOK, [3]int32{1, 1, 1}},
+	}
+	for _, tt := range tests {
+		for i := range hits {
+			hits[i].Store(0)
+		}
+		target := httpTarget(srv.URL + "/0")
+		target.Options = map[string]string{OptMaxRedirects: tt.max}
+		res := New().Check(context.Background(), target)
+		if !res.Success || res.StatusCode != tt.status {
+			t.Errorf("max_redirects=%q: exito=%v status=%d, se esperaba %d", tt.max, res.Success, res.StatusCode, tt.status)
+		}
+		got := [3]int32{hits[0].Load(), hits[1].Load(), hits[2].Load()}
+		if got != tt.hits {
+			t.Errorf("max_redirects=%q: visitas %v, se esperaba %v", tt.max, got, tt.hits)
+		}
+	}
+}
+
+func TestParseRequestConfigErrors(t *testing.T) {
+	tests := []struct {
+		opts map[string]string
+		want string
+	}{
+		{map[string]string{OptMethod: "TRACE"}, "metodo HTTP no soportado"},
+		{map[string]string{OptHeaders: "sin-dos-puntos"}, "header invalido"},
+		{map[string]string{OptHeaders: ": valor"}, "header invalido"},
+		{map[string]string{OptAuthType: AuthBasic}, "requiere auth_user"},
+		{map[string]string{OptAuthType: AuthBearer}, "requiere auth_token"},
+		{map[string]string{OptAuthType: "digest"}, "auth_type desconocido"},
+		{map[string]string{OptMaxRedirects: "-1"}, "max_redirects invalido"},
+		{map[string]string{OptMaxRedirects: "muchos"}, "max_redirects invalido"},
+	}
+	for _, tt := range tests {
+		_, err := parseRequestConfig(tt.opts)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%v: error %v, se esperaba %q", tt.opts, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package httpcheck
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+)
+
+// timingTrace registra los instantes de cada fase de la peticion. Ante
+// redirects se conservan los de la ultima conexion. Los callbacks pueden
+// llegar desde varias goroutines (intentos de conexion en paralelo, o uno que
+// termina despues de Do), por eso cada fase guarda solo su primer evento y
+// todo se lee bajo mu.
+type timingTrace struct {
+	mu                        sync.Mutex
+	dnsStart, dnsDone         time.Time
+	connectStart, connectDone time.Time
+	tlsStart, tlsDone         time.Time
+	wroteRequest, firstByte   time.Time
+}
+
+func (t *timingTrace) withContext(ctx context.Context) context.Context {
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		// cada peticion de una cadena de redirects empieza de cero
+		GetConn:              func(string) { t.reset() },
+		DNSStart:             func(httptrace.DNSStartInfo) { t.mark(&t.dnsStart) },
+		DNSDone:              func(httptrace.DNSDoneInfo) { t.mark(&t.dnsDone) },
+		ConnectStart:         func(string, string) { t.mark(&t.connectStart) },
+		ConnectDone:          t.connected,
+		TLSHandshakeStart:    func() { t.mark(&t.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { t.mark(&t.tlsDone) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { t.mark(&t.wroteRequest) },
+		GotFirstResponseByte: func() { t.mark(&t.firstByte) },
+	})
+}
+
+// mark registra el instante de un evento si es el primero de su fase.
+func (t *timingTrace) mark(at *time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if at.IsZero() {
+		*at = time.Now()
+	}
+}
+
+// connected cierra la fase de conexion con el primer intento exitoso; los
+// intentos en paralelo que fallan o se descartan no cuentan.
+func (t *timingTrace) connected(_, _ string, err error) {
+	if err == nil {
+		t.mark(&t.connectDone)
+	}
+}
+
+func (t *timingTrace) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.dnsStart, t.dnsDone = time.Time{}, time.Time{}
+	t.connectStart, t.connectDone = time.Time{}, time.Time{}
+	t.tlsStart, t.tlsDone = time.Time{}, time.Time{}
+	t.wroteRequest, t.firstByte = time.Time{}, time.Time{}
+}
+
+// timing arma el desglose; bodyDone es el instante en que se termino de leer
+// la respuesta.
+func (t *timingTrace) timing(bodyDone time.Time) *model.HTTPTiming {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return &model.HTTPTiming{
+		DNS:      between(t.dnsStart, t.dnsDone),
+		Connect:  between(t.connectStart, t.connectDone),
+		TLS:      between(t.tlsStart, t.tlsDone),
+		TTFB:     between(t.wroteRequest, t.firstByte),
+		Transfer: between(t.firstByte, bodyDone),
+	}
+}
+
+func between(from, to time.Time) time.Duration {
+	if from.IsZero() || to.IsZero() || to.Before(from) {
+		return 0
+	}
+	return to.Sub(from)
+}
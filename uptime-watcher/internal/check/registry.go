@@ -0,0 +1,172 @@
+package check
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+)
+
+// Nombres de campos que se mapean a atributos fijos de model.Target. El resto
+// de los campos declarados en un Spec se guardan en Target.Options.
+const (
+	FieldURL  = "url"
+	FieldHost = "host"
+	FieldPort = "port"
+)
+
+// Tipos de input soportados por el formulario de la UI.
+const (
+	InputText     = "text"
+	InputNumber   = "number"
+	InputTextarea = "textarea"
+	InputSelect   = "select"
+	InputPassword = "password"
+)
+
+// Spec describe un tipo de chequeo para los distintos frontends.
+type Spec struct {
+	Kind   model.TargetKind `json:"kind"`
+	Label  string           `json:"label"`
+	Fields []Field          `json:"fields"`
+}
+
+// Field es un campo configurable propio de un tipo de chequeo.
+type Field struct {
+	Name        string   `json:"name"`
+	Label       string   `json:"label"`
+	Placeholder string   `json:"placeholder,omitempty"`
+	Type        string   `json:"type,omitempty"`
+	Choices     []string `json:"choices,omitempty"`
+	// Secret marca campos que solo se escriben: nunca se devuelven (ver
+	// Redact) y un valor vacio al editar conserva el guardado. Los campos
+	// InputPassword son siempre secretos.
+	Secret bool `json:"secret,omitempty"`
+}
+
+// IsSecret indica si el valor del campo no debe salir del monitor.
+func (f Field) IsSecret() bool {
+	return f.Secret || f.Type == InputPassword
+}
+
+// Registry asocia cada TargetKind con su Checker.
+type Registry struct {
+	mu       sync.RWMutex
+	checkers map[model.TargetKind]Checker
+}
+
+// DefaultRegistry es el registro usado por las funciones del paquete.
+var DefaultRegistry = NewRegistry()
+
+// NewRegistry crea un registro vacio.
+func NewRegistry() *Registry {
+	return &Registry{checkers: make(map[model.TargetKind]Checker)}
+}
+
+// Register agrega un Checker. Registrar dos veces el mismo tipo es un error de
+// programacion y provoca panic, igual que sql.Register.
+func (r *Registry) Register(c Checker) {
+	kind := c.Spec().Kind
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, dup := r.checkers[kind]; dup {
+		panic(fmt.Sprintf("check: tipo %q registrado dos veces", kind))
+	}
+	r.checkers[kind] = c
+}
+
+// Lookup busca el Checker de un tipo.
+func (r *Registry) Lookup(kind model.TargetKind) (Checker, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c, ok := r.checkers[kind]
+	return c, ok
+}
+
+// Specs devuelve los tipos registrados ordenados por kind.
+func (r *Registry) Specs() []Spec {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]Spec, 0, len(r.checkers))
+	for _, c := range r.checkers {
+		out = append(out, c.Spec())
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Kind < out[j].Kind })
+	return out
+}
+
+// Validate delega la validacion en el Checker del tipo del target.
+func (r *Registry) Validate(target model.Target) error {
+	c, ok := r.Lookup(target.Kind)
+	if !ok {
+		return fmt.Errorf("tipo de target desconocido: %s", target.Kind)
+	}
+	return c.Validate(target)
+}
+
+// Register agrega un Checker al registro por defecto.
+func Register(c Checker) { DefaultRegistry.Register(c) }
+
+// Lookup busca un Checker en el registro por defecto.
+func Lookup(kind model.TargetKind) (Checker, bool) { return DefaultRegistry.Lookup(kind) }
+
+// Specs lista los tipos del registro por defecto.
+func Specs() []Spec { return DefaultRegistry.Specs() }
+
+// Validate valida un target contra el registro por defecto.
+func Validate(target model.Target) error { return DefaultRegistry.Validate(target) }
+
+// SetField asigna el valor de un campo declarado en un Spec al target. Un
+// campo secreto vacio o enmascarado conserva el valor que ya tenia; uno con
+// ClearSecret queda marcado para que KeepSecrets lo borre.
+func SetField(target *model.Target, name, value string) error {
+	switch name {
+	case FieldURL:
+		target.URL = value
+	case FieldHost:
+		target.Host = value
+	case FieldPort:
+		if value == "" {
+			target.Port = 0
+			return nil
+		}
+		port, err := strconv.Atoi(value)
+		if err != nil {
+			return errors.New("port invalido")
+		}
+		target.Port = port
+	default:
+		if (value == "" || value == Redacted) && secretField(target.Kind, name) {
+			return nil
+		}
+		if value == "" {
+			delete(target.Options, name)
+			return nil
+		}
+		if target.Options == nil {
+			target.Options = make(map[string]string)
+		}
+		target.Options[name] = value
+	}
+	return nil
+}
+
+// FieldValue lee el valor de un campo declarado en un Spec.
+func FieldValue(target model.Target, name string) string {
+	switch name {
+	case FieldURL:
+		return target.URL
+	case FieldHost:
+		return target.Host
+	case FieldPort:
+		if target.Port == 0 {
+			return ""
+		}
+		return strconv.Itoa(target.Port)
+	default:
+		return target.Options[name]
+	}
+}
@@ -0,0 +1,92 @@
+package check
+
+import (
+	"maps"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+)
+
+// Redacted reemplaza el valor de los campos secretos en las lecturas.
+const Redacted = "********"
+
+// ClearSecret, enviado como valor de un campo secreto, borra el valor
+// guardado. Vacio o Redacted lo conservan, asi que sin este valor un secreto
+// no podria quitarse al editar.
+const ClearSecret = "<clear>"
+
+// Redact devuelve una copia de target con los campos secretos de su tipo
+// enmascarados, para exponerlo por la API, los eventos o la UI. El target
+// original no se modifica.
+func Redact(target model.Target) model.Target {
+	spec, ok := specOf(target.Kind)
+	if !ok || len(target.Options) == 0 {
+		return target
+	}
+	var redacted map[string]string
+	for _, f := range spec.Fields {
+		if !f.IsSecret() || target.Options[f.Name] == "" {
+			continue
+		}
+		if redacted == nil {
+			redacted = maps.Clone(target.Options)
+		}
+		redacted[f.Name] = Redacted
+	}
+	if redacted != nil {
+		target.Options = redacted
+	}
+	return target
+}
+
+// KeepSecrets completa los campos secretos de target que llegan vacios o
+// enmascarados con los de stored, la version guardada. Asi un cliente puede
+// reenviar lo que leyo (o un formulario sin el secreto) sin borrarlo. Los que
+// llegan como ClearSecret se quitan.
+func KeepSecrets(target *model.Target, stored model.Target) {
+	spec, ok := specOf(target.Kind)
+	if !ok {
+		return
+	}
+	for _, f := range spec.Fields {
+		if !f.IsSecret() {
+			continue
+		}
+		value := target.Options[f.Name]
+		if value == ClearSecret {
+			delete(target.Options, f.Name)
+			continue
+		}
+		if value != "" && value != Redacted {
+			continue
+		}
+		if previous := stored.Options[f.Name]; stored.Kind == target.Kind && previous != "" {
+			if target.Options == nil {
+				target.Options = make(map[string]string)
+			}
+			target.Options[f.Name] = previous
+			continue
+		}
+		delete(target.Options, f.Name)
+	}
+}
+
+func specOf(kind model.TargetKind) (Spec, bool) {
+	c, ok := Lookup(kind)
+	if !ok {
+		return Spec{}, false
+	}
+	return c.Spec(), true
+}
+
+func secretField(kind model.TargetKind, name string) bool {
+	spec, ok := specOf(kind)
+	if !ok {
+		return false
+	}
+	for _, f := range spec.Fields {
+		if f.Name == name {
+			return f.IsSecret()
+		}
+	}
+	return false
+}
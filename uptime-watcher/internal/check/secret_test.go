@@ -0,0 +1,126 @@
+package check
+
+import (
+	"context"
+	"testing"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+)
+
+const secretKind model.TargetKind = "secret-test"
+
+type secretChecker struct{}
+
+func (secretChecker) Spec() Spec {
+	return Spec{Kind: secretKind, Fields: []Field{
+		{Name: FieldURL},
+		{Name: "user"},
+		{Name: "password", Type: InputPassword},
+		{Name: "headers", Type: InputTextarea, Secret: true},
+	}}
+}
+
+func (secretChecker) Validate(model.Target) error { return nil }
+
+func (secretChecker) Check(context.Context, model.Target) model.CheckResult {
+	return model.CheckResult{}
+}
+
+func init() {
+	Register(secretChecker{})
+}
+
+func secretTarget(options map[string]string) model.Target {
+	return model.Target{ID: "a", Kind: secretKind, URL: "https://example.test", Options: options}
+}
+
+func TestRedact(t *testing.T) {
+	stored := secretTarget(map[string]string{"user": "ana", "password": "hunter2", "headers": "X-Api-Key: k"})
+	got := Redact(stored)
+	want := map[string]string{"user": "ana", "password": Redacted, "headers": Redacted}
+	for k, v := range want {
+		if got.Options[k] != v {
+			t.Errorf("Options[%s] = %q, se esperaba %q", k, got.Options[k], v)
+		}
+	}
+	if stored.Options["password"] != "hunter2" {
+		t.Error("Redact modifico el target original")
+	}
+
+	empty := Redact(secretTarget(map[string]string{"user": "ana"}))
+	if _, ok := empty.Options["password"]; ok {
+		t.Error("Redact agrego un secreto que no estaba configurado")
+	}
+}
+
+func TestKeepSecrets(t *testing.T) {
+	stored := secretTarget(map[string]string{"password": "hunter2", "headers": "X-Api-Key: k"})
+
+	// lo que devolvio una lectura, reenviado tal cual
+	next := Redact(stored)
+	KeepSecrets(&next, stored)
+	if next.Options["password"] != "hunter2" || next.Options["headers"] != "X-Api-Key: k" {
+		t.Errorf("enmascarados: Options = %v", next.Options)
+	}
+
+	// formulario sin los secretos
+	next = secretTarget(nil)
+	KeepSecrets(&next, stored)
+	if next.Options["password"] != "hunter2" {
+		t.Errorf("vacios: Options = %v", next.Options)
+	}
+
+	// un valor nuevo reemplaza al guardado
+	next = secretTarget(map[string]string{"password": "nueva"})
+	KeepSecrets(&next, stored)
+	if next.Options["password"] != "nueva" {
+		t.Errorf("reemplazo: Options = %v", next.Options)
+	}
+
+	// ClearSecret borra el guardado
+	next = secretTarget(map[string]string{"password": Redacted, "headers": ClearSecret})
+	KeepSecrets(&next, stored)
+	if _, ok := next.Options["headers"]; ok || next.Options["password"] != "hunter2" {
+		t.Errorf("borrado: Options = %v", next.Options)
+	}
+
+	// sin valor guardado la mascara no se persiste
+	next = secretTarget(map[string]string{"password": Redacted})
+	KeepSecrets(&next, secretTarget(nil))
+	if _, ok := next.Options["password"]; ok {
+		t.Errorf("mascara persistida: Options = %v", next.Options)
+	}
+}
+
+func TestSetFieldKeepsSecrets(t *testing.T) {
+	target := secretTarget(map[string]string{"user": "ana", "password": "hunter2"})
+	for name, value := range map[string]string{"user": "", "password": ""} {
+		if err := SetField(&target, name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, ok := target.Options["user"]; ok {
+		t.Error("un campo comun vacio deberia borrarse")
+	}
+	if target.Options["password"] != "hunter2" {
+		t.Error("un secreto vacio deberia conservar el valor guardado")
+	}
+	if err := SetField(&target, "password", Redacted); err != nil {
+		t.Fatal(err)
+	}
+	if target.Options["password"] != "hunter2" {
+		t.Error("un secreto enmascarado deberia conservar el valor guardado")
+	}
+}
+
+func TestSetFieldClearSecret(t *testing.T) {
+	stored := secretTarget(map[string]string{"headers": "X-Api-Key: k"})
+	next := secretTarget(nil)
+	if err := SetField(&next, "headers", ClearSecret); err != nil {
+		t.Fatal(err)
+	}
+	KeepSecrets(&next, stored)
+	if _, ok := next.Options["headers"]; ok {
+		t.Errorf("Options = %v, se esperaba el header borrado", next.Options)
+	}
+}
@@ -0,0 +1,61 @@
+// Package tcpcheck implementa chequeos de conexion TCP.
+package tcpcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/check"
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+)
+
+func init() {
+	check.Register(Checker{})
+}
+
+// Checker intenta abrir una conexion TCP contra host:port.
+type Checker struct{}
+
+// Spec implementa check.Checker.
+func (Checker) Spec() check.Spec {
+	return check.Spec{
+		Kind:  model.TargetTCP,
+		Label: "TCP",
+		Fields: []check.Field{
+			{Name: check.FieldHost, Label: "Host", Placeholder: "localhost"},
+			{Name: check.FieldPort, Label: "Puerto", Placeholder: "5432", Type: check.InputNumber},
+		},
+	}
+}
+
+// Validate implementa check.Checker.
+func (Checker) Validate(target model.Target) error {
+	if target.Host == "" || target.Port == 0 {
+		return errors.New("host y port requeridos para targets tcp")
+	}
+	return nil
+}
+
+// Check implementa check.Checker.
+func (Checker) Check(ctx context.Context, target model.Target) model.CheckResult {
+	start := time.Now()
+	address := net.JoinHostPort(target.Host, strconv.Itoa(target.Port))
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return check.Failure(target, start, fmt.Sprintf("conexion fallida: %v", err))
+	}
+	conn.Close()
+	return model.CheckResult{
+		TargetID:  target.ID,
+		CheckedAt: time.Now(),
+		Duration:  time.Since(start),
+		Success:   true,
+		Message:   "tcp ok",
+	}
+}
@@ -0,0 +1,168 @@
+// Package tlscheck implementa chequeos de certificados TLS: vencimiento del
+// certificado hoja y validez de la cadena.
+package tlscheck
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"time"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/check"
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+)
+
+// Kind identifica los targets TLS.
+const Kind model.TargetKind = "tls"
+
+// Opciones propias del tipo tls.
+const (
+	OptMinDays    = "min_days"
+	OptServerName = "server_name"
+)
+
+// DefaultMinDays es el umbral de vencimiento cuando no se configura min_days.
+const DefaultMinDays = 14
+
+const defaultPort = 443
+
+func init() {
+	check.Register(Checker{})
+}
+
+// Checker realiza un handshake TLS contra host:port e inspecciona el
+// certificado presentado.
+type Checker struct {
+	// Roots reemplaza las raices del sistema al validar la cadena.
+	Roots *x509.CertPool
+}
+
+// Spec implementa check.Checker.
+func (Checker) Spec() check.Spec {
+	return check.Spec{
+		Kind:  Kind,
+		Label: "TLS",
+		Fields: []check.Field{
+			{Name: check.FieldHost, Label: "Host", Placeholder: "example.com"},
+			{Name: check.FieldPort, Label: "Puerto", Placeholder: "443", Type: check.InputNumber},
+			{Name: OptMinDays, Label: "Dias minimos de vigencia", Placeholder: strconv.Itoa(DefaultMinDays), Type: check.InputNumber},
+			{Name: OptServerName, Label: "SNI (opcional)", Placeholder: "example.com"},
+		},
+	}
+}
+
+// Validate implementa check.Checker.
+func (Checker) Validate(target model.Target) error {
+	if target.Host == "" {
+		return errors.New("host requerido para targets tls")
+	}
+	if _, err := MinDays(target.Options[OptMinDays], DefaultMinDays); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Check implementa check.Checker.
+func (c Checker) Check(ctx context.Context, target model.Target) model.CheckResult {
+	start := time.Now()
+	minDays, err := MinDays(target.Options[OptMinDays], DefaultMinDays)
+	if err != nil {
+		return check.Failure(target, start, err.Error())
+	}
+	port := target.Port
+	if port == 0 {
+		port = defaultPort
+	}
+	serverName := target.Options[OptServerName]
+	if serverName == "" {
+		serverName = target.Host
+	}
+
+	// la verificacion se hace a mano para poder reportar certificados vencidos
+	dialer := tls.Dialer{Config: &tls.Config{ServerName: serverName, InsecureSkipVerify: true}}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(target.Host, strconv.Itoa(port)))
+	if err != nil {
+		return check.Failure(target, start, fmt.Sprintf("handshake TLS fallido: %v", err))
+	}
+	state := conn.(*tls.Conn).ConnectionState()
+	conn.Close()
+
+	info := Inspect(state.PeerCertificates, serverName, c.Roots, time.Now())
+	result := model.CheckResult{
+		TargetID:  target.ID,
+		CheckedAt: time.Now(),
+		Duration:  time.Since(start),
+		TLS:       info,
+	}
+	result.Message, result.Success = Evaluate(info, minDays)
+	return result
+}
+
+// Inspect resume la cadena presentada por el servidor y verifica que sea
+// valida para serverName. Con roots nil se usan las raices del sistema.
+func Inspect(certs []*x509.Certificate, serverName string, roots *x509.CertPool, now time.Time) *model.TLSInfo {
+	if len(certs) == 0 {
+		return nil
+	}
+	leaf := certs[0]
+	info := &model.TLSInfo{
+		Subject:  leaf.Subject.String(),
+		Issuer:   leaf.Issuer.String(),
+		SANs:     append([]string(nil), leaf.DNSNames...),
+		NotAfter: leaf.NotAfter,
+		DaysLeft: int(math.Floor(leaf.NotAfter.Sub(now).Hours() / 24)),
+	}
+	for _, ip := range leaf.IPAddresses {
+		info.SANs = append(info.SANs, ip.String())
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := leaf.Verify(x509.VerifyOptions{
+		DNSName:       serverName,
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+	})
+	info.ChainValid = err == nil
+	if err != nil {
+		info.ChainError = err.Error()
+	}
+	return info
+}
+
+// Evaluate decide si el certificado es aceptable y arma el mensaje del
+// resultado.
+func Evaluate(info *model.TLSInfo, minDays int) (string, bool) {
+	switch {
+	case info == nil:
+		return "el servidor no presento certificados", false
+	case info.DaysLeft < 0:
+		return fmt.Sprintf("certificado expirado hace %d dias", -info.DaysLeft), false
+	case !info.ChainValid:
+		return fmt.Sprintf("cadena invalida: %s", info.ChainError), false
+	case info.DaysLeft < minDays:
+		return fmt.Sprintf("certificado expira en %d dias (minimo %d)", info.DaysLeft, minDays), false
+	default:
+		return fmt.Sprintf("certificado valido, expira en %d dias", info.DaysLeft), true
+	}
+}
+
+// MinDays interpreta la opcion de umbral; vacio equivale a def.
+func MinDays(raw string, def int) (int, error) {
+	if raw == "" {
+		return def, nil
+	}
+	days, err := strconv.Atoi(raw)
+	if err != nil || days < 0 {
+		return 0, fmt.Errorf("dias minimos invalidos: %s", raw)
+	}
+	return days, nil
+}
@@ -0,0 +1,203 @@
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Fake es un reloj manual: la hora solo cambia con Advance o Set, y los
+// timers y tickers se disparan cuando la hora alcanza su vencimiento. Como
+// los de time, sus canales tienen capacidad 1 y descartan disparos que nadie
+// leyo.
+type Fake struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*fakeWaiter
+	changed chan struct{}
+}
+
+// NewFake crea un reloj manual detenido en start.
+func NewFake(start time.Time) *Fake {
+	return &Fake{now: start, changed: make(chan struct{})}
+}
+
+// Now devuelve la hora actual del reloj.
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Advance adelanta el reloj d y dispara, en orden, los timers que vencen.
+// Quien espera esos timers lee la hora final; para observar cada vencimiento
+// en su instante, avanzar con Next.
+func (f *Fake) Advance(d time.Duration) {
+	f.Set(f.Now().Add(d))
+}
+
+// Set mueve el reloj a t; no retrocede.
+func (f *Fake) Set(t time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for {
+		w := f.earliestLocked()
+		if w == nil || w.when.After(t) {
+			break
+		}
+		f.now = w.when
+		w.fireLocked(f)
+	}
+	if t.After(f.now) {
+		f.now = t
+	}
+}
+
+// Next devuelve el vencimiento mas proximo entre los timers y tickers
+// armados. Sirve para avanzar de a un vencimiento y dejar que quien espera
+// reaccione antes de seguir.
+func (f *Fake) Next() (time.Time, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w := f.earliestLocked()
+	if w == nil {
+		return time.Time{}, false
+	}
+	return w.when, true
+}
+
+// Waiters devuelve cuantos timers y tickers estan armados.
+func (f *Fake) Waiters() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.waiters)
+}
+
+// BlockUntil espera hasta que haya al menos n timers o tickers armados, es
+// decir, hasta que las goroutines que usan el reloj esten esperando.
+func (f *Fake) BlockUntil(n int) {
+	for {
+		f.mu.Lock()
+		if len(f.waiters) >= n {
+			f.mu.Unlock()
+			return
+		}
+		changed := f.changed
+		f.mu.Unlock()
+		<-changed
+	}
+}
+
+// NewTimer crea un timer que vence d despues de la hora actual del reloj.
+func (f *Fake) NewTimer(d time.Duration) Timer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w := &fakeWaiter{clock: f, c: make(chan time.Time, 1)}
+	f.armLocked(w, d)
+	return w
+}
+
+// NewTicker crea un ticker con periodo d.
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: periodo no positivo en NewTicker")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w := &fakeWaiter{clock: f, c: make(chan time.Time, 1), period: d}
+	f.armLocked(w, d)
+	return fakeTicker{w}
+}
+
+func (f *Fake) earliestLocked() *fakeWaiter {
+	if len(f.waiters) == 0 {
+		return nil
+	}
+	sort.SliceStable(f.waiters, func(i, j int) bool { return f.waiters[i].when.Before(f.waiters[j].when) })
+	return f.waiters[0]
+}
+
+func (f *Fake) armLocked(w *fakeWaiter, d time.Duration) {
+	w.when = f.now.Add(d)
+	if !w.armed {
+		w.armed = true
+		f.waiters = append(f.waiters, w)
+		f.broadcastLocked()
+	}
+	if d <= 0 {
+		w.fireLocked(f)
+	}
+}
+
+func (f *Fake) disarmLocked(w *fakeWaiter) bool {
+	if !w.armed {
+		return false
+	}
+	w.armed = false
+	for i, other := range f.waiters {
+		if other == w {
+			f.waiters = append(f.waiters[:i], f.waiters[i+1:]...)
+			break
+		}
+	}
+	f.broadcastLocked()
+	return true
+}
+
+// broadcastLocked despierta a los BlockUntil pendientes.
+func (f *Fake) broadcastLocked() {
+	close(f.changed)
+	f.changed = make(chan struct{})
+}
+
+// fakeWaiter implementa Timer sobre Fake y, envuelto en fakeTicker, Ticker.
+// Igual que time desde Go 1.23, Stop y Reset descartan un disparo pendiente
+// que nadie leyo.
+type fakeWaiter struct {
+	clock  *Fake
+	c      chan time.Time
+	when   time.Time
+	period time.Duration // 0 para timers
+	armed  bool
+}
+
+func (w *fakeWaiter) fireLocked(f *Fake) {
+	select {
+	case w.c <- w.when:
+	default:
+	}
+	if w.period > 0 {
+		w.when = w.when.Add(w.period)
+		return
+	}
+	f.disarmLocked(w)
+}
+
+func (w *fakeWaiter) C() <-chan time.Time { return w.c }
+
+func (w *fakeWaiter) Stop() bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+	w.drain()
+	return w.clock.disarmLocked(w)
+}
+
+func (w *fakeWaiter) Reset(d time.Duration) bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+	w.drain()
+	active := w.armed
+	w.clock.armLocked(w, d)
+	return active
+}
+
+func (w *fakeWaiter) drain() {
+	select {
+	case <-w.c:
+	default:
+	}
+}
+
+type fakeTicker struct{ *fakeWaiter }
+
+func (t fakeTicker) Stop() { t.fakeWaiter.Stop() }
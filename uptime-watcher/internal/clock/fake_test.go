@@ -0,0 +1,140 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+var epoch = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// fired devuelve el disparo pendiente de c, si hay uno.
+func fired(c <-chan time.Time) (time.Time, bool) {
+	select {
+	case at := <-c:
+		return at, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+func TestFakeAdvanceFiresInOrder(t *testing.T) {
+	f := NewFake(epoch)
+	late := f.NewTimer(3 * time.Second)
+	early := f.NewTimer(time.Second)
+	if next, ok := f.Next(); !ok || !next.Equal(epoch.Add(time.Second)) {
+		t.Fatalf("Next = %s, %v; se esperaba 1s", next.Sub(epoch), ok)
+	}
+
+	f.Advance(2 * time.Second)
+	if at, ok := fired(early.C()); !ok || !at.Equal(epoch.Add(time.Second)) {
+		t.Fatalf("el timer de 1s disparo %v en %s", ok, at.Sub(epoch))
+	}
+	if _, ok := fired(late.C()); ok {
+		t.Fatal("el timer de 3s disparo antes de tiempo")
+	}
+	if n := f.Waiters(); n != 1 {
+		t.Fatalf("Waiters = %d, se esperaba 1", n)
+	}
+
+	f.Advance(time.Second)
+	if at, ok := fired(late.C()); !ok || !at.Equal(epoch.Add(3*time.Second)) {
+		t.Fatalf("el timer de 3s disparo %v en %s", ok, at.Sub(epoch))
+	}
+	if _, ok := f.Next(); ok || f.Waiters() != 0 {
+		t.Fatal("quedaron timers armados tras dispararse")
+	}
+}
+
+func TestFakeSetDoesNotGoBack(t *testing.T) {
+	f := NewFake(epoch)
+	f.Set(epoch.Add(-time.Hour))
+	if !f.Now().Equal(epoch) {
+		t.Fatalf("Now = %s, el reloj retrocedio", f.Now())
+	}
+}
+
+func TestFakeTimerStopAndReset(t *testing.T) {
+	f := NewFake(epoch)
+	timer := f.NewTimer(time.Second)
+	if !timer.Stop() {
+		t.Fatal("Stop de un timer armado devolvio false")
+	}
+	f.Advance(time.Second)
+	if _, ok := fired(timer.C()); ok {
+		t.Fatal("un timer detenido disparo")
+	}
+
+	if timer.Reset(time.Second) {
+		t.Fatal("Reset de un timer detenido devolvio true")
+	}
+	f.Advance(time.Second)
+	// Reset descarta el disparo que nadie leyo y rearma
+	if timer.Reset(2 * time.Second) {
+		t.Fatal("Reset de un timer ya disparado devolvio true")
+	}
+	if _, ok := fired(timer.C()); ok {
+		t.Fatal("Reset no descarto el disparo pendiente")
+	}
+	f.Advance(2 * time.Second)
+	if at, ok := fired(timer.C()); !ok || !at.Equal(epoch.Add(4*time.Second)) {
+		t.Fatalf("el timer rearmado disparo %v en %s", ok, at.Sub(epoch))
+	}
+	if timer.Stop() {
+		t.Fatal("Stop de un timer ya disparado devolvio true")
+	}
+}
+
+func TestFakeTimerZeroFiresNow(t *testing.T) {
+	f := NewFake(epoch)
+	timer := f.NewTimer(0)
+	if at, ok := fired(timer.C()); !ok || !at.Equal(epoch) {
+		t.Fatalf("un timer de 0 disparo %v en %s", ok, at.Sub(epoch))
+	}
+	if f.Waiters() != 0 {
+		t.Fatal("un timer de 0 quedo armado")
+	}
+}
+
+func TestFakeTickerDropsUnreadTicks(t *testing.T) {
+	f := NewFake(epoch)
+	ticker := f.NewTicker(time.Second)
+	f.Advance(time.Second)
+	if at, ok := fired(ticker.C()); !ok || !at.Equal(epoch.Add(time.Second)) {
+		t.Fatalf("primer tick %v en %s", ok, at.Sub(epoch))
+	}
+
+	// como time.Ticker, el canal guarda un tick y descarta los demas
+	f.Advance(3 * time.Second)
+	if at, ok := fired(ticker.C()); !ok || !at.Equal(epoch.Add(2*time.Second)) {
+		t.Fatalf("tick pendiente %v en %s, se esperaba 2s", ok, at.Sub(epoch))
+	}
+	if _, ok := fired(ticker.C()); ok {
+		t.Fatal("el ticker acumulo mas de un tick")
+	}
+	if next, _ := f.Next(); !next.Equal(epoch.Add(5 * time.Second)) {
+		t.Fatalf("proximo tick en %s, se esperaba 5s", next.Sub(epoch))
+	}
+
+	ticker.Stop()
+	if f.Waiters() != 0 {
+		t.Fatal("el ticker siguio armado tras Stop")
+	}
+}
+
+func TestFakeBlockUntil(t *testing.T) {
+	f := NewFake(epoch)
+	armed := make(chan Timer)
+	go func() { armed <- f.NewTimer(time.Second) }()
+
+	f.BlockUntil(1)
+	if f.Waiters() != 1 {
+		t.Fatalf("BlockUntil volvio con %d timers armados", f.Waiters())
+	}
+	timer := <-armed
+	f.Advance(time.Second)
+	if _, ok := fired(timer.C()); !ok {
+		t.Fatal("el timer no disparo")
+	}
+	// sin timers armados vuelve enseguida para n = 0
+	f.BlockUntil(0)
+}
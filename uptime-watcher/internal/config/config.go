@@ -7,6 +7,8 @@ import (
 	"strings"
 	"time"
 
+	"proyecto-leng-paradigmas/ejemplo/internal/check"
+	"proyecto-leng-paradigmas/ejemplo/internal/cron"
 	"proyecto-leng-paradigmas/ejemplo/internal/model"
 )
 
@@ -40,6 +42,17 @@ type rawTarget struct {
 	Port      int      `json:"port"`
 	Frequency Duration `json:"frequency"`
 	Timeout   Duration `json:"timeout"`
+	Schedule  string   `json:"schedule"`
+	Timezone  string   `json:"timezone"`
+
+	Retries       int      `json:"retries"`
+	RetryInterval Duration `json:"retry_interval"`
+	Paused        bool     `json:"paused"`
+	Group         string   `json:"group"`
+	Tags          []string `json:"tags"`
+	Public        bool     `json:"public"`
+
+	Options map[string]string `json:"options"`
 }
 
 // Config representa el resultado final del parseo del archivo de configuracion.
@@ -82,20 +95,16 @@ func mapTarget(raw rawTarget) (model.Target, error) {
 		return model.Target{}, fmt.Errorf("target %q sin kind", raw.ID)
 	}
 	kind := model.TargetKind(strings.ToLower(raw.Kind))
-	switch kind {
-	case model.TargetHTTP:
-		if raw.URL == "" {
-			return model.Target{}, fmt.Errorf("target %q requiere url", raw.ID)
+	freq := time.Duration(raw.Frequency)
+	schedule := strings.TrimSpace(raw.Schedule)
+	if schedule != "" {
+		if freq != 0 {
+			return model.Target{}, fmt.Errorf("target %q: frequency y schedule son excluyentes", raw.ID)
 		}
-	case model.TargetTCP:
-		if raw.Host == "" || raw.Port == 0 {
-			return model.Target{}, fmt.Errorf("target %q requiere host y port", raw.ID)
+		if _, err := cron.Parse(schedule, raw.Timezone); err != nil {
+			return model.Target{}, fmt.Errorf("target %q: %w", raw.ID, err)
 		}
-	default:
-		return model.Target{}, fmt.Errorf("target %q tiene kind desconocido %q", raw.ID, raw.Kind)
-	}
-	freq := time.Duration(raw.Frequency)
-	if freq <= 0 {
+	} else if freq <= 0 {
 		freq = 30 * time.Second
 	}
 	timeout := time.Duration(raw.Timeout)
@@ -103,7 +112,7 @@ func mapTarget(raw rawTarget) (model.Target, error) {
 		timeout = 5 * time.Second
 	}
 
-	return model.Target{
+	target := model.Target{
 		ID:        raw.ID,
 		Name:      raw.Name,
 		Kind:      kind,
@@ -112,5 +121,22 @@ func mapTarget(raw rawTarget) (model.Target, error) {
 		Port:      raw.Port,
 		Frequency: freq,
 		Timeout:   timeout,
-	}, nil
+		Schedule:  schedule,
+		Timezone:  strings.TrimSpace(raw.Timezone),
+		Options:   raw.Options,
+
+		Retries:       raw.Retries,
+		RetryInterval: time.Duration(raw.RetryInterval),
+		Paused:        raw.Paused,
+		Group:         strings.TrimSpace(raw.Group),
+		Tags:          raw.Tags,
+		Public:        raw.Public,
+	}
+	if _, ok := check.Lookup(kind); !ok {
+		return model.Target{}, fmt.Errorf("target %q tiene kind desconocido %q", raw.ID, raw.Kind)
+	}
+	if err := check.Validate(target); err != nil {
+		return model.Target{}, fmt.Errorf("target %q: %w", raw.ID, err)
+	}
+	return target, nil
 }
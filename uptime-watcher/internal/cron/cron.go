@@ -0,0 +1,278 @@
+// Package cron interpreta expresiones cron de cinco campos (minuto, hora, dia
+// del mes, mes y dia de la semana) y calcula sus proximas ejecuciones en una
+// zona horaria.
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// horizon acota la busqueda de la proxima ejecucion. Una expresion que se
+// cumple alguna vez vuelve a cumplirse antes: el caso mas espaciado, el 29
+// de febrero, se repite a lo sumo cada 8 años.
+const horizon = 10
+
+// Schedule es una expresion cron ya interpretada.
+type Schedule struct {
+	expr    string
+	loc     *time.Location
+	minute  uint64
+	hour    uint64
+	dom     uint64
+	month   uint64
+	dow     uint64
+	domStar bool
+	dowStar bool
+}
+
+type field struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField = field{name: "minuto", min: 0, max: 59}
+	hourField   = field{name: "hora", min: 0, max: 23}
+	domField    = field{name: "dia del mes", min: 1, max: 31}
+	monthField  = field{name: "mes", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// el domingo es 0 o 7
+	dowField = field{name: "dia de la semana", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Parse interpreta expr en la zona horaria IANA timezone (UTC si esta
+// vacia). Cada campo acepta *, valores, rangos (1-5), listas (1,15) y pasos
+// (*/15, 9-17/2); mes y dia de la semana aceptan tambien nombres en ingles
+// (jan, mon). Tambien se aceptan @hourly, @daily, @weekly, @monthly y
+// @yearly. Como en cron, si se restringen dia del mes y dia de la semana
+// alcanza con que se cumpla uno de los dos.
+func Parse(expr, timezone string) (*Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, errors.New("expresion cron vacia")
+	}
+	loc := time.UTC
+	if timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(timezone); err != nil {
+			return nil, fmt.Errorf("zona horaria invalida %q", timezone)
+		}
+	}
+
+	spec := expr
+	if strings.HasPrefix(spec, "@") {
+		var ok bool
+		if spec, ok = macros[strings.ToLower(spec)]; !ok {
+			return nil, fmt.Errorf("macro cron desconocida %q", expr)
+		}
+	}
+	parts := strings.Fields(spec)
+	if len(parts) != 5 {
+		return nil, fmt.Errorf("la expresion cron %q debe tener 5 campos (minuto hora dia mes dia-de-semana), tiene %d", expr, len(parts))
+	}
+
+	s := &Schedule{expr: expr, loc: loc}
+	var err error
+	if s.minute, err = minuteField.parse(parts[0]); err != nil {
+		return nil, err
+	}
+	if s.hour, err = hourField.parse(parts[1]); err != nil {
+		return nil, err
+	}
+	if s.dom, err = domField.parse(parts[2]); err != nil {
+		return nil, err
+	}
+	if s.month, err = monthField.parse(parts[3]); err != nil {
+		return nil, err
+	}
+	if s.dow, err = dowField.parse(parts[4]); err != nil {
+		return nil, err
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	s.domStar = strings.HasPrefix(parts[2], "*")
+	s.dowStar = strings.HasPrefix(parts[4], "*")
+
+	// una expresion como "0 0 30 2 *" es valida campo a campo pero nunca se
+	// cumple
+	if s.Next(time.Date(2000, 1, 1, 0, 0, 0, 0, loc)).IsZero() {
+		return nil, fmt.Errorf("la expresion cron %q nunca se cumple", expr)
+	}
+	return s, nil
+}
+
+// String devuelve la expresion tal como se escribio.
+func (s *Schedule) String() string { return s.expr }
+
+// Next devuelve la primera ejecucion estrictamente posterior a after, en la
+// zona horaria del Schedule. Las horas que no existen por un cambio de
+// horario se saltean y las que se repiten corren una sola vez.
+func (s *Schedule) Next(after time.Time) time.Time {
+	t := after.In(s.loc).Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(horizon, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case !has(s.month, int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, s.loc)
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, s.loc)
+		case !has(s.hour, t.Hour()):
+			next := time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, s.loc)
+			if !next.After(t) {
+				// la hora se repite al atrasar el reloj
+				next = t.Add(time.Hour).Truncate(time.Minute)
+			}
+			t = next
+		case !has(s.minute, t.Minute()) || repeated(t):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// NextN devuelve las proximas n ejecuciones posteriores a after.
+func (s *Schedule) NextN(after time.Time, n int) []time.Time {
+	out := make([]time.Time, 0, n)
+	for t := after; len(out) < n; {
+		if t = s.Next(t); t.IsZero() {
+			break
+		}
+		out = append(out, t)
+	}
+	return out
+}
+
+// MinInterval devuelve la menor separacion entre las proximas n ejecuciones
+// posteriores a after.
+func (s *Schedule) MinInterval(after time.Time, n int) time.Duration {
+	runs := s.NextN(after, n)
+	var gap time.Duration
+	for i := 1; i < len(runs); i++ {
+		if d := runs[i].Sub(runs[i-1]); gap == 0 || d < gap {
+			gap = d
+		}
+	}
+	return gap
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	dom := has(s.dom, t.Day())
+	dow := has(s.dow, int(t.Weekday()))
+	if s.domStar || s.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// repeated indica si t es la segunda vez que el reloj marca esa hora y
+// minuto porque se atraso por un cambio de horario.
+func repeated(t time.Time) bool {
+	_, now := t.Zone()
+	_, before := t.Add(-3 * time.Hour).Zone()
+	if before <= now {
+		return false
+	}
+	earlier := t.Add(-time.Duration(before-now) * time.Second)
+	return earlier.Hour() == t.Hour() && earlier.Minute() == t.Minute()
+}
+
+func has(set uint64, v int) bool { return set&(1<<uint(v)) != 0 }
+
+// parse convierte un campo en el conjunto de valores que cumplen.
+func (f field) parse(raw string) (uint64, error) {
+	var set uint64
+	for _, item := range strings.Split(raw, ",") {
+		bitsOf, err := f.parseItem(item)
+		if err != nil {
+			return 0, fmt.Errorf("%s invalido %q: %w", f.name, raw, err)
+		}
+		set |= bitsOf
+	}
+	return set, nil
+}
+
+func (f field) parseItem(item string) (uint64, error) {
+	rangePart, stepPart, hasStep := strings.Cut(item, "/")
+	step := 1
+	if hasStep {
+		n, err := strconv.Atoi(stepPart)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("paso invalido %q", stepPart)
+		}
+		step = n
+	}
+
+	var lo, hi int
+	switch {
+	case rangePart == "*":
+		lo, hi = f.min, f.max
+	case strings.Contains(rangePart, "-"):
+		a, b, _ := strings.Cut(rangePart, "-")
+		var err error
+		if lo, err = f.value(a); err != nil {
+			return 0, err
+		}
+		if hi, err = f.value(b); err != nil {
+			return 0, err
+		}
+		if lo > hi {
+			return 0, fmt.Errorf("rango invertido %q", rangePart)
+		}
+	default:
+		v, err := f.value(rangePart)
+		if err != nil {
+			return 0, err
+		}
+		// "10/5" equivale a "10-max/5"
+		lo, hi = v, v
+		if hasStep {
+			hi = f.max
+		}
+	}
+
+	var set uint64
+	for v := lo; v <= hi; v += step {
+		set |= 1 << uint(v)
+	}
+	if bits.OnesCount64(set) == 0 {
+		return 0, errors.New("no incluye ningun valor")
+	}
+	return set, nil
+}
+
+func (f field) value(raw string) (int, error) {
+	if v, ok := f.names[strings.ToLower(raw)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("valor invalido %q", raw)
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("%d fuera de rango %d-%d", v, f.min, f.max)
+	}
+	return v, nil
+}
@@ -0,0 +1,175 @@
+package cron
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata" // las zonas no dependen del sistema
+)
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		expr, timezone string
+		want           string // fragmento del error
+	}{
+		{"", "", "vacia"},
+		{"* * * *", "", "5 campos"},
+		{"* * * * * *", "", "5 campos"},
+		{"@cada-rato", "", "macro cron desconocida"},
+		{"60 * * * *", "", "fuera de rango"},
+		{"* 24 * * *", "", "fuera de rango"},
+		{"* * 0 * *", "", "fuera de rango"},
+		{"* * * 13 *", "", "fuera de rango"},
+		{"* * * * 8", "", "fuera de rango"},
+		{"10-5 * * * *", "", "rango invertido"},
+		{"1-x * * * *", "", "valor invalido"},
+		{"*/0 * * * *", "", "paso invalido"},
+		{"*/-2 * * * *", "", "paso invalido"},
+		{"*/a * * * *", "", "paso invalido"},
+		{"* * * foo *", "", "valor invalido"},
+		{"0 0 30 2 *", "", "nunca se cumple"},
+		{"0 0 31 4,6,9,11 *", "", "nunca se cumple"},
+		{"0 9 * * *", "Marte/Olympus", "zona horaria invalida"},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.expr, tt.timezone)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Parse(%q, %q) = %v, se esperaba un error con %q", tt.expr, tt.timezone, err, tt.want)
+		}
+	}
+}
+
+func date(loc *time.Location, y int, m time.Month, d, h, min int) time.Time {
+	return time.Date(y, m, d, h, min, 0, 0, loc)
+}
+
+func TestNext(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	utc := time.UTC
+	tests := []struct {
+		name     string
+		expr     string
+		timezone string
+		after    time.Time
+		want     []time.Time
+	}{
+		{
+			name: "lista, rango y paso", expr: "0,30 9-17/4 * * *",
+			after: date(utc, 2026, 3, 2, 12, 0),
+			want: []time.Time{
+				date(utc, 2026, 3, 2, 13, 0), date(utc, 2026, 3, 2, 13, 30),
+				date(utc, 2026, 3, 2, 17, 0), date(utc, 2026, 3, 2, 17, 30),
+				date(utc, 2026, 3, 3, 9, 0),
+			},
+		},
+		{
+			name: "nombres y domingo como 7", expr: "0 8 * jan sun,7",
+			after: date(utc, 2026, 1, 1, 0, 0),
+			want: []time.Time{
+				date(utc, 2026, 1, 4, 8, 0), date(utc, 2026, 1, 11, 8, 0), date(utc, 2026, 1, 18, 8, 0),
+			},
+		},
+		{
+			name: "@daily", expr: "@daily",
+			after: date(utc, 2026, 3, 2, 0, 0),
+			want:  []time.Time{date(utc, 2026, 3, 3, 0, 0), date(utc, 2026, 3, 4, 0, 0)},
+		},
+		{
+			// el 1 de marzo de 2026 es domingo
+			name: "@weekly", expr: "@WEEKLY",
+			after: date(utc, 2026, 3, 1, 0, 0),
+			want:  []time.Time{date(utc, 2026, 3, 8, 0, 0), date(utc, 2026, 3, 15, 0, 0)},
+		},
+		{
+			// dia del mes y dia de la semana restringidos: alcanza con uno
+			name: "dia del mes o dia de la semana", expr: "0 0 13 * fri",
+			after: date(utc, 2026, 3, 1, 0, 0),
+			want: []time.Time{
+				date(utc, 2026, 3, 6, 0, 0), date(utc, 2026, 3, 13, 0, 0),
+				date(utc, 2026, 3, 20, 0, 0), date(utc, 2026, 3, 27, 0, 0),
+				date(utc, 2026, 4, 3, 0, 0),
+			},
+		},
+		{
+			// con * en uno de los dos campos se exigen ambos
+			name: "dia de la semana con dia del mes libre", expr: "0 0 * * fri",
+			after: date(utc, 2026, 3, 12, 0, 0),
+			want:  []time.Time{date(utc, 2026, 3, 13, 0, 0), date(utc, 2026, 3, 20, 0, 0)},
+		},
+		{
+			// 2100 no es bisiesto: el salto es de 8 años, dentro del horizonte
+			name: "29 de febrero", expr: "0 0 29 2 *",
+			after: date(utc, 2096, 3, 1, 0, 0),
+			want:  []time.Time{date(utc, 2104, 2, 29, 0, 0), date(utc, 2108, 2, 29, 0, 0)},
+		},
+		{
+			// el 8 de marzo de 2026 el reloj pasa de 2:00 a 3:00
+			name: "hora salteada al adelantar", expr: "30 2 * * *", timezone: "America/New_York",
+			after: date(ny, 2026, 3, 7, 12, 0),
+			want:  []time.Time{date(ny, 2026, 3, 9, 2, 30), date(ny, 2026, 3, 10, 2, 30)},
+		},
+		{
+			// el 1 de noviembre de 2026 el reloj vuelve de 2:00 a 1:00
+			name: "hora repetida al atrasar", expr: "30 1 * * *", timezone: "America/New_York",
+			after: date(ny, 2026, 10, 31, 12, 0),
+			want: []time.Time{
+				time.Date(2026, 11, 1, 5, 30, 0, 0, utc), // 1:30 EDT
+				time.Date(2026, 11, 2, 6, 30, 0, 0, utc), // 1:30 EST del dia siguiente
+			},
+		},
+		{
+			name: "minutos repetidos al atrasar", expr: "*/30 * * * *", timezone: "America/New_York",
+			after: date(ny, 2026, 11, 1, 0, 10),
+			want: []time.Time{
+				time.Date(2026, 11, 1, 4, 30, 0, 0, utc), // 0:30 EDT
+				time.Date(2026, 11, 1, 5, 0, 0, 0, utc),  // 1:00 EDT
+				time.Date(2026, 11, 1, 5, 30, 0, 0, utc), // 1:30 EDT
+				time.Date(2026, 11, 1, 7, 0, 0, 0, utc),  // 2:00 EST
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.expr, tt.timezone)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := s.NextN(tt.after, len(tt.want))
+			if !slices.EqualFunc(got, tt.want, time.Time.Equal) {
+				t.Fatalf("NextN = %v, se esperaba %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestNextHorizon verifica que Next deje de buscar a los 10 años en lugar
+// de iterar sin fin con una expresion que nunca se cumple.
+func TestNextHorizon(t *testing.T) {
+	// "0 0 30 2 *", que Parse rechaza
+	never := &Schedule{loc: time.UTC, minute: 1, hour: 1, dom: 1 << 30, month: 1 << 2, dow: 1<<7 - 1, dowStar: true}
+	if got := never.Next(date(time.UTC, 2026, 1, 1, 0, 0)); !got.IsZero() {
+		t.Fatalf("Next = %s, se esperaba cero", got)
+	}
+	s, err := Parse("0 0 29 2 *", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Next(date(time.UTC, 2096, 3, 1, 0, 0)); got.Year() != 2104 {
+		t.Fatalf("Next = %s, se esperaba 2104-02-29", got)
+	}
+}
+
+func TestMinInterval(t *testing.T) {
+	s, err := Parse("0 9,10 * * mon-fri", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// lunes 2 de marzo de 2026
+	if got := s.MinInterval(date(time.UTC, 2026, 3, 2, 0, 0), 5); got != time.Hour {
+		t.Fatalf("MinInterval = %s, se esperaba 1h", got)
+	}
+}
@@ -0,0 +1,165 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+)
+
+// AlertRepository persiste reglas y canales de alerta.
+type AlertRepository struct {
+	db *sql.DB
+}
+
+// NewAlertRepository crea el repositorio; el esquema lo crea Migrate.
+func NewAlertRepository(db *sql.DB) *AlertRepository {
+	return &AlertRepository{db: db}
+}
+
+// ListRules devuelve todas las reglas.
+func (r *AlertRepository) ListRules(ctx context.Context) ([]model.AlertRule, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, name, kind, target_id, threshold, latency_ns, window_ns, channel_ids, enabled
+		FROM alert_rules
+		ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo listar reglas: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []model.AlertRule
+	for rows.Next() {
+		var (
+			rule      model.AlertRule
+			kind      string
+			targetID  sql.NullString
+			latencyNS int64
+			windowNS  int64
+			channels  string
+		)
+		if err := rows.Scan(&rule.ID, &rule.Name, &kind, &targetID, &rule.Threshold, &latencyNS, &windowNS, &channels, &rule.Enabled); err != nil {
+			return nil, fmt.Errorf("fila invalida: %w", err)
+		}
+		rule.Kind = model.AlertKind(kind)
+		rule.TargetID = targetID.String
+		rule.Latency = time.Duration(latencyNS)
+		rule.Window = time.Duration(windowNS)
+		if err := json.Unmarshal([]byte(channels), &rule.ChannelIDs); err != nil {
+			return nil, fmt.Errorf("channel_ids invalidos en regla %q: %w", rule.ID, err)
+		}
+		rules = append(rules, rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// SaveRule crea o reemplaza una regla.
+func (r *AlertRepository) SaveRule(ctx context.Context, rule model.AlertRule) error {
+	channels, err := json.Marshal(nonNil(rule.ChannelIDs))
+	if err != nil {
+		return err
+	}
+	var targetID sql.NullString
+	if rule.TargetID != "" {
+		targetID = sql.NullString{String: rule.TargetID, Valid: true}
+	}
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO alert_rules (id, name, kind, target_id, threshold, latency_ns, window_ns, channel_ids, enabled)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			name = excluded.name,
+			kind = excluded.kind,
+			target_id = excluded.target_id,
+			threshold = excluded.threshold,
+			latency_ns = excluded.latency_ns,
+			window_ns = excluded.window_ns,
+			channel_ids = excluded.channel_ids,
+			enabled = excluded.enabled
+	`, rule.ID, rule.Name, string(rule.Kind), targetID, rule.Threshold, rule.Latency.Nanoseconds(), rule.Window.Nanoseconds(), string(channels), rule.Enabled)
+	if err != nil {
+		return fmt.Errorf("no se pudo guardar regla %q: %w", rule.ID, err)
+	}
+	return nil
+}
+
+// DeleteRule elimina una regla.
+func (r *AlertRepository) DeleteRule(ctx context.Context, id string) error {
+	return r.deleteByID(ctx, "alert_rules", id)
+}
+
+// ListChannels devuelve todos los canales.
+func (r *AlertRepository) ListChannels(ctx context.Context) ([]model.AlertChannel, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, name, url, headers, enabled
+		FROM alert_channels
+		ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo listar canales: %w", err)
+	}
+	defer rows.Close()
+
+	var channels []model.AlertChannel
+	for rows.Next() {
+		var (
+			ch      model.AlertChannel
+			headers string
+		)
+		if err := rows.Scan(&ch.ID, &ch.Name, &ch.URL, &headers, &ch.Enabled); err != nil {
+			return nil, fmt.Errorf("fila invalida: %w", err)
+		}
+		if err := json.Unmarshal([]byte(headers), &ch.Headers); err != nil {
+			return nil, fmt.Errorf("headers invalidos en canal %q: %w", ch.ID, err)
+		}
+		channels = append(channels, ch)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return channels, nil
+}
+
+// SaveChannel crea o reemplaza un canal.
+func (r *AlertRepository) SaveChannel(ctx context.Context, ch model.AlertChannel) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO alert_channels (id, name, url, headers, enabled)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			name = excluded.name,
+			url = excluded.url,
+			headers = excluded.headers,
+			enabled = excluded.enabled
+	`, ch.ID, ch.Name, ch.URL, encodeOptions(ch.Headers), ch.Enabled)
+	if err != nil {
+		return fmt.Errorf("no se pudo guardar canal %q: %w", ch.ID, err)
+	}
+	return nil
+}
+
+// DeleteChannel elimina un canal.
+func (r *AlertRepository) DeleteChannel(ctx context.Context, id string) error {
+	return r.deleteByID(ctx, "alert_channels", id)
+}
+
+func (r *AlertRepository) deleteByID(ctx context.Context, table, id string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM `+table+` WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("no se pudo eliminar %q de %s: %w", id, table, err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func nonNil(ids []string) []string {
+	if ids == nil {
+		return []string{}
+	}
+	return ids
+}
@@ -0,0 +1,260 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+)
+
+// AuthRepository persiste usuarios, sesiones del dashboard y tokens de API.
+// Sesiones y tokens se guardan solo como hash.
+type AuthRepository struct {
+	db *sql.DB
+}
+
+// NewAuthRepository crea el repositorio; el esquema lo crea Migrate.
+func NewAuthRepository(db *sql.DB) *AuthRepository {
+	return &AuthRepository{db: db}
+}
+
+// CreateUser agrega un usuario con su hash de password.
+func (r *AuthRepository) CreateUser(ctx context.Context, user model.User, passwordHash string) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO users (id, username, password_hash, created_at_ns)
+		VALUES (?, ?, ?, ?)
+	`, user.ID, user.Username, passwordHash, user.CreatedAt.UnixNano())
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE") {
+			return fmt.Errorf("el usuario %q ya existe", user.Username)
+		}
+		return fmt.Errorf("no se pudo crear usuario %q: %w", user.Username, err)
+	}
+	return nil
+}
+
+// UserByName devuelve un usuario y su hash de password.
+func (r *AuthRepository) UserByName(ctx context.Context, username string) (model.User, string, error) {
+	var (
+		u         model.User
+		hash      string
+		createdNS int64
+	)
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, username, password_hash, created_at_ns
+		FROM users
+		WHERE username = ?`, username).Scan(&u.ID, &u.Username, &hash, &createdNS)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.User{}, "", ErrNotFound
+	}
+	if err != nil {
+		return model.User{}, "", fmt.Errorf("no se pudo obtener usuario %q: %w", username, err)
+	}
+	u.CreatedAt = time.Unix(0, createdNS)
+	return u, hash, nil
+}
+
+// ListUsers devuelve los usuarios ordenados por nombre.
+func (r *AuthRepository) ListUsers(ctx context.Context) ([]model.User, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, username, created_at_ns
+		FROM users
+		ORDER BY username`)
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo listar usuarios: %w", err)
+	}
+	defer rows.Close()
+
+	var users []model.User
+	for rows.Next() {
+		var (
+			u         model.User
+			createdNS int64
+		)
+		if err := rows.Scan(&u.ID, &u.Username, &createdNS); err != nil {
+			return nil, fmt.Errorf("fila invalida: %w", err)
+		}
+		u.CreatedAt = time.Unix(0, createdNS)
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// SetPassword reemplaza el hash de password de un usuario y cierra sus
+// sesiones abiertas.
+func (r *AuthRepository) SetPassword(ctx context.Context, username, passwordHash string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `UPDATE users SET password_hash = ? WHERE username = ?`, passwordHash, username)
+	if err != nil {
+		return fmt.Errorf("no se pudo cambiar password de %q: %w", username, err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return ErrNotFound
+	}
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM sessions
+		WHERE user_id = (SELECT id FROM users WHERE username = ?)`, username); err != nil {
+		return fmt.Errorf("no se pudieron cerrar sesiones de %q: %w", username, err)
+	}
+	return tx.Commit()
+}
+
+// DeleteUser elimina un usuario junto con sus sesiones.
+func (r *AuthRepository) DeleteUser(ctx context.Context, username string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM users WHERE username = ?`, username)
+	if err != nil {
+		return fmt.Errorf("no se pudo eliminar usuario %q: %w", username, err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// CountUsers devuelve cuantos usuarios existen.
+func (r *AuthRepository) CountUsers(ctx context.Context) (int, error) {
+	var n int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM users`).Scan(&n); err != nil {
+		return 0, fmt.Errorf("no se pudieron contar usuarios: %w", err)
+	}
+	return n, nil
+}
+
+// CreateSession guarda una sesion del dashboard.
+func (r *AuthRepository) CreateSession(ctx context.Context, tokenHash, userID string, expiresAt time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO sessions (token_hash, user_id, expires_at_ns)
+		VALUES (?, ?, ?)
+	`, tokenHash, userID, expiresAt.UnixNano())
+	if err != nil {
+		return fmt.Errorf("no se pudo crear sesion: %w", err)
+	}
+	return nil
+}
+
+// SessionUser devuelve el usuario de una sesion vigente en now.
+func (r *AuthRepository) SessionUser(ctx context.Context, tokenHash string, now time.Time) (model.User, error) {
+	var (
+		u         model.User
+		createdNS int64
+	)
+	err := r.db.QueryRowContext(ctx, `
+		SELECT u.id, u.username, u.created_at_ns
+		FROM sessions s
+		JOIN users u ON u.id = s.user_id
+		WHERE s.token_hash = ? AND s.expires_at_ns > ?`, tokenHash, now.UnixNano()).Scan(&u.ID, &u.Username, &createdNS)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.User{}, ErrNotFound
+	}
+	if err != nil {
+		return model.User{}, fmt.Errorf("no se pudo obtener sesion: %w", err)
+	}
+	u.CreatedAt = time.Unix(0, createdNS)
+	return u, nil
+}
+
+// DeleteSession cierra una sesion.
+func (r *AuthRepository) DeleteSession(ctx context.Context, tokenHash string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM sessions WHERE token_hash = ?`, tokenHash); err != nil {
+		return fmt.Errorf("no se pudo cerrar sesion: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpiredSessions elimina las sesiones vencidas en now.
+func (r *AuthRepository) DeleteExpiredSessions(ctx context.Context, now time.Time) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM sessions WHERE expires_at_ns <= ?`, now.UnixNano()); err != nil {
+		return fmt.Errorf("no se pudieron limpiar sesiones: %w", err)
+	}
+	return nil
+}
+
+// CreateToken guarda un token de API.
+func (r *AuthRepository) CreateToken(ctx context.Context, token model.APIToken, tokenHash string) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO api_tokens (id, name, scope, token_hash, created_at_ns)
+		VALUES (?, ?, ?, ?, ?)
+	`, token.ID, token.Name, string(token.Scope), tokenHash, token.CreatedAt.UnixNano())
+	if err != nil {
+		return fmt.Errorf("no se pudo crear token %q: %w", token.Name, err)
+	}
+	return nil
+}
+
+// TokenByHash busca un token por el hash de su valor.
+func (r *AuthRepository) TokenByHash(ctx context.Context, tokenHash string) (model.APIToken, error) {
+	t, err := scanToken(r.db.QueryRowContext(ctx, `
+		SELECT id, name, scope, created_at_ns
+		FROM api_tokens
+		WHERE token_hash = ?`, tokenHash))
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.APIToken{}, ErrNotFound
+	}
+	if err != nil {
+		return model.APIToken{}, fmt.Errorf("no se pudo obtener token: %w", err)
+	}
+	return t, nil
+}
+
+// ListTokens devuelve los tokens sin su valor secreto.
+func (r *AuthRepository) ListTokens(ctx context.Context) ([]model.APIToken, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, name, scope, created_at_ns
+		FROM api_tokens
+		ORDER BY created_at_ns, id`)
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo listar tokens: %w", err)
+	}
+	defer rows.Close()
+
+	var tokens []model.APIToken
+	for rows.Next() {
+		t, err := scanToken(rows)
+		if err != nil {
+			return nil, fmt.Errorf("fila invalida: %w", err)
+		}
+		tokens = append(tokens, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// DeleteToken revoca un token.
+func (r *AuthRepository) DeleteToken(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM api_tokens WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("no se pudo eliminar token %q: %w", id, err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func scanToken(row rowScanner) (model.APIToken, error) {
+	var (
+		t         model.APIToken
+		scope     string
+		createdNS int64
+	)
+	if err := row.Scan(&t.ID, &t.Name, &scope, &createdNS); err != nil {
+		return model.APIToken{}, err
+	}
+	t.Scope = model.TokenScope(scope)
+	t.CreatedAt = time.Unix(0, createdNS)
+	return t, nil
+}
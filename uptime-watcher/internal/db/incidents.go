@@ -0,0 +1,125 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+)
+
+// IncidentFilter restringe la consulta de incidentes. Un incidente entra en
+// el rango si estuvo abierto en algun momento entre From y To.
+type IncidentFilter struct {
+	TargetID string
+	From     time.Time
+	To       time.Time
+	OnlyOpen bool
+	Limit    int
+}
+
+// IncidentRepository persiste los incidentes detectados.
+type IncidentRepository struct {
+	db *sql.DB
+}
+
+// NewIncidentRepository crea el repositorio; el esquema lo crea Migrate.
+func NewIncidentRepository(db *sql.DB) *IncidentRepository {
+	return &IncidentRepository{db: db}
+}
+
+// Open registra un nuevo incidente y retorna su id.
+func (r *IncidentRepository) Open(ctx context.Context, incident model.Incident) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `
+		INSERT INTO incidents (target_id, started_at_ns, first_error)
+		VALUES (?, ?, ?)
+	`, incident.TargetID, incident.StartedAt.UnixNano(), incident.FirstError)
+	if err != nil {
+		return 0, fmt.Errorf("no se pudo abrir incidente de %q: %w", incident.TargetID, err)
+	}
+	return res.LastInsertId()
+}
+
+// Resolve cierra un incidente abierto.
+func (r *IncidentRepository) Resolve(ctx context.Context, id int64, at time.Time) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE incidents SET resolved_at_ns = ?
+		WHERE id = ? AND resolved_at_ns IS NULL
+	`, at.UnixNano(), id)
+	if err != nil {
+		return fmt.Errorf("no se pudo cerrar incidente %d: %w", id, err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// List devuelve los incidentes que cumplen el filtro, del mas reciente al mas
+// antiguo.
+func (r *IncidentRepository) List(ctx context.Context, filter IncidentFilter) ([]model.Incident, error) {
+	var (
+		where []string
+		args  []any
+	)
+	if filter.TargetID != "" {
+		where = append(where, "target_id = ?")
+		args = append(args, filter.TargetID)
+	}
+	if !filter.From.IsZero() {
+		where = append(where, "(resolved_at_ns IS NULL OR resolved_at_ns >= ?)")
+		args = append(args, filter.From.UnixNano())
+	}
+	if !filter.To.IsZero() {
+		where = append(where, "started_at_ns <= ?")
+		args = append(args, filter.To.UnixNano())
+	}
+	if filter.OnlyOpen {
+		where = append(where, "resolved_at_ns IS NULL")
+	}
+	query := `
+		SELECT id, target_id, started_at_ns, resolved_at_ns, first_error
+		FROM incidents`
+	if len(where) > 0 {
+		query += ` WHERE ` + strings.Join(where, " AND ")
+	}
+	query += ` ORDER BY started_at_ns DESC`
+	if filter.Limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo listar incidentes: %w", err)
+	}
+	defer rows.Close()
+
+	now := time.Now()
+	var incidents []model.Incident
+	for rows.Next() {
+		var (
+			inc      model.Incident
+			started  int64
+			resolved sql.NullInt64
+		)
+		if err := rows.Scan(&inc.ID, &inc.TargetID, &started, &resolved, &inc.FirstError); err != nil {
+			return nil, fmt.Errorf("fila invalida: %w", err)
+		}
+		inc.StartedAt = time.Unix(0, started)
+		end := now
+		if resolved.Valid {
+			at := time.Unix(0, resolved.Int64)
+			inc.ResolvedAt = &at
+			end = at
+		}
+		inc.Duration = end.Sub(inc.StartedAt)
+		incidents = append(incidents, inc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return incidents, nil
+}
@@ -0,0 +1,125 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+)
+
+// MaintenanceRepository persiste las ventanas de mantenimiento.
+type MaintenanceRepository struct {
+	db *sql.DB
+}
+
+// NewMaintenanceRepository crea el repositorio; el esquema lo crea Migrate.
+func NewMaintenanceRepository(db *sql.DB) *MaintenanceRepository {
+	return &MaintenanceRepository{db: db}
+}
+
+// List devuelve todas las ventanas ordenadas por inicio.
+func (r *MaintenanceRepository) List(ctx context.Context) ([]model.MaintenanceWindow, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, name, target_ids, groups_json, start_ns, duration_ns, repeat
+		FROM maintenance_windows
+		ORDER BY start_ns, id`)
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo listar ventanas de mantenimiento: %w", err)
+	}
+	defer rows.Close()
+
+	var windows []model.MaintenanceWindow
+	for rows.Next() {
+		var (
+			w          model.MaintenanceWindow
+			targetIDs  string
+			groups     string
+			startNS    int64
+			durationNS int64
+			repeat     string
+		)
+		if err := rows.Scan(&w.ID, &w.Name, &targetIDs, &groups, &startNS, &durationNS, &repeat); err != nil {
+			return nil, fmt.Errorf("fila invalida: %w", err)
+		}
+		w.Start = time.Unix(0, startNS).UTC()
+		w.Duration = time.Duration(durationNS)
+		w.Repeat = model.MaintenanceRepeat(repeat)
+		if err := json.Unmarshal([]byte(targetIDs), &w.TargetIDs); err != nil {
+			return nil, fmt.Errorf("target_ids invalidos en ventana %q: %w", w.ID, err)
+		}
+		if err := json.Unmarshal([]byte(groups), &w.Groups); err != nil {
+			return nil, fmt.Errorf("groups invalidos en ventana %q: %w", w.ID, err)
+		}
+		windows = append(windows, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return windows, nil
+}
+
+// Save crea o reemplaza una ventana.
+func (r *MaintenanceRepository) Save(ctx context.Context, w model.MaintenanceWindow) error {
+	targetIDs, err := json.Marshal(nonNil(w.TargetIDs))
+	if err != nil {
+		return err
+	}
+	groups, err := json.Marshal(nonNil(w.Groups))
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO maintenance_windows (id, name, target_ids, groups_json, start_ns, duration_ns, repeat)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			name = excluded.name,
+			target_ids = excluded.target_ids,
+			groups_json = excluded.groups_json,
+			start_ns = excluded.start_ns,
+			duration_ns = excluded.duration_ns,
+			repeat = excluded.repeat
+	`, w.ID, w.Name, string(targetIDs), string(groups), w.Start.UnixNano(), w.Duration.Nanoseconds(), string(w.Repeat))
+	if err != nil {
+		return fmt.Errorf("no se pudo guardar ventana %q: %w", w.ID, err)
+	}
+	return nil
+}
+
+// Update reemplaza una ventana existente; ErrNotFound si no existe.
+func (r *MaintenanceRepository) Update(ctx context.Context, w model.MaintenanceWindow) error {
+	targetIDs, err := json.Marshal(nonNil(w.TargetIDs))
+	if err != nil {
+		return err
+	}
+	groups, err := json.Marshal(nonNil(w.Groups))
+	if err != nil {
+		return err
+	}
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE maintenance_windows
+		SET name = ?, target_ids = ?, groups_json = ?, start_ns = ?, duration_ns = ?, repeat = ?
+		WHERE id = ?
+	`, w.Name, string(targetIDs), string(groups), w.Start.UnixNano(), w.Duration.Nanoseconds(), string(w.Repeat), w.ID)
+	if err != nil {
+		return fmt.Errorf("no se pudo actualizar ventana %q: %w", w.ID, err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Delete elimina una ventana.
+func (r *MaintenanceRepository) Delete(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM maintenance_windows WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("no se pudo eliminar ventana %q: %w", id, err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,471 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Migration es un cambio de esquema numerado. Up y Down corren dentro de una
+// transaccion junto con el registro en schema_migrations, asi que una
+// migracion se aplica entera o no se aplica.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(tx *sql.Tx) error
+	Down    func(tx *sql.Tx) error
+}
+
+// MigrationStatus indica si una migracion esta aplicada.
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+// migrations es el historial del esquema, en orden. Las primeras versiones
+// usan CREATE TABLE IF NOT EXISTS y addColumns, que no fallan si el cambio ya
+// existe: asi las bases creadas antes de este sistema se adoptan aplicando
+// todo el historial. Nunca modificar una migracion publicada; agregar una
+// nueva al final.
+var migrations = []Migration{
+	{
+		Version: 1,
+		Name:    "create_targets",
+		Up: execSQL(`
+		CREATE TABLE IF NOT EXISTS targets (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			kind TEXT NOT NULL,
+			url TEXT,
+			host TEXT,
+			port INTEGER,
+			frequency_ns INTEGER NOT NULL,
+			timeout_ns INTEGER NOT NULL,
+			created_at TEXT NOT NULL DEFAULT (datetime('now')),
+			updated_at TEXT NOT NULL DEFAULT (datetime('now'))
+		);`),
+		Down: execSQL(`DROP TABLE IF EXISTS targets;`),
+	},
+	{
+		Version: 2,
+		Name:    "create_check_results",
+		Up: execSQL(`
+		CREATE TABLE IF NOT EXISTS check_results (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			target_id TEXT NOT NULL REFERENCES targets(id) ON DELETE CASCADE,
+			checked_at_ns INTEGER NOT NULL,
+			duration_ns INTEGER NOT NULL,
+			success INTEGER NOT NULL,
+			message TEXT NOT NULL DEFAULT '',
+			status_code INTEGER NOT NULL DEFAULT 0
+		);
+		CREATE INDEX IF NOT EXISTS idx_check_results_target_time
+			ON check_results (target_id, checked_at_ns);`),
+		Down: execSQL(`DROP TABLE IF EXISTS check_results;`),
+	},
+	{
+		Version: 3,
+		Name:    "add_target_options",
+		Up:      addColumns("targets", column{"options", `TEXT NOT NULL DEFAULT '{}'`}),
+		Down:    dropColumns("targets", "options"),
+	},
+	{
+		Version: 4,
+		Name:    "add_check_results_tls",
+		Up:      addColumns("check_results", column{"tls_json", "TEXT"}),
+		Down:    dropColumns("check_results", "tls_json"),
+	},
+	{
+		Version: 5,
+		Name:    "add_check_results_timing",
+		Up:      addColumns("check_results", column{"timing_json", "TEXT"}),
+		Down:    dropColumns("check_results", "timing_json"),
+	},
+	{
+		Version: 6,
+		Name:    "create_incidents",
+		Up: execSQL(`
+		CREATE TABLE IF NOT EXISTS incidents (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			target_id TEXT NOT NULL REFERENCES targets(id) ON DELETE CASCADE,
+			started_at_ns INTEGER NOT NULL,
+			resolved_at_ns INTEGER,
+			first_error TEXT NOT NULL DEFAULT ''
+		);
+		CREATE INDEX IF NOT EXISTS idx_incidents_target_start
+			ON incidents (target_id, started_at_ns);`),
+		Down: execSQL(`DROP TABLE IF EXISTS incidents;`),
+	},
+	{
+		Version: 7,
+		Name:    "create_alerts",
+		Up: execSQL(`
+		CREATE TABLE IF NOT EXISTS alert_channels (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			url TEXT NOT NULL,
+			headers TEXT NOT NULL DEFAULT '{}',
+			enabled INTEGER NOT NULL DEFAULT 1,
+			created_at TEXT NOT NULL DEFAULT (datetime('now'))
+		);
+		CREATE TABLE IF NOT EXISTS alert_rules (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			kind TEXT NOT NULL,
+			target_id TEXT REFERENCES targets(id) ON DELETE CASCADE,
+			threshold INTEGER NOT NULL DEFAULT 0,
+			latency_ns INTEGER NOT NULL DEFAULT 0,
+			window_ns INTEGER NOT NULL DEFAULT 0,
+			channel_ids TEXT NOT NULL DEFAULT '[]',
+			enabled INTEGER NOT NULL DEFAULT 1,
+			created_at TEXT NOT NULL DEFAULT (datetime('now'))
+		);`),
+		Down: execSQL(`
+		DROP TABLE IF EXISTS alert_rules;
+		DROP TABLE IF EXISTS alert_channels;`),
+	},
+	{
+		Version: 8,
+		Name:    "add_retries",
+		Up: steps(
+			addColumns("targets",
+				column{"retries", `INTEGER NOT NULL DEFAULT 0`},
+				column{"retry_interval_ns", `INTEGER NOT NULL DEFAULT 0`}),
+			addColumns("check_results", column{"attempts", `INTEGER NOT NULL DEFAULT 1`}),
+		),
+		Down: steps(
+			dropColumns("check_results", "attempts"),
+			dropColumns("targets", "retry_interval_ns", "retries"),
+		),
+	},
+	{
+		Version: 9,
+		Name:    "create_maintenance_windows",
+		Up: steps(
+			execSQL(`
+			CREATE TABLE IF NOT EXISTS maintenance_windows (
+				id TEXT PRIMARY KEY,
+				name TEXT NOT NULL,
+				target_ids TEXT NOT NULL DEFAULT '[]',
+				start_ns INTEGER NOT NULL,
+				duration_ns INTEGER NOT NULL,
+				repeat TEXT NOT NULL DEFAULT '',
+				created_at TEXT NOT NULL DEFAULT (datetime('now'))
+			);`),
+			addColumns("check_results", column{"maintenance", `INTEGER NOT NULL DEFAULT 0`}),
+		),
+		Down: steps(
+			dropColumns("check_results", "maintenance"),
+			execSQL(`DROP TABLE IF EXISTS maintenance_windows;`),
+		),
+	},
+	{
+		Version: 10,
+		Name:    "add_target_paused",
+		Up:      addColumns("targets", column{"paused", `INTEGER NOT NULL DEFAULT 0`}),
+		Down:    dropColumns("targets", "paused"),
+	},
+	{
+		Version: 11,
+		Name:    "add_groups_and_tags",
+		Up: steps(
+			addColumns("targets",
+				column{"group_name", `TEXT NOT NULL DEFAULT ''`},
+				column{"tags", `TEXT NOT NULL DEFAULT '[]'`}),
+			addColumns("maintenance_windows", column{"groups_json", `TEXT NOT NULL DEFAULT '[]'`}),
+		),
+		Down: steps(
+			dropColumns("maintenance_windows", "groups_json"),
+			dropColumns("targets", "tags", "group_name"),
+		),
+	},
+	{
+		Version: 12,
+		Name:    "add_target_public",
+		Up:      addColumns("targets", column{"public", `INTEGER NOT NULL DEFAULT 0`}),
+		Down:    dropColumns("targets", "public"),
+	},
+	{
+		Version: 13,
+		Name:    "create_auth",
+		Up: execSQL(`
+		CREATE TABLE IF NOT EXISTS users (
+			id TEXT PRIMARY KEY,
+			username TEXT NOT NULL UNIQUE,
+			password_hash TEXT NOT NULL,
+			created_at_ns INTEGER NOT NULL
+		);
+		CREATE TABLE IF NOT EXISTS sessions (
+			token_hash TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			expires_at_ns INTEGER NOT NULL
+		);
+		CREATE TABLE IF NOT EXISTS api_tokens (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			scope TEXT NOT NULL,
+			token_hash TEXT NOT NULL UNIQUE,
+			created_at_ns INTEGER NOT NULL
+		);`),
+		Down: execSQL(`
+		DROP TABLE IF EXISTS api_tokens;
+		DROP TABLE IF EXISTS sessions;
+		DROP TABLE IF EXISTS users;`),
+	},
+	{
+		Version: 14,
+		Name:    "add_target_schedule",
+		Up: addColumns("targets",
+			column{"schedule", `TEXT NOT NULL DEFAULT ''`},
+			column{"timezone", `TEXT NOT NULL DEFAULT ''`}),
+		Down: dropColumns("targets", "timezone", "schedule"),
+	},
+	{
+		// las estadisticas por ventana filtran por fecha sobre todos los targets
+		Version: 15,
+		Name:    "add_check_results_time_index",
+		Up: execSQL(`
+		CREATE INDEX IF NOT EXISTS idx_check_results_time
+			ON check_results (checked_at_ns);`),
+		Down: execSQL(`DROP INDEX IF EXISTS idx_check_results_time;`),
+	},
+}
+
+// Migrate aplica todas las migraciones pendientes.
+func Migrate(ctx context.Context, db *sql.DB) ([]Migration, error) {
+	return MigrateUp(ctx, db, 0)
+}
+
+// MigrateUp aplica hasta n migraciones pendientes en orden; con n <= 0 aplica
+// todas. Devuelve las aplicadas.
+func MigrateUp(ctx context.Context, db *sql.DB, n int) ([]Migration, error) {
+	applied, err := appliedVersions(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	var done []Migration
+	for _, m := range migrations {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+		if n > 0 && len(done) == n {
+			break
+		}
+		if err := runMigration(ctx, db, m, true); err != nil {
+			return done, err
+		}
+		done = append(done, m)
+	}
+	return done, nil
+}
+
+// MigrateDown revierte las ultimas n migraciones aplicadas (al menos una).
+// Devuelve las revertidas.
+func MigrateDown(ctx context.Context, db *sql.DB, n int) ([]Migration, error) {
+	if n <= 0 {
+		n = 1
+	}
+	applied, err := appliedVersions(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	var done []Migration
+	for i := len(migrations) - 1; i >= 0 && len(done) < n; i-- {
+		m := migrations[i]
+		if _, ok := applied[m.Version]; !ok {
+			continue
+		}
+		if err := runMigration(ctx, db, m, false); err != nil {
+			return done, err
+		}
+		done = append(done, m)
+	}
+	return done, nil
+}
+
+// MigrationsStatus lista todas las migraciones conocidas y cuando se
+// aplicaron.
+func MigrationsStatus(ctx context.Context, db *sql.DB) ([]MigrationStatus, error) {
+	applied, err := appliedVersions(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		st := MigrationStatus{Version: m.Version, Name: m.Name}
+		if at, ok := applied[m.Version]; ok {
+			st.AppliedAt = &at
+		}
+		out = append(out, st)
+	}
+	return out, nil
+}
+
+// PendingMigrations devuelve cuantas migraciones faltan aplicar.
+func PendingMigrations(ctx context.Context, db *sql.DB) (int, error) {
+	applied, err := appliedVersions(ctx, db)
+	if err != nil {
+		return 0, err
+	}
+	pending := 0
+	for _, m := range migrations {
+		if _, ok := applied[m.Version]; !ok {
+			pending++
+		}
+	}
+	return pending, nil
+}
+
+// appliedVersions crea schema_migrations si hace falta y devuelve las
+// versiones aplicadas. Falla si la base tiene versiones que este binario no
+// conoce, para no operar sobre un esquema mas nuevo.
+func appliedVersions(ctx context.Context, db *sql.DB) (map[int]time.Time, error) {
+	if _, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at_ns INTEGER NOT NULL
+		)`); err != nil {
+		return nil, fmt.Errorf("no se pudo crear tabla schema_migrations: %w", err)
+	}
+	rows, err := db.QueryContext(ctx, `SELECT version, applied_at_ns FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("no se pudieron leer migraciones aplicadas: %w", err)
+	}
+	defer rows.Close()
+
+	known := make(map[int]bool, len(migrations))
+	for _, m := range migrations {
+		known[m.Version] = true
+	}
+	applied := make(map[int]time.Time)
+	var unknown []int
+	for rows.Next() {
+		var version int
+		var appliedNS int64
+		if err := rows.Scan(&version, &appliedNS); err != nil {
+			return nil, fmt.Errorf("fila invalida: %w", err)
+		}
+		applied[version] = time.Unix(0, appliedNS)
+		if !known[version] {
+			unknown = append(unknown, version)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(unknown) > 0 {
+		sort.Ints(unknown)
+		return nil, fmt.Errorf("la base tiene migraciones desconocidas %v: fue creada por una version mas nueva del monitor", unknown)
+	}
+	return applied, nil
+}
+
+func runMigration(ctx context.Context, db *sql.DB, m Migration, up bool) error {
+	direction, fn := "up", m.Up
+	if !up {
+		direction, fn = "down", m.Down
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return fmt.Errorf("migracion %d_%s (%s) fallo: %w", m.Version, m.Name, direction, err)
+	}
+	if up {
+		_, err = tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, name, applied_at_ns) VALUES (?, ?, ?)`,
+			m.Version, m.Name, time.Now().UnixNano())
+	} else {
+		_, err = tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = ?`, m.Version)
+	}
+	if err != nil {
+		return fmt.Errorf("no se pudo registrar migracion %d: %w", m.Version, err)
+	}
+	return tx.Commit()
+}
+
+// column es una columna a agregar con su definicion SQL.
+type column struct {
+	name       string
+	definition string
+}
+
+func execSQL(stmts string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		_, err := tx.Exec(stmts)
+		return err
+	}
+}
+
+func steps(fns ...func(tx *sql.Tx) error) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		for _, fn := range fns {
+			if err := fn(tx); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// addColumns agrega las columnas que falten; las que ya existen (bases
+// anteriores a las migraciones) se dejan como estan.
+func addColumns(table string, cols ...column) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		existing, err := tableColumns(tx, table)
+		if err != nil {
+			return err
+		}
+		for _, c := range cols {
+			if existing[c.name] {
+				continue
+			}
+			if _, err := tx.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, table, c.name, c.definition)); err != nil {
+				return fmt.Errorf("no se pudo agregar columna %s.%s: %w", table, c.name, err)
+			}
+		}
+		return nil
+	}
+}
+
+func dropColumns(table string, names ...string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		existing, err := tableColumns(tx, table)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			if !existing[name] {
+				continue
+			}
+			if _, err := tx.Exec(fmt.Sprintf(`ALTER TABLE %s DROP COLUMN %s`, table, name)); err != nil {
+				return fmt.Errorf("no se pudo eliminar columna %s.%s: %w", table, name, err)
+			}
+		}
+		return nil
+	}
+}
+
+func tableColumns(tx *sql.Tx, table string) (map[string]bool, error) {
+	rows, err := tx.Query(`SELECT name FROM pragma_table_info(?)`, table)
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo inspeccionar tabla %s: %w", table, err)
+	}
+	defer rows.Close()
+	cols := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		cols[strings.ToLower(name)] = true
+	}
+	return cols, rows.Err()
+}
@@ -0,0 +1,241 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+)
+
+func openMemoryDB(t *testing.T) *sql.DB {
+	t.Helper()
+	sqlDB, err := OpenSQLite(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	return sqlDB
+}
+
+// schemaOf describe el esquema como tabla o indice -> columnas, para comparar
+// bases sin depender del texto de los CREATE.
+func schemaOf(t *testing.T, sqlDB *sql.DB) map[string][]string {
+	t.Helper()
+	rows, err := sqlDB.Query(`
+		SELECT type, name FROM sqlite_master
+		WHERE name NOT LIKE 'sqlite_%' AND name != 'schema_migrations'`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type object struct{ kind, name string }
+	var objects []object
+	for rows.Next() {
+		var o object
+		if err := rows.Scan(&o.kind, &o.name); err != nil {
+			t.Fatal(err)
+		}
+		objects = append(objects, o)
+	}
+	rows.Close()
+
+	schema := make(map[string][]string)
+	for _, o := range objects {
+		pragma := `SELECT name FROM pragma_table_info(?)`
+		if o.kind == "index" {
+			pragma = `SELECT name FROM pragma_index_info(?)`
+		}
+		cols, err := sqlDB.Query(pragma, o.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for cols.Next() {
+			var name string
+			if err := cols.Scan(&name); err != nil {
+				t.Fatal(err)
+			}
+			names = append(names, name)
+		}
+		cols.Close()
+		slices.Sort(names)
+		schema[o.kind+" "+o.name] = names
+	}
+	return schema
+}
+
+// expectApplied verifica que MigrationsStatus y PendingMigrations marquen
+// aplicadas exactamente las primeras n migraciones.
+func expectApplied(t *testing.T, sqlDB *sql.DB, n int) {
+	t.Helper()
+	ctx := context.Background()
+	status, err := MigrationsStatus(ctx, sqlDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status) != len(migrations) {
+		t.Fatalf("MigrationsStatus devolvio %d migraciones, se esperaban %d", len(status), len(migrations))
+	}
+	for i, st := range status {
+		if st.Version != migrations[i].Version || st.Name != migrations[i].Name {
+			t.Fatalf("MigrationsStatus[%d] = %d_%s, fuera de orden", i, st.Version, st.Name)
+		}
+		if applied := st.AppliedAt != nil; applied != (i < n) {
+			t.Errorf("migracion %d_%s aplicada = %v, se esperaba %v", st.Version, st.Name, applied, i < n)
+		}
+	}
+	pending, err := PendingMigrations(ctx, sqlDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending != len(migrations)-n {
+		t.Errorf("PendingMigrations = %d, se esperaba %d", pending, len(migrations)-n)
+	}
+}
+
+func versions(ms []Migration) []int {
+	out := make([]int, len(ms))
+	for i, m := range ms {
+		out[i] = m.Version
+	}
+	return out
+}
+
+func TestMigrationVersionsAreOrdered(t *testing.T) {
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			t.Fatalf("migracion %d_%s en la posicion %d: las versiones deben ser 1, 2, 3...", m.Version, m.Name, i)
+		}
+		if m.Up == nil || m.Down == nil {
+			t.Fatalf("migracion %d_%s sin Up o Down", m.Version, m.Name)
+		}
+	}
+}
+
+// TestMigrateUpDownUp aplica todo el historial sobre una base vacia, lo
+// revierte entero (incluidos los DROP COLUMN) y lo vuelve a aplicar.
+func TestMigrateUpDownUp(t *testing.T) {
+	sqlDB := openMemoryDB(t)
+	ctx := context.Background()
+	expectApplied(t, sqlDB, 0)
+
+	// de a pasos: MigrateUp respeta n y MigrateDown revierte la ultima
+	done, err := MigrateUp(ctx, sqlDB, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := versions(done); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Fatalf("MigrateUp(3) aplico %v", got)
+	}
+	expectApplied(t, sqlDB, 3)
+	done, err = MigrateDown(ctx, sqlDB, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := versions(done); !slices.Equal(got, []int{3}) {
+		t.Fatalf("MigrateDown(0) revirtio %v, se esperaba solo la ultima", got)
+	}
+	expectApplied(t, sqlDB, 2)
+	if cols := schemaOf(t, sqlDB)["table targets"]; slices.Contains(cols, "options") {
+		t.Fatalf("targets conserva options tras revertir la migracion 3: %v", cols)
+	}
+
+	if _, err := Migrate(ctx, sqlDB); err != nil {
+		t.Fatal(err)
+	}
+	expectApplied(t, sqlDB, len(migrations))
+	migrated := schemaOf(t, sqlDB)
+	if again, err := Migrate(ctx, sqlDB); err != nil || len(again) != 0 {
+		t.Fatalf("Migrate sobre una base al dia aplico %v, %v", versions(again), err)
+	}
+
+	done, err = MigrateDown(ctx, sqlDB, len(migrations)+5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := versions(migrations)
+	slices.Reverse(want)
+	if got := versions(done); !slices.Equal(got, want) {
+		t.Fatalf("MigrateDown revirtio %v, se esperaba %v", got, want)
+	}
+	expectApplied(t, sqlDB, 0)
+	if left := schemaOf(t, sqlDB); len(left) != 0 {
+		t.Fatalf("quedaron objetos tras revertir todo: %v", slices.Collect(maps.Keys(left)))
+	}
+
+	if _, err := Migrate(ctx, sqlDB); err != nil {
+		t.Fatal(err)
+	}
+	expectApplied(t, sqlDB, len(migrations))
+	if again := schemaOf(t, sqlDB); !maps.EqualFunc(again, migrated, slices.Equal) {
+		t.Fatalf("el esquema tras down y up difiere:\n%v\nse esperaba\n%v", again, migrated)
+	}
+}
+
+// TestMigrateAdoptsBaseline parte del esquema que creaba el monitor antes de
+// las migraciones, con datos, y verifica que se adopte sin perderlos.
+func TestMigrateAdoptsBaseline(t *testing.T) {
+	sqlDB := openMemoryDB(t)
+	ctx := context.Background()
+	if _, err := sqlDB.Exec(`
+		CREATE TABLE targets (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			kind TEXT NOT NULL,
+			url TEXT,
+			host TEXT,
+			port INTEGER,
+			frequency_ns INTEGER NOT NULL,
+			timeout_ns INTEGER NOT NULL,
+			created_at TEXT NOT NULL DEFAULT (datetime('now')),
+			updated_at TEXT NOT NULL DEFAULT (datetime('now'))
+		);
+		INSERT INTO targets (id, name, kind, url, frequency_ns, timeout_ns)
+			VALUES ('web', 'Web', 'http', 'https://example.com', 30000000000, 5000000000);
+		INSERT INTO targets (id, name, kind, host, port, frequency_ns, timeout_ns)
+			VALUES ('db', 'DB', 'tcp', 'db.internal', 5432, 60000000000, 2000000000);`); err != nil {
+		t.Fatal(err)
+	}
+	expectApplied(t, sqlDB, 0)
+
+	if _, err := Migrate(ctx, sqlDB); err != nil {
+		t.Fatal(err)
+	}
+	expectApplied(t, sqlDB, len(migrations))
+
+	fresh := openMemoryDB(t)
+	if _, err := Migrate(ctx, fresh); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := schemaOf(t, sqlDB), schemaOf(t, fresh); !maps.EqualFunc(got, want, slices.Equal) {
+		t.Fatalf("el esquema adoptado difiere del nuevo:\n%v\nse esperaba\n%v", got, want)
+	}
+
+	targets := NewTargetRepository(sqlDB)
+	web, err := targets.Get(ctx, "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if web.Name != "Web" || web.URL != "https://example.com" || web.Frequency != 30*time.Second || web.Timeout != 5*time.Second {
+		t.Errorf("web = %+v", web)
+	}
+	if web.Paused || web.Public || web.Retries != 0 || len(web.Options) != 0 || len(web.Tags) != 0 {
+		t.Errorf("web no tomo los valores por defecto de las columnas nuevas: %+v", web)
+	}
+	tcp, err := targets.Get(ctx, "db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tcp.Kind != model.TargetTCP || tcp.Host != "db.internal" || tcp.Port != 5432 {
+		t.Errorf("db = %+v", tcp)
+	}
+
+	// la base adoptada acepta las escrituras del esquema actual
+	results := NewResultRepository(sqlDB)
+	if err := results.Insert(ctx, model.CheckResult{TargetID: "web", CheckedAt: time.Now(), Success: true, Attempts: 1}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,231 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+)
+
+// ResultRepository persiste el historial de chequeos en SQLite.
+type ResultRepository struct {
+	db *sql.DB
+}
+
+// NewResultRepository crea el repositorio; el esquema lo crea Migrate.
+func NewResultRepository(db *sql.DB) *ResultRepository {
+	return &ResultRepository{db: db}
+}
+
+// Insert guarda un resultado puntual.
+func (r *ResultRepository) Insert(ctx context.Context, result model.CheckResult) error {
+	tlsJSON, err := encodeJSON(result.TLS)
+	if err != nil {
+		return err
+	}
+	timingJSON, err := encodeJSON(result.Timing)
+	if err != nil {
+		return err
+	}
+	attempts := result.Attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO check_results (target_id, checked_at_ns, duration_ns, success, message, status_code, tls_json, timing_json, attempts, maintenance)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, result.TargetID, result.CheckedAt.UnixNano(), result.Duration.Nanoseconds(), result.Success, result.Message, result.StatusCode, tlsJSON, timingJSON, attempts, result.Maintenance)
+	if err != nil {
+		return fmt.Errorf("no se pudo guardar resultado de %q: %w", result.TargetID, err)
+	}
+	return nil
+}
+
+// List devuelve los resultados de un target en el rango [from, to], del mas
+// reciente al mas antiguo. Las fechas en cero no limitan el rango y un limit
+// menor o igual a 0 devuelve todas las filas.
+func (r *ResultRepository) List(ctx context.Context, targetID string, from, to time.Time, limit int) ([]model.CheckResult, error) {
+	var (
+		where = []string{"target_id = ?"}
+		args  = []any{targetID}
+	)
+	if !from.IsZero() {
+		where = append(where, "checked_at_ns >= ?")
+		args = append(args, from.UnixNano())
+	}
+	if !to.IsZero() {
+		where = append(where, "checked_at_ns <= ?")
+		args = append(args, to.UnixNano())
+	}
+	query := `
+		SELECT target_id, checked_at_ns, duration_ns, success, message, status_code, tls_json, timing_json, attempts, maintenance
+		FROM check_results
+		WHERE ` + strings.Join(where, " AND ") + `
+		ORDER BY checked_at_ns DESC`
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo listar historial de %q: %w", targetID, err)
+	}
+	defer rows.Close()
+
+	var results []model.CheckResult
+	for rows.Next() {
+		var (
+			res        model.CheckResult
+			checkedAt  int64
+			durationNS int64
+			tlsJSON    sql.NullString
+			timingJSON sql.NullString
+		)
+		if err := rows.Scan(&res.TargetID, &checkedAt, &durationNS, &res.Success, &res.Message, &res.StatusCode, &tlsJSON, &timingJSON, &res.Attempts, &res.Maintenance); err != nil {
+			return nil, fmt.Errorf("fila invalida: %w", err)
+		}
+		res.CheckedAt = time.Unix(0, checkedAt)
+		res.Duration = time.Duration(durationNS)
+		if err := decodeJSON(tlsJSON, &res.TLS); err != nil {
+			return nil, fmt.Errorf("tls_json invalido: %w", err)
+		}
+		if err := decodeJSON(timingJSON, &res.Timing); err != nil {
+			return nil, fmt.Errorf("timing_json invalido: %w", err)
+		}
+		results = append(results, res)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// encodeJSON serializa valores opcionales; nil se guarda como NULL.
+func encodeJSON[T any](v *T) (sql.NullString, error) {
+	if v == nil {
+		return sql.NullString{}, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("no se pudo serializar: %w", err)
+	}
+	return sql.NullString{String: string(b), Valid: true}, nil
+}
+
+// decodeJSON es el inverso de encodeJSON.
+func decodeJSON[T any](raw sql.NullString, dst **T) error {
+	if !raw.Valid || raw.String == "" {
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal([]byte(raw.String), &v); err != nil {
+		return err
+	}
+	*dst = &v
+	return nil
+}
+
+// windowStatsQuery calcula en SQLite, para todos los targets a la vez, la
+// cantidad de chequeos, el uptime y los percentiles nearest-rank de latencia
+// de los exitosos desde un instante, sin contar los tomados en mantenimiento.
+// El rango de nearest-rank, ceil(p/100*n), se calcula en enteros como
+// (p*n+99)/100.
+const windowStatsQuery = `
+	WITH w AS (
+		SELECT target_id, duration_ns, success
+		FROM check_results
+		WHERE checked_at_ns >= ? AND maintenance = 0
+	),
+	ranked AS (
+		SELECT target_id, duration_ns,
+			ROW_NUMBER() OVER (PARTITION BY target_id ORDER BY duration_ns) AS rn,
+			COUNT(*) OVER (PARTITION BY target_id) AS n
+		FROM w
+		WHERE success = 1
+	),
+	pct AS (
+		SELECT target_id,
+			MAX(CASE WHEN rn = (50*n+99)/100 THEN duration_ns END) AS p50,
+			MAX(CASE WHEN rn = (90*n+99)/100 THEN duration_ns END) AS p90,
+			MAX(CASE WHEN rn = (95*n+99)/100 THEN duration_ns END) AS p95,
+			MAX(CASE WHEN rn = (99*n+99)/100 THEN duration_ns END) AS p99
+		FROM ranked
+		GROUP BY target_id
+	)
+	SELECT c.target_id, c.checks, c.ok,
+		COALESCE(p.p50, 0), COALESCE(p.p90, 0), COALESCE(p.p95, 0), COALESCE(p.p99, 0)
+	FROM (
+		SELECT target_id, COUNT(*) AS checks, SUM(success) AS ok
+		FROM w
+		GROUP BY target_id
+	) c
+	LEFT JOIN pct p ON p.target_id = c.target_id`
+
+// WindowStats devuelve las estadisticas de la ventana name (desde since) de
+// cada target con resultados en ella. La agregacion ocurre en SQLite: solo
+// vuelve una fila por target, sin importar cuantas muestras haya.
+func (r *ResultRepository) WindowStats(ctx context.Context, name string, since time.Time) (map[string]model.WindowStats, error) {
+	rows, err := r.db.QueryContext(ctx, windowStatsQuery, since.UnixNano())
+	if err != nil {
+		return nil, fmt.Errorf("no se pudieron calcular estadisticas de %s: %w", name, err)
+	}
+	defer rows.Close()
+
+	out := make(map[string]model.WindowStats)
+	for rows.Next() {
+		var (
+			id                 string
+			ok                 int
+			p50, p90, p95, p99 int64
+		)
+		stats := model.WindowStats{Window: name}
+		if err := rows.Scan(&id, &stats.Checks, &ok, &p50, &p90, &p95, &p99); err != nil {
+			return nil, fmt.Errorf("fila invalida: %w", err)
+		}
+		if stats.Checks > 0 {
+			stats.UptimePerc = float64(ok) / float64(stats.Checks) * 100
+		}
+		stats.P50, stats.P90 = time.Duration(p50), time.Duration(p90)
+		stats.P95, stats.P99 = time.Duration(p95), time.Duration(p99)
+		out[id] = stats
+	}
+	return out, rows.Err()
+}
+
+// Daily agrupa por dia UTC los resultados de un target desde since, sin
+// contar los obtenidos en mantenimiento.
+func (r *ResultRepository) Daily(ctx context.Context, targetID string, since time.Time) ([]model.DailyUptime, error) {
+	const day = int64(24 * time.Hour)
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT checked_at_ns / ? AS day, COUNT(*), SUM(success)
+		FROM check_results
+		WHERE target_id = ? AND checked_at_ns >= ? AND maintenance = 0
+		GROUP BY day
+		ORDER BY day`, day, targetID, since.UnixNano())
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo agrupar historial de %q: %w", targetID, err)
+	}
+	defer rows.Close()
+
+	var days []model.DailyUptime
+	for rows.Next() {
+		var (
+			d     model.DailyUptime
+			index int64
+		)
+		if err := rows.Scan(&index, &d.Checks, &d.Successes); err != nil {
+			return nil, fmt.Errorf("fila invalida: %w", err)
+		}
+		d.Date = time.Unix(0, index*day).UTC()
+		days = append(days, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return days, nil
+}
@@ -0,0 +1,93 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+)
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	sqlDB, err := OpenSQLite(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	if _, err := Migrate(context.Background(), sqlDB); err != nil {
+		t.Fatal(err)
+	}
+	return sqlDB
+}
+
+func TestWindowStats(t *testing.T) {
+	sqlDB := openTestDB(t)
+	ctx := context.Background()
+	targets := NewTargetRepository(sqlDB)
+	for _, id := range []string{"a", "b", "c"} {
+		if err := targets.Create(ctx, model.Target{ID: id, Name: id, Kind: model.TargetHTTP}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	results := NewResultRepository(sqlDB)
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	insert := func(id string, ago time.Duration, d time.Duration, success, maintenance bool) {
+		t.Helper()
+		err := results.Insert(ctx, model.CheckResult{
+			TargetID: id, CheckedAt: now.Add(-ago), Duration: d, Success: success, Maintenance: maintenance,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	// a: 10 exitosos de 1ms a 10ms y 2 fallos dentro de la ventana
+	for i := 1; i <= 10; i++ {
+		insert("a", time.Duration(i)*time.Minute, time.Duration(i)*time.Millisecond, true, false)
+	}
+	insert("a", 20*time.Minute, time.Second, false, false)
+	insert("a", 21*time.Minute, time.Second, false, false)
+	// fuera de la ventana y en mantenimiento: no cuentan
+	insert("a", 2*time.Hour, time.Hour, true, false)
+	insert("a", 5*time.Minute, time.Hour, false, true)
+	// b: solo fallos
+	insert("b", time.Minute, time.Second, false, false)
+	// c: sin resultados en la ventana
+	insert("c", 3*time.Hour, time.Millisecond, true, false)
+
+	stats, err := results.WindowStats(ctx, "1h", now.Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := stats["a"]
+	if a.Window != "1h" || a.Checks != 12 {
+		t.Fatalf("a = %+v, se esperaban 12 chequeos en 1h", a)
+	}
+	if want := 10.0 / 12 * 100; math.Abs(a.UptimePerc-want) > 1e-9 {
+		t.Errorf("a uptime = %v, se esperaba %v", a.UptimePerc, want)
+	}
+	// nearest-rank sobre 10 valores: p50 es el 5to, p90 el 9no, p95 y p99 el 10mo
+	want := map[string][2]time.Duration{
+		"p50": {a.P50, 5 * time.Millisecond},
+		"p90": {a.P90, 9 * time.Millisecond},
+		"p95": {a.P95, 10 * time.Millisecond},
+		"p99": {a.P99, 10 * time.Millisecond},
+	}
+	for name, v := range want {
+		if v[0] != v[1] {
+			t.Errorf("a %s = %s, se esperaba %s", name, v[0], v[1])
+		}
+	}
+
+	if b := stats["b"]; b.Checks != 1 || b.UptimePerc != 0 || b.P50 != 0 {
+		t.Errorf("b = %+v, se esperaba 1 chequeo fallido sin latencias", b)
+	}
+	if c, ok := stats["c"]; ok {
+		t.Errorf("c = %+v, no deberia tener estadisticas en 1h", c)
+	}
+}
@@ -3,6 +3,7 @@ package db
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -39,40 +40,69 @@ type TargetRepository struct {
 	db *sql.DB
 }
 
-// NewTargetRepository inicializa la tabla necesaria para almacenar targets.
-func NewTargetRepository(db *sql.DB) (*TargetRepository, error) {
-	repo := &TargetRepository{db: db}
-	if err := repo.migrate(); err != nil {
-		return nil, err
+// NewTargetRepository crea el repositorio; el esquema lo crea Migrate.
+func NewTargetRepository(db *sql.DB) *TargetRepository {
+	return &TargetRepository{db: db}
+}
+
+const targetColumns = `id, name, kind, url, host, port, frequency_ns, timeout_ns, schedule, timezone, retries, retry_interval_ns, paused, group_name, tags, public, options`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTarget(row rowScanner) (model.Target, error) {
+	var (
+		t       model.Target
+		kind    string
+		url     sql.NullString
+		host    sql.NullString
+		port    sql.NullInt64
+		freqNS  int64
+		timeout int64
+		retryNS int64
+		tags    string
+		options string
+	)
+	if err := row.Scan(&t.ID, &t.Name, &kind, &url, &host, &port, &freqNS, &timeout, &t.Schedule, &t.Timezone, &t.Retries, &retryNS, &t.Paused, &t.Group, &tags, &t.Public, &options); err != nil {
+		return model.Target{}, err
+	}
+	t.Kind = model.TargetKind(kind)
+	t.URL = url.String
+	t.Host = host.String
+	t.Port = int(port.Int64)
+	t.Frequency = time.Duration(freqNS)
+	t.Timeout = time.Duration(timeout)
+	t.RetryInterval = time.Duration(retryNS)
+	if err := json.Unmarshal([]byte(options), &t.Options); err != nil {
+		return model.Target{}, fmt.Errorf("options invalidas en target %q: %w", t.ID, err)
 	}
-	return repo, nil
+	if err := json.Unmarshal([]byte(tags), &t.Tags); err != nil {
+		return model.Target{}, fmt.Errorf("tags invalidas en target %q: %w", t.ID, err)
+	}
+	if len(t.Tags) == 0 {
+		t.Tags = nil
+	}
+	return t, nil
+}
+
+func encodeTags(tags []string) string {
+	b, _ := json.Marshal(nonNil(tags))
+	return string(b)
 }
 
-func (r *TargetRepository) migrate() error {
-	const schema = `
-	CREATE TABLE IF NOT EXISTS targets (
-		id TEXT PRIMARY KEY,
-		name TEXT NOT NULL,
-		kind TEXT NOT NULL,
-		url TEXT,
-		host TEXT,
-		port INTEGER,
-		frequency_ns INTEGER NOT NULL,
-		timeout_ns INTEGER NOT NULL,
-		created_at TEXT NOT NULL DEFAULT (datetime('now')),
-		updated_at TEXT NOT NULL DEFAULT (datetime('now'))
-	);
-	`
-	if _, err := r.db.Exec(schema); err != nil {
-		return fmt.Errorf("no se pudo crear tabla targets: %w", err)
+func encodeOptions(options map[string]string) string {
+	if len(options) == 0 {
+		return "{}"
 	}
-	return nil
+	b, _ := json.Marshal(options)
+	return string(b)
 }
 
 // List devuelve todos los targets almacenados.
 func (r *TargetRepository) List(ctx context.Context) ([]model.Target, error) {
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, name, kind, url, host, port, frequency_ns, timeout_ns
+		SELECT `+targetColumns+`
 		FROM targets
 		ORDER BY id`)
 	if err != nil {
@@ -82,18 +112,10 @@ func (r *TargetRepository) List(ctx context.Context) ([]model.Target, error) {
 
 	var targets []model.Target
 	for rows.Next() {
-		var (
-			t       model.Target
-			kind    string
-			freqNS  int64
-			timeout int64
-		)
-		if err := rows.Scan(&t.ID, &t.Name, &kind, &t.URL, &t.Host, &t.Port, &freqNS, &timeout); err != nil {
+		t, err := scanTarget(rows)
+		if err != nil {
 			return nil, fmt.Errorf("fila invalida: %w", err)
 		}
-		t.Kind = model.TargetKind(kind)
-		t.Frequency = time.Duration(freqNS)
-		t.Timeout = time.Duration(timeout)
 		targets = append(targets, t)
 	}
 	if err := rows.Err(); err != nil {
@@ -104,47 +126,39 @@ func (r *TargetRepository) List(ctx context.Context) ([]model.Target, error) {
 
 // Get recupera un target especifico.
 func (r *TargetRepository) Get(ctx context.Context, id string) (model.Target, error) {
-	var (
-		t       model.Target
-		kind    string
-		freqNS  int64
-		timeout int64
-	)
-	err := r.db.QueryRowContext(ctx, `
-		SELECT id, name, kind, url, host, port, frequency_ns, timeout_ns
+	t, err := scanTarget(r.db.QueryRowContext(ctx, `
+		SELECT `+targetColumns+`
 		FROM targets
-		WHERE id = ?`, id).Scan(&t.ID, &t.Name, &kind, &t.URL, &t.Host, &t.Port, &freqNS, &timeout)
+		WHERE id = ?`, id))
 	if errors.Is(err, sql.ErrNoRows) {
 		return model.Target{}, ErrNotFound
 	}
 	if err != nil {
 		return model.Target{}, fmt.Errorf("no se pudo obtener target %q: %w", id, err)
 	}
-	t.Kind = model.TargetKind(kind)
-	t.Frequency = time.Duration(freqNS)
-	t.Timeout = time.Duration(timeout)
 	return t, nil
 }
 
 // Create agrega un nuevo target.
 func (r *TargetRepository) Create(ctx context.Context, target model.Target) error {
 	_, err := r.db.ExecContext(ctx, `
-		INSERT INTO targets (id, name, kind, url, host, port, frequency_ns, timeout_ns)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
-	`, target.ID, target.Name, string(target.Kind), target.URL, target.Host, target.Port, target.Frequency.Nanoseconds(), target.Timeout.Nanoseconds())
+		INSERT INTO targets (id, name, kind, url, host, port, frequency_ns, timeout_ns, schedule, timezone, retries, retry_interval_ns, paused, group_name, tags, public, options)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, target.ID, target.Name, string(target.Kind), target.URL, target.Host, target.Port, target.Frequency.Nanoseconds(), target.Timeout.Nanoseconds(), target.Schedule, target.Timezone, target.Retries, target.RetryInterval.Nanoseconds(), target.Paused, target.Group, encodeTags(target.Tags), target.Public, encodeOptions(target.Options))
 	if err != nil {
 		return fmt.Errorf("no se pudo crear target %q: %w", target.ID, err)
 	}
 	return nil
 }
 
-// Update modifica un target existente.
+// Update modifica la configuracion de un target existente. El estado de pausa
+// solo cambia con SetPaused.
 func (r *TargetRepository) Update(ctx context.Context, target model.Target) error {
 	res, err := r.db.ExecContext(ctx, `
 		UPDATE targets
-		SET name = ?, kind = ?, url = ?, host = ?, port = ?, frequency_ns = ?, timeout_ns = ?, updated_at = datetime('now')
+		SET name = ?, kind = ?, url = ?, host = ?, port = ?, frequency_ns = ?, timeout_ns = ?, schedule = ?, timezone = ?, retries = ?, retry_interval_ns = ?, group_name = ?, tags = ?, public = ?, options = ?, updated_at = datetime('now')
 		WHERE id = ?
-	`, target.Name, string(target.Kind), target.URL, target.Host, target.Port, target.Frequency.Nanoseconds(), target.Timeout.Nanoseconds(), target.ID)
+	`, target.Name, string(target.Kind), target.URL, target.Host, target.Port, target.Frequency.Nanoseconds(), target.Timeout.Nanoseconds(), target.Schedule, target.Timezone, target.Retries, target.RetryInterval.Nanoseconds(), target.Group, encodeTags(target.Tags), target.Public, encodeOptions(target.Options), target.ID)
 	if err != nil {
 		return fmt.Errorf("no se pudo actualizar target %q: %w", target.ID, err)
 	}
@@ -158,8 +172,8 @@ func (r *TargetRepository) Update(ctx context.Context, target model.Target) erro
 // Upsert crea o actualiza segun exista el registro.
 func (r *TargetRepository) Upsert(ctx context.Context, target model.Target) error {
 	_, err := r.db.ExecContext(ctx, `
-		INSERT INTO targets (id, name, kind, url, host, port, frequency_ns, timeout_ns)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO targets (id, name, kind, url, host, port, frequency_ns, timeout_ns, schedule, timezone, retries, retry_interval_ns, paused, group_name, tags, public, options)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(id) DO UPDATE SET
 			name = excluded.name,
 			kind = excluded.kind,
@@ -168,14 +182,39 @@ func (r *TargetRepository) Upsert(ctx context.Context, target model.Target) erro
 			port = excluded.port,
 			frequency_ns = excluded.frequency_ns,
 			timeout_ns = excluded.timeout_ns,
+			schedule = excluded.schedule,
+			timezone = excluded.timezone,
+			retries = excluded.retries,
+			retry_interval_ns = excluded.retry_interval_ns,
+			paused = excluded.paused,
+			group_name = excluded.group_name,
+			tags = excluded.tags,
+			public = excluded.public,
+			options = excluded.options,
 			updated_at = datetime('now')
-	`, target.ID, target.Name, string(target.Kind), target.URL, target.Host, target.Port, target.Frequency.Nanoseconds(), target.Timeout.Nanoseconds())
+	`, target.ID, target.Name, string(target.Kind), target.URL, target.Host, target.Port, target.Frequency.Nanoseconds(), target.Timeout.Nanoseconds(), target.Schedule, target.Timezone, target.Retries, target.RetryInterval.Nanoseconds(), target.Paused, target.Group, encodeTags(target.Tags), target.Public, encodeOptions(target.Options))
 	if err != nil {
 		return fmt.Errorf("no se pudo upsert target %q: %w", target.ID, err)
 	}
 	return nil
 }
 
+// SetPaused pausa o reanuda un target.
+func (r *TargetRepository) SetPaused(ctx context.Context, id string, paused bool) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE targets
+		SET paused = ?, updated_at = datetime('now')
+		WHERE id = ?
+	`, paused, id)
+	if err != nil {
+		return fmt.Errorf("no se pudo cambiar pausa de %q: %w", id, err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
 // Delete elimina un target.
 func (r *TargetRepository) Delete(ctx context.Context, id string) error {
 	res, err := r.db.ExecContext(ctx, `DELETE FROM targets WHERE id = ?`, id)
@@ -0,0 +1,183 @@
+// Package incident detecta caidas a partir de las transiciones de estado de
+// los targets y las persiste como incidentes.
+package incident
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/db"
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+)
+
+// DefaultThreshold es la cantidad de fallos seguidos que abre un incidente.
+const DefaultThreshold = 3
+
+// Logger define interfaz minima para registrar eventos.
+type Logger interface {
+	Printf(format string, v ...any)
+}
+
+// Repository es la persistencia requerida por el Tracker.
+type Repository interface {
+	Open(ctx context.Context, incident model.Incident) (int64, error)
+	Resolve(ctx context.Context, id int64, at time.Time) error
+	List(ctx context.Context, filter db.IncidentFilter) ([]model.Incident, error)
+}
+
+// targetState sigue la racha de fallos actual de un target. mu serializa los
+// resultados del target, incluida la escritura del incidente, sin frenar a
+// los demas targets.
+type targetState struct {
+	mu         sync.Mutex
+	failures   int
+	firstAt    time.Time
+	firstError string
+	openID     int64
+}
+
+// Tracker abre un incidente cuando un target acumula Threshold fallos seguidos
+// y lo cierra con el primer chequeo exitoso. Implementa scheduler.Observer.
+type Tracker struct {
+	repo      Repository
+	threshold int
+	logger    Logger
+
+	mu     sync.Mutex // protege el mapa, no el estado de cada target
+	states map[string]*targetState
+}
+
+// NewTracker crea un Tracker; threshold menor a 1 usa DefaultThreshold.
+func NewTracker(repo Repository, threshold int, logger Logger) *Tracker {
+	if threshold < 1 {
+		threshold = DefaultThreshold
+	}
+	if logger == nil {
+		logger = noopLogger{}
+	}
+	return &Tracker{
+		repo:      repo,
+		threshold: threshold,
+		logger:    logger,
+		states:    make(map[string]*targetState),
+	}
+}
+
+// Load recupera los incidentes que quedaron abiertos antes de un reinicio.
+func (t *Tracker) Load(ctx context.Context) error {
+	open, err := t.repo.List(ctx, db.IncidentFilter{OnlyOpen: true})
+	if err != nil {
+		return err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, inc := range open {
+		t.states[inc.TargetID] = &targetState{
+			failures:   t.threshold,
+			firstAt:    inc.StartedAt,
+			firstError: inc.FirstError,
+			openID:     inc.ID,
+		}
+	}
+	return nil
+}
+
+// Observe implementa scheduler.Observer. Lo llaman los workers del
+// scheduler en paralelo: solo se bloquea el target observado, asi la
+// escritura de un incidente no demora los resultados de otros targets.
+func (t *Tracker) Observe(ctx context.Context, target model.Target, result model.CheckResult) {
+	// en mantenimiento no se abren incidentes ni se cuentan fallos
+	if result.Maintenance {
+		return
+	}
+
+	st := t.state(target.ID)
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if result.Success {
+		if st.openID != 0 {
+			if err := t.repo.Resolve(ctx, st.openID, result.CheckedAt); err != nil {
+				t.logger.Printf("target %s: no se pudo cerrar incidente %d: %v", target.ID, st.openID, err)
+			} else {
+				t.logger.Printf("target %s: incidente %d resuelto tras %s", target.ID, st.openID, result.CheckedAt.Sub(st.firstAt).Round(time.Second))
+			}
+		}
+		st.reset()
+		return
+	}
+
+	if st.failures == 0 {
+		st.firstAt = result.CheckedAt
+		st.firstError = result.Message
+	}
+	st.failures++
+	if st.openID != 0 || st.failures < t.threshold {
+		return
+	}
+	id, err := t.repo.Open(ctx, model.Incident{
+		TargetID:   target.ID,
+		StartedAt:  st.firstAt,
+		FirstError: st.firstError,
+	})
+	if err != nil {
+		t.logger.Printf("target %s: no se pudo abrir incidente: %v", target.ID, err)
+		return
+	}
+	st.openID = id
+	t.logger.Printf("target %s: incidente %d abierto (%s)", target.ID, id, st.firstError)
+}
+
+func (t *Tracker) state(targetID string) *targetState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	st, ok := t.states[targetID]
+	if !ok {
+		st = &targetState{}
+		t.states[targetID] = st
+	}
+	return st
+}
+
+func (st *targetState) reset() {
+	st.failures, st.firstAt, st.firstError, st.openID = 0, time.Time{}, "", 0
+}
+
+// Resolve cierra en at el incidente abierto de un target, si lo hay, y
+// descarta su racha de fallos. Se usa al pausar un target: sin chequeos no
+// llegaria el exito que lo cierra.
+func (t *Tracker) Resolve(ctx context.Context, targetID string, at time.Time) error {
+	t.mu.Lock()
+	st, ok := t.states[targetID]
+	t.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.openID != 0 {
+		if err := t.repo.Resolve(ctx, st.openID, at); err != nil {
+			return err
+		}
+		t.logger.Printf("target %s: incidente %d resuelto al pausar tras %s", targetID, st.openID, at.Sub(st.firstAt).Round(time.Second))
+	}
+	st.reset()
+	return nil
+}
+
+// Forget descarta el estado de un target eliminado.
+func (t *Tracker) Forget(targetID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.states, targetID)
+}
+
+// List consulta los incidentes persistidos.
+func (t *Tracker) List(ctx context.Context, filter db.IncidentFilter) ([]model.Incident, error) {
+	return t.repo.List(ctx, filter)
+}
+
+type noopLogger struct{}
+
+func (noopLogger) Printf(string, ...any) {}
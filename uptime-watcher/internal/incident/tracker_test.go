@@ -0,0 +1,193 @@
+package incident
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/db"
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+)
+
+// memRepo guarda incidentes en memoria; si block no es nil, Open del target
+// indicado espera a que se cierre.
+type memRepo struct {
+	mu        sync.Mutex
+	nextID    int64
+	incidents map[int64]model.Incident
+
+	blockTarget string
+	block       chan struct{}
+	entered     chan struct{}
+}
+
+func newMemRepo() *memRepo {
+	return &memRepo{incidents: make(map[int64]model.Incident)}
+}
+
+func (r *memRepo) Open(ctx context.Context, inc model.Incident) (int64, error) {
+	if r.block != nil && inc.TargetID == r.blockTarget {
+		r.entered <- struct{}{}
+		<-r.block
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	inc.ID = r.nextID
+	r.incidents[inc.ID] = inc
+	return inc.ID, nil
+}
+
+func (r *memRepo) Resolve(ctx context.Context, id int64, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	inc := r.incidents[id]
+	inc.ResolvedAt = &at
+	r.incidents[id] = inc
+	return nil
+}
+
+func (r *memRepo) List(ctx context.Context, filter db.IncidentFilter) ([]model.Incident, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []model.Incident
+	for _, inc := range r.incidents {
+		if filter.OnlyOpen && inc.ResolvedAt != nil {
+			continue
+		}
+		out = append(out, inc)
+	}
+	return out, nil
+}
+
+var t0 = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func result(success bool, at time.Duration) model.CheckResult {
+	return model.CheckResult{Success: success, CheckedAt: t0.Add(at), Message: "caido"}
+}
+
+func TestOpenAndResolve(t *testing.T) {
+	repo := newMemRepo()
+	tr := NewTracker(repo, 3, nil)
+	target := model.Target{ID: "a"}
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		tr.Observe(ctx, target, result(false, time.Duration(i)*time.Minute))
+	}
+	if len(repo.incidents) != 0 {
+		t.Fatal("se abrio un incidente antes del umbral")
+	}
+	tr.Observe(ctx, target, result(false, 2*time.Minute))
+	open, _ := repo.List(ctx, db.IncidentFilter{OnlyOpen: true})
+	if len(open) != 1 || !open[0].StartedAt.Equal(t0) {
+		t.Fatalf("incidentes abiertos = %+v, se esperaba uno desde el primer fallo", open)
+	}
+	tr.Observe(ctx, target, result(false, 3*time.Minute))
+	if len(repo.incidents) != 1 {
+		t.Fatal("un fallo mas abrio otro incidente")
+	}
+
+	tr.Observe(ctx, target, result(true, 4*time.Minute))
+	open, _ = repo.List(ctx, db.IncidentFilter{OnlyOpen: true})
+	if len(open) != 0 {
+		t.Fatalf("quedaron incidentes abiertos: %+v", open)
+	}
+}
+
+func TestMaintenanceIgnored(t *testing.T) {
+	repo := newMemRepo()
+	tr := NewTracker(repo, 2, nil)
+	target := model.Target{ID: "a"}
+	for i := 0; i < 5; i++ {
+		res := result(false, time.Duration(i)*time.Minute)
+		res.Maintenance = true
+		tr.Observe(context.Background(), target, res)
+	}
+	if len(repo.incidents) != 0 {
+		t.Fatal("los fallos en mantenimiento abrieron un incidente")
+	}
+}
+
+func TestLoadResumesOpenIncident(t *testing.T) {
+	repo := newMemRepo()
+	ctx := context.Background()
+	id, _ := repo.Open(ctx, model.Incident{TargetID: "a", StartedAt: t0})
+	tr := NewTracker(repo, 3, nil)
+	if err := tr.Load(ctx); err != nil {
+		t.Fatal(err)
+	}
+	tr.Observe(ctx, model.Target{ID: "a"}, result(true, time.Minute))
+	if repo.incidents[id].ResolvedAt == nil {
+		t.Fatal("el incidente recuperado no se cerro")
+	}
+}
+
+func TestResolveOnPause(t *testing.T) {
+	repo := newMemRepo()
+	tr := NewTracker(repo, 2, nil)
+	target := model.Target{ID: "a"}
+	ctx := context.Background()
+
+	// sin estado previo no hay nada que resolver
+	if err := tr.Resolve(ctx, "b", t0); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		tr.Observe(ctx, target, result(false, time.Duration(i)*time.Minute))
+	}
+	if err := tr.Resolve(ctx, "a", t0.Add(10*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	open, _ := repo.List(ctx, db.IncidentFilter{OnlyOpen: true})
+	if len(open) != 0 {
+		t.Fatalf("quedaron incidentes abiertos: %+v", open)
+	}
+	if at := repo.incidents[1].ResolvedAt; at == nil || !at.Equal(t0.Add(10*time.Minute)) {
+		t.Fatalf("ResolvedAt = %v, se esperaba el momento de la pausa", at)
+	}
+
+	// al reanudar la racha empieza de cero: un fallo no reabre el incidente
+	tr.Observe(ctx, target, result(false, 20*time.Minute))
+	if len(repo.incidents) != 1 {
+		t.Fatalf("incidentes = %d, el fallo previo a la pausa siguio contando", len(repo.incidents))
+	}
+}
+
+// TestSlowWriteDoesNotBlockOtherTargets verifica que mientras se persiste el
+// incidente de un target los resultados de otros se procesen igual.
+func TestSlowWriteDoesNotBlockOtherTargets(t *testing.T) {
+	repo := newMemRepo()
+	repo.blockTarget = "lento"
+	repo.block = make(chan struct{})
+	repo.entered = make(chan struct{})
+	tr := NewTracker(repo, 1, nil)
+	ctx := context.Background()
+
+	done := make(chan struct{})
+	go func() {
+		tr.Observe(ctx, model.Target{ID: "lento"}, result(false, 0))
+		close(done)
+	}()
+	<-repo.entered
+
+	other := make(chan struct{})
+	go func() {
+		tr.Observe(ctx, model.Target{ID: "otro"}, result(false, 0))
+		tr.Observe(ctx, model.Target{ID: "otro"}, result(true, time.Minute))
+		close(other)
+	}()
+	select {
+	case <-other:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Observe de otro target quedo bloqueado por la escritura lenta")
+	}
+
+	close(repo.block)
+	<-done
+	open, _ := repo.List(ctx, db.IncidentFilter{OnlyOpen: true})
+	if len(open) != 1 || open[0].TargetID != "lento" {
+		t.Fatalf("incidentes abiertos = %+v", open)
+	}
+}
@@ -0,0 +1,166 @@
+// Package maintenance mantiene las ventanas de mantenimiento y responde si un
+// target esta en mantenimiento en un instante dado.
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+)
+
+// Repository es la persistencia de las ventanas.
+type Repository interface {
+	List(ctx context.Context) ([]model.MaintenanceWindow, error)
+	Save(ctx context.Context, w model.MaintenanceWindow) error
+	Update(ctx context.Context, w model.MaintenanceWindow) error
+	Delete(ctx context.Context, id string) error
+}
+
+// Schedule guarda en memoria las ventanas persistidas.
+type Schedule struct {
+	repo Repository
+
+	mu      sync.RWMutex
+	windows []model.MaintenanceWindow
+}
+
+// NewSchedule crea un Schedule vacio; Load trae las ventanas persistidas.
+func NewSchedule(repo Repository) *Schedule {
+	return &Schedule{repo: repo}
+}
+
+// Load recarga las ventanas desde el repositorio.
+func (s *Schedule) Load(ctx context.Context) error {
+	windows, err := s.repo.List(ctx)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.windows = windows
+	return nil
+}
+
+// Windows devuelve todas las ventanas.
+func (s *Schedule) Windows() []model.MaintenanceWindow {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]model.MaintenanceWindow(nil), s.windows...)
+}
+
+// InMaintenance indica si el target esta en mantenimiento en at.
+func (s *Schedule) InMaintenance(target model.Target, at time.Time) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, w := range s.windows {
+		if w.Covers(target) && w.ActiveAt(at) {
+			return true
+		}
+	}
+	return false
+}
+
+// Upcoming devuelve las ventanas en curso o futuras ordenadas por su proximo
+// inicio; las ventanas unicas ya terminadas se omiten.
+func (s *Schedule) Upcoming(at time.Time) []model.MaintenanceWindow {
+	type entry struct {
+		window model.MaintenanceWindow
+		next   time.Time
+	}
+	s.mu.RLock()
+	entries := make([]entry, 0, len(s.windows))
+	for _, w := range s.windows {
+		if next, ok := w.NextStart(at); ok {
+			entries = append(entries, entry{w, next})
+		}
+	}
+	s.mu.RUnlock()
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].next.Before(entries[j].next) })
+	out := make([]model.MaintenanceWindow, len(entries))
+	for i, e := range entries {
+		out[i] = e.window
+	}
+	return out
+}
+
+// Save valida, persiste y activa una ventana. Sin id se genera uno nuevo.
+func (s *Schedule) Save(ctx context.Context, w model.MaintenanceWindow) (model.MaintenanceWindow, error) {
+	if w.ID == "" {
+		w.ID = uuid.NewString()
+	}
+	w, err := normalize(w)
+	if err != nil {
+		return model.MaintenanceWindow{}, err
+	}
+	if err := s.repo.Save(ctx, w); err != nil {
+		return model.MaintenanceWindow{}, err
+	}
+	return w, s.Load(ctx)
+}
+
+// Update reemplaza una ventana existente. Si el id no existe devuelve el
+// error del repositorio (db.ErrNotFound) en lugar de crearla.
+func (s *Schedule) Update(ctx context.Context, w model.MaintenanceWindow) (model.MaintenanceWindow, error) {
+	if w.ID == "" {
+		return model.MaintenanceWindow{}, errors.New("id requerido")
+	}
+	w, err := normalize(w)
+	if err != nil {
+		return model.MaintenanceWindow{}, err
+	}
+	if err := s.repo.Update(ctx, w); err != nil {
+		return model.MaintenanceWindow{}, err
+	}
+	return w, s.Load(ctx)
+}
+
+// Delete elimina una ventana.
+func (s *Schedule) Delete(ctx context.Context, id string) error {
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	return s.Load(ctx)
+}
+
+// normalize valida la ventana y la deja como se persiste.
+func normalize(w model.MaintenanceWindow) (model.MaintenanceWindow, error) {
+	if err := validate(w); err != nil {
+		return model.MaintenanceWindow{}, err
+	}
+	w.Start = w.Start.UTC()
+	if w.TargetIDs == nil {
+		w.TargetIDs = []string{}
+	}
+	if w.Groups == nil {
+		w.Groups = []string{}
+	}
+	return w, nil
+}
+
+func validate(w model.MaintenanceWindow) error {
+	if w.Name == "" {
+		return errors.New("nombre requerido")
+	}
+	if w.Start.IsZero() {
+		return errors.New("start requerido")
+	}
+	if w.Duration <= 0 {
+		return errors.New("duration debe ser mayor a 0")
+	}
+	switch w.Repeat {
+	case model.RepeatNone, model.RepeatDaily, model.RepeatWeekly:
+	default:
+		return fmt.Errorf("repeat invalido %q (usar daily o weekly)", w.Repeat)
+	}
+	if period := w.Period(); period > 0 && w.Duration >= period {
+		return fmt.Errorf("duration debe ser menor que el periodo de repeticion (%s)", period)
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+// Package metrics expone el estado del monitor en el formato de texto de
+// Prometheus.
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+	"proyecto-leng-paradigmas/ejemplo/internal/scheduler"
+)
+
+// ContentType es el Content-Type del formato de exposicion.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// StatusSource entrega el snapshot de estados (store o servicio).
+type StatusSource interface {
+	Status() []model.TargetStatus
+}
+
+type checkCounts struct {
+	target      model.Target
+	success     uint64
+	failure     uint64
+	maintenance uint64
+}
+
+// Collector acumula contadores de chequeos e implementa scheduler.Observer.
+type Collector struct {
+	status StatusSource
+	sched  *scheduler.Scheduler
+
+	mu     sync.Mutex
+	checks map[string]*checkCounts
+}
+
+// NewCollector crea un Collector.
+func NewCollector(status StatusSource, sched *scheduler.Scheduler) *Collector {
+	return &Collector{
+		status: status,
+		sched:  sched,
+		checks: make(map[string]*checkCounts),
+	}
+}
+
+// Observe implementa scheduler.Observer.
+func (c *Collector) Observe(_ context.Context, target model.Target, result model.CheckResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cnt, ok := c.checks[target.ID]
+	if !ok {
+		cnt = &checkCounts{}
+		c.checks[target.ID] = cnt
+	}
+	cnt.target = target
+	switch {
+	case result.Maintenance:
+		cnt.maintenance++
+	case result.Success:
+		cnt.success++
+	default:
+		cnt.failure++
+	}
+}
+
+// Forget descarta los contadores de un target eliminado.
+func (c *Collector) Forget(targetID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.checks, targetID)
+}
+
+// WriteTo escribe todas las metricas en formato de texto.
+func (c *Collector) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	statuses := c.status.Status()
+
+	family(&b, "monitor_target_up", "gauge", "1 si el ultimo chequeo fue exitoso, 0 si fallo.")
+	for _, st := range statuses {
+		if st.LastCheck == nil {
+			continue
+		}
+		sample(&b, "monitor_target_up", targetLabels(st.Target), boolValue(st.LastCheck.Success))
+	}
+
+	family(&b, "monitor_target_latency_seconds", "gauge", "Duracion del ultimo chequeo.")
+	for _, st := range statuses {
+		if st.LastCheck == nil {
+			continue
+		}
+		sample(&b, "monitor_target_latency_seconds", targetLabels(st.Target), st.LastCheck.Duration.Seconds())
+	}
+
+	family(&b, "monitor_target_consecutive_failures", "gauge", "Chequeos fallidos seguidos.")
+	for _, st := range statuses {
+		sample(&b, "monitor_target_consecutive_failures", targetLabels(st.Target), float64(st.ConsecutiveFailures))
+	}
+
+	family(&b, "monitor_target_uptime_percent", "gauge", "Porcentaje de chequeos exitosos.")
+	for _, st := range statuses {
+		sample(&b, "monitor_target_uptime_percent", targetLabels(st.Target), st.UptimePerc)
+	}
+
+	family(&b, "monitor_checks_total", "counter", "Chequeos ejecutados desde el inicio del proceso por resultado.")
+	c.mu.Lock()
+	ids := make([]string, 0, len(c.checks))
+	for id := range c.checks {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		cnt := c.checks[id]
+		labels := targetLabels(cnt.target)
+		sample(&b, "monitor_checks_total", append(labels, label{"result", "success"}), float64(cnt.success))
+		sample(&b, "monitor_checks_total", append(labels, label{"result", "failure"}), float64(cnt.failure))
+		sample(&b, "monitor_checks_total", append(labels, label{"result", "maintenance"}), float64(cnt.maintenance))
+	}
+	c.mu.Unlock()
+
+	stats := c.sched.Stats()
+	family(&b, "monitor_scheduler_targets", "gauge", "Targets planificados (no pausados).")
+	sample(&b, "monitor_scheduler_targets", nil, float64(stats.Targets))
+	family(&b, "monitor_scheduler_workers", "gauge", "Workers del pool: limite de chequeos simultaneos.")
+	sample(&b, "monitor_scheduler_workers", nil, float64(stats.Workers))
+	family(&b, "monitor_scheduler_running_checks", "gauge", "Chequeos en ejecucion en este momento.")
+	sample(&b, "monitor_scheduler_running_checks", nil, float64(stats.Running))
+	family(&b, "monitor_scheduler_queued_checks", "gauge", "Chequeos vencidos esperando un worker libre.")
+	sample(&b, "monitor_scheduler_queued_checks", nil, float64(stats.Queued))
+	family(&b, "monitor_go_goroutines", "gauge", "Goroutines del proceso.")
+	sample(&b, "monitor_go_goroutines", nil, float64(runtime.NumGoroutine()))
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+type label struct {
+	name, value string
+}
+
+func targetLabels(t model.Target) []label {
+	return []label{{"id", t.ID}, {"name", t.Name}, {"kind", string(t.Kind)}}
+}
+
+func family(b *strings.Builder, name, kind, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func sample(b *strings.Builder, name string, labels []label, value float64) {
+	b.WriteString(name)
+	if len(labels) > 0 {
+		b.WriteByte('{')
+		for i, l := range labels {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			fmt.Fprintf(b, "%s=\"%s\"", l.name, escape(l.value))
+		}
+		b.WriteByte('}')
+	}
+	fmt.Fprintf(b, " %g\n", value)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escape(v string) string {
+	return labelEscaper.Replace(v)
+}
+
+func boolValue(v bool) float64 {
+	if v {
+		return 1
+	}
+	return 0
+}
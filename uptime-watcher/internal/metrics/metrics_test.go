@@ -0,0 +1,155 @@
+package metrics
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/check"
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+	"proyecto-leng-paradigmas/ejemplo/internal/scheduler"
+	"proyecto-leng-paradigmas/ejemplo/internal/store"
+)
+
+type staticStatus []model.TargetStatus
+
+func (s staticStatus) Status() []model.TargetStatus { return s }
+
+var (
+	// un nombre con los tres caracteres que el formato obliga a escapar
+	web = model.Target{ID: "web", Name: "Web \"prod\"\\eu\nnorte", Kind: model.TargetHTTP}
+	// sin chequeos todavia
+	db = model.Target{ID: "db", Name: "DB", Kind: model.TargetTCP}
+)
+
+var (
+	helpLine   = regexp.MustCompile(`^# HELP ([a-zA-Z_:][a-zA-Z0-9_:]*) \S.*$`)
+	typeLine   = regexp.MustCompile(`^# TYPE ([a-zA-Z_:][a-zA-Z0-9_:]*) (gauge|counter)$`)
+	sampleLine = regexp.MustCompile(`^([a-zA-Z_:][a-zA-Z0-9_:]*)(\{[^{}]*\})? (\S+)$`)
+	labelPair  = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*="(?:[^"\\\n]|\\["\\n])*"`)
+)
+
+// parseExposition valida el formato de texto de Prometheus: HELP y TYPE una
+// vez por familia y antes de sus muestras, muestras solo de familias
+// declaradas, etiquetas bien escapadas y series sin repetir. Devuelve las
+// muestras como serie -> valor.
+func parseExposition(t *testing.T, text string) map[string]string {
+	t.Helper()
+	if !strings.HasSuffix(text, "\n") {
+		t.Fatal("la exposicion debe terminar en salto de linea")
+	}
+	helps := make(map[string]bool)
+	types := make(map[string]bool)
+	samples := make(map[string]string)
+	var current string
+	for i, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "# HELP "):
+			m := helpLine.FindStringSubmatch(line)
+			if m == nil || helps[m[1]] {
+				t.Fatalf("linea %d: HELP invalido o repetido: %q", i+1, line)
+			}
+			helps[m[1]] = true
+			current = m[1]
+		case strings.HasPrefix(line, "# TYPE "):
+			m := typeLine.FindStringSubmatch(line)
+			if m == nil || types[m[1]] || m[1] != current {
+				t.Fatalf("linea %d: TYPE invalido, repetido o sin HELP: %q", i+1, line)
+			}
+			types[m[1]] = true
+		default:
+			m := sampleLine.FindStringSubmatch(line)
+			if m == nil {
+				t.Fatalf("linea %d: muestra invalida: %q", i+1, line)
+			}
+			if m[1] != current || !types[m[1]] {
+				t.Fatalf("linea %d: muestra de %s fuera de su familia", i+1, m[1])
+			}
+			if labels := m[2]; labels != "" {
+				rest := strings.TrimSuffix(strings.TrimPrefix(labels, "{"), "}")
+				for rest != "" {
+					pair := labelPair.FindString(rest)
+					if pair == "" {
+						t.Fatalf("linea %d: etiquetas invalidas %s", i+1, labels)
+					}
+					rest = strings.TrimPrefix(rest[len(pair):], ",")
+				}
+			}
+			series := m[1] + m[2]
+			if _, dup := samples[series]; dup {
+				t.Fatalf("linea %d: serie repetida %s", i+1, series)
+			}
+			samples[series] = m[3]
+		}
+	}
+	return samples
+}
+
+func TestWriteToExpositionFormat(t *testing.T) {
+	statuses := staticStatus{
+		{
+			Target:              web,
+			LastCheck:           &model.CheckResult{Success: false, Duration: 250 * time.Millisecond},
+			UptimePerc:          99.5,
+			ConsecutiveFailures: 2,
+		},
+		{Target: db},
+	}
+	sched := scheduler.New(check.NewRunner(), store.New(nil), nil)
+	sched.SetConcurrency(8)
+	c := NewCollector(statuses, sched)
+	ctx := context.Background()
+	c.Observe(ctx, web, model.CheckResult{Success: true})
+	c.Observe(ctx, web, model.CheckResult{Success: false})
+	c.Observe(ctx, web, model.CheckResult{Success: false})
+	c.Observe(ctx, web, model.CheckResult{Success: false, Maintenance: true})
+
+	var b strings.Builder
+	n, err := c.WriteTo(&b)
+	if err != nil || n != int64(b.Len()) {
+		t.Fatalf("WriteTo = %d, %v; se escribieron %d bytes", n, err, b.Len())
+	}
+	samples := parseExposition(t, b.String())
+
+	webLabels := `id="web",name="Web \"prod\"\\eu\nnorte",kind="http"`
+	dbLabels := `id="db",name="DB",kind="tcp"`
+	want := map[string]string{
+		"monitor_target_up{" + webLabels + "}":                         "0",
+		"monitor_target_latency_seconds{" + webLabels + "}":            "0.25",
+		"monitor_target_consecutive_failures{" + webLabels + "}":       "2",
+		"monitor_target_consecutive_failures{" + dbLabels + "}":        "0",
+		"monitor_target_uptime_percent{" + webLabels + "}":             "99.5",
+		"monitor_checks_total{" + webLabels + `,result="success"}`:     "1",
+		"monitor_checks_total{" + webLabels + `,result="failure"}`:     "2",
+		"monitor_checks_total{" + webLabels + `,result="maintenance"}`: "1",
+		"monitor_scheduler_targets":                                    "0",
+		"monitor_scheduler_workers":                                    "8",
+		"monitor_scheduler_running_checks":                             "0",
+		"monitor_scheduler_queued_checks":                              "0",
+	}
+	for series, value := range want {
+		if got, ok := samples[series]; !ok || got != value {
+			t.Errorf("%s = %q (presente %v), se esperaba %s", series, got, ok, value)
+		}
+	}
+	// sin chequeos no hay estado ni latencia que informar
+	for _, series := range []string{"monitor_target_up{" + dbLabels + "}", "monitor_target_latency_seconds{" + dbLabels + "}"} {
+		if _, ok := samples[series]; ok {
+			t.Errorf("%s presente para un target sin chequeos", series)
+		}
+	}
+	if _, ok := samples["monitor_go_goroutines"]; !ok {
+		t.Error("falta monitor_go_goroutines")
+	}
+
+	c.Forget("web")
+	b.Reset()
+	if _, err := c.WriteTo(&b); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(b.String(), "monitor_checks_total{") {
+		t.Error("Forget no descarto los contadores del target")
+	}
+}
@@ -22,6 +22,59 @@ type Target struct {
 	Port      int           `json:"port,omitempty"`
 	Frequency time.Duration `json:"frequency"`
 	Timeout   time.Duration `json:"timeout"`
+	// Schedule es una expresion cron ("15 3 * * *") que reemplaza a
+	// Frequency; Timezone es la zona IANA en que se interpreta (UTC si esta
+	// vacia).
+	Schedule string `json:"schedule,omitempty"`
+	Timezone string `json:"timezone,omitempty"`
+	// Retries es la cantidad de reintentos antes de registrar un fallo.
+	Retries       int           `json:"retries,omitempty"`
+	RetryInterval time.Duration `json:"retry_interval,omitempty"`
+	// Paused detiene los chequeos sin perder configuracion ni historial.
+	Paused bool `json:"paused,omitempty"`
+	// Group agrupa targets en el dashboard; Tags son etiquetas libres
+	// como "env:prod".
+	Group string   `json:"group,omitempty"`
+	Tags  []string `json:"tags,omitempty"`
+	// Public muestra el target como componente en la pagina de estado publica.
+	Public bool `json:"public,omitempty"`
+	// Options guarda la configuracion propia de cada tipo de chequeo.
+	Options map[string]string `json:"options,omitempty"`
+}
+
+// HasTag indica si el target tiene la etiqueta indicada.
+func (t Target) HasTag(tag string) bool {
+	for _, tg := range t.Tags {
+		if tg == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// TargetFilter selecciona targets por grupo y etiquetas. Los campos vacios no
+// filtran; todas las etiquetas indicadas deben estar presentes.
+type TargetFilter struct {
+	Group string
+	Tags  []string
+}
+
+// Empty indica si el filtro no restringe nada.
+func (f TargetFilter) Empty() bool {
+	return f.Group == "" && len(f.Tags) == 0
+}
+
+// Match indica si el target cumple el filtro.
+func (f TargetFilter) Match(t Target) bool {
+	if f.Group != "" && t.Group != f.Group {
+		return false
+	}
+	for _, tag := range f.Tags {
+		if !t.HasTag(tag) {
+			return false
+		}
+	}
+	return true
 }
 
 // CheckResult representa el resultado de un chequeo puntual.
@@ -32,13 +85,259 @@ type CheckResult struct {
 	Success    bool          `json:"success"`
 	Message    string        `json:"message"`
 	StatusCode int           `json:"status_code,omitempty"`
+	// Attempts cuenta los intentos realizados, incluidos los reintentos.
+	Attempts int `json:"attempts,omitempty"`
+	// Maintenance marca resultados obtenidos durante una ventana de
+	// mantenimiento; no cuentan para uptime, incidentes ni alertas.
+	Maintenance bool `json:"maintenance,omitempty"`
+	// TLS describe el certificado del servidor cuando el chequeo lo inspecciona.
+	TLS *TLSInfo `json:"tls,omitempty"`
+	// Timing desglosa la duracion de los chequeos HTTP.
+	Timing *HTTPTiming `json:"timing,omitempty"`
+}
+
+// HTTPTiming separa las fases de una peticion HTTP. TTFB mide la espera desde
+// que se envio la peticion hasta el primer byte, de modo que las fases sumadas
+// aproximan Duration. Las fases que no ocurrieron (p. ej. conexion
+// reutilizada) quedan en 0.
+type HTTPTiming struct {
+	DNS      time.Duration `json:"dns"`
+	Connect  time.Duration `json:"connect"`
+	TLS      time.Duration `json:"tls"`
+	TTFB     time.Duration `json:"ttfb"`
+	Transfer time.Duration `json:"transfer"`
+}
+
+// TLSInfo resume el certificado hoja presentado por un servidor.
+type TLSInfo struct {
+	Subject    string    `json:"subject"`
+	Issuer     string    `json:"issuer"`
+	SANs       []string  `json:"sans,omitempty"`
+	NotAfter   time.Time `json:"not_after"`
+	DaysLeft   int       `json:"days_left"`
+	ChainValid bool      `json:"chain_valid"`
+	ChainError string    `json:"chain_error,omitempty"`
 }
 
 // TargetStatus resume el estado actual de un Target.
 type TargetStatus struct {
-	Target     Target       `json:"target"`
-	LastCheck  *CheckResult `json:"last_check,omitempty"`
-	UptimePerc float64      `json:"uptime_perc"`
+	Target    Target       `json:"target"`
+	LastCheck *CheckResult `json:"last_check,omitempty"`
+	// UptimePerc corresponde a la ventana de 24h cuando hay estadisticas.
+	UptimePerc float64 `json:"uptime_perc"`
 	// Failures seguidas para detectar alertas simples.
 	ConsecutiveFailures int `json:"consecutive_failures"`
+	// Windows contiene uptime y percentiles de latencia por ventana fija.
+	Windows []WindowStats `json:"windows,omitempty"`
+}
+
+// WindowStats resume los chequeos de una ventana de tiempo fija (1h, 24h...).
+// Los percentiles se calculan sobre los chequeos exitosos.
+type WindowStats struct {
+	Window     string        `json:"window"`
+	Checks     int           `json:"checks"`
+	UptimePerc float64       `json:"uptime_perc"`
+	P50        time.Duration `json:"p50"`
+	P90        time.Duration `json:"p90"`
+	P95        time.Duration `json:"p95"`
+	P99        time.Duration `json:"p99"`
+}
+
+// DailyUptime resume los chequeos de un dia UTC, sin contar mantenimiento.
+type DailyUptime struct {
+	Date      time.Time `json:"date"`
+	Checks    int       `json:"checks"`
+	Successes int       `json:"successes"`
+}
+
+// Sample es la version reducida de un CheckResult usada para estadisticas.
+type Sample struct {
+	CheckedAt   time.Time
+	Duration    time.Duration
+	Success     bool
+	Maintenance bool
+}
+
+// Incident representa un periodo en que un target estuvo caido.
+type Incident struct {
+	ID         int64      `json:"id"`
+	TargetID   string     `json:"target_id"`
+	StartedAt  time.Time  `json:"started_at"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+	// Duration se calcula hasta ResolvedAt o, si sigue abierto, hasta ahora.
+	Duration   time.Duration `json:"duration"`
+	FirstError string        `json:"first_error"`
+}
+
+// Open indica si el incidente sigue sin resolverse.
+func (i Incident) Open() bool {
+	return i.ResolvedAt == nil
+}
+
+// AlertKind identifica la condicion que evalua una regla de alerta.
+type AlertKind string
+
+const (
+	// AlertDown se dispara tras Threshold chequeos fallidos seguidos.
+	AlertDown AlertKind = "down"
+	// AlertLatency se dispara cuando la latencia supera Latency durante Window.
+	AlertLatency AlertKind = "latency"
+	// AlertRecovery se dispara con el primer exito luego de Threshold fallos.
+	AlertRecovery AlertKind = "recovery"
+)
+
+// AlertRule define cuando notificar y a que canales.
+type AlertRule struct {
+	ID   string    `json:"id"`
+	Name string    `json:"name"`
+	Kind AlertKind `json:"kind"`
+	// TargetID vacio aplica la regla a todos los targets.
+	TargetID   string        `json:"target_id,omitempty"`
+	Threshold  int           `json:"threshold,omitempty"`
+	Latency    time.Duration `json:"latency,omitempty"`
+	Window     time.Duration `json:"window,omitempty"`
+	ChannelIDs []string      `json:"channel_ids"`
+	Enabled    bool          `json:"enabled"`
+}
+
+// AlertChannel es un destino webhook para las notificaciones.
+type AlertChannel struct {
+	ID      string            `json:"id"`
+	Name    string            `json:"name"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Enabled bool              `json:"enabled"`
+}
+
+// AlertEvent es el payload enviado a los webhooks.
+type AlertEvent struct {
+	RuleID              string        `json:"rule_id"`
+	RuleName            string        `json:"rule_name"`
+	Kind                AlertKind     `json:"kind"`
+	Target              Target        `json:"target"`
+	Message             string        `json:"message"`
+	CheckedAt           time.Time     `json:"checked_at"`
+	Latency             time.Duration `json:"latency"`
+	ConsecutiveFailures int           `json:"consecutive_failures"`
+}
+
+// MaintenanceRepeat indica cada cuanto se repite una ventana de mantenimiento.
+type MaintenanceRepeat string
+
+const (
+	RepeatNone   MaintenanceRepeat = ""
+	RepeatDaily  MaintenanceRepeat = "daily"
+	RepeatWeekly MaintenanceRepeat = "weekly"
+)
+
+// MaintenanceWindow es un periodo en que los fallos de ciertos targets no
+// cuentan: los chequeos se ejecutan pero se marcan como mantenimiento. Las
+// ventanas recurrentes repiten Start cada 24h o 7 dias.
+type MaintenanceWindow struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Con TargetIDs y Groups vacios la ventana aplica a todos los targets.
+	TargetIDs []string          `json:"target_ids"`
+	Groups    []string          `json:"groups"`
+	Start     time.Time         `json:"start"`
+	Duration  time.Duration     `json:"duration"`
+	Repeat    MaintenanceRepeat `json:"repeat,omitempty"`
+}
+
+// Period devuelve el intervalo de repeticion, o 0 si la ventana es unica.
+func (w MaintenanceWindow) Period() time.Duration {
+	switch w.Repeat {
+	case RepeatDaily:
+		return 24 * time.Hour
+	case RepeatWeekly:
+		return 7 * 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+// Covers indica si la ventana aplica al target, por id o por grupo.
+func (w MaintenanceWindow) Covers(target Target) bool {
+	if len(w.TargetIDs) == 0 && len(w.Groups) == 0 {
+		return true
+	}
+	for _, id := range w.TargetIDs {
+		if id == target.ID {
+			return true
+		}
+	}
+	for _, g := range w.Groups {
+		if target.Group != "" && g == target.Group {
+			return true
+		}
+	}
+	return false
+}
+
+// ActiveAt indica si at cae dentro de alguna ocurrencia de la ventana.
+func (w MaintenanceWindow) ActiveAt(at time.Time) bool {
+	if at.Before(w.Start) {
+		return false
+	}
+	elapsed := at.Sub(w.Start)
+	if period := w.Period(); period > 0 {
+		elapsed %= period
+	}
+	return elapsed < w.Duration
+}
+
+// NextStart devuelve el inicio de la ocurrencia en curso o de la proxima a
+// partir de at; false si la ventana unica ya termino.
+func (w MaintenanceWindow) NextStart(at time.Time) (time.Time, bool) {
+	if at.Before(w.Start) {
+		return w.Start, true
+	}
+	period := w.Period()
+	if period == 0 {
+		return w.Start, at.Before(w.Start.Add(w.Duration))
+	}
+	n := at.Sub(w.Start) / period
+	current := w.Start.Add(n * period)
+	if at.Before(current.Add(w.Duration)) {
+		return current, true
+	}
+	return current.Add(period), true
+}
+
+// User es una cuenta con acceso al dashboard y a la API de administracion.
+type User struct {
+	ID        string    `json:"id"`
+	Username  string    `json:"username"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TokenScope limita lo que puede hacer un token de API.
+type TokenScope string
+
+const (
+	// ScopeRead solo permite consultas (GET y HEAD).
+	ScopeRead TokenScope = "read"
+	// ScopeAdmin permite ademas crear, modificar y eliminar.
+	ScopeAdmin TokenScope = "admin"
+)
+
+// Allows indica si el scope permite el metodo HTTP indicado.
+func (s TokenScope) Allows(method string) bool {
+	switch s {
+	case ScopeAdmin:
+		return true
+	case ScopeRead:
+		return method == "GET" || method == "HEAD"
+	default:
+		return false
+	}
+}
+
+// APIToken describe un token de API; el valor secreto solo se muestra al
+// crearlo y en la base se guarda su hash.
+type APIToken struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Scope     TokenScope `json:"scope"`
+	CreatedAt time.Time  `json:"created_at"`
 }
@@ -0,0 +1,106 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+// monday es el lunes 5 de enero de 2026 a las 22:00 UTC.
+var monday = time.Date(2026, 1, 5, 22, 0, 0, 0, time.UTC)
+
+func TestMaintenanceWindowActiveAt(t *testing.T) {
+	once := MaintenanceWindow{Start: monday, Duration: time.Hour}
+	// de 22:00 a 04:00: cada ocurrencia cruza la medianoche
+	daily := MaintenanceWindow{Start: monday, Duration: 6 * time.Hour, Repeat: RepeatDaily}
+	// del lunes 22:00 al martes 02:00
+	weekly := MaintenanceWindow{Start: monday, Duration: 4 * time.Hour, Repeat: RepeatWeekly}
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name   string
+		window MaintenanceWindow
+		at     time.Time
+		want   bool
+	}{
+		{"unica antes de empezar", once, monday.Add(-time.Second), false},
+		{"unica al empezar", once, monday, true},
+		{"unica en curso", once, monday.Add(59 * time.Minute), true},
+		{"unica al terminar", once, monday.Add(time.Hour), false},
+		{"unica vencida", once, monday.Add(7 * day), false},
+		{"diaria antes del primer inicio", daily, monday.Add(-time.Hour), false},
+		{"diaria pasada la medianoche", daily, monday.Add(3 * time.Hour), true},
+		{"diaria al terminar", daily, monday.Add(6 * time.Hour), false},
+		{"diaria entre ocurrencias", daily, monday.Add(12 * time.Hour), false},
+		{"diaria un mes despues", daily, monday.Add(31*day + 5*time.Hour), true},
+		{"semanal el martes de madrugada", weekly, monday.Add(3 * time.Hour), true},
+		{"semanal el martes a la tarde", weekly, monday.Add(20 * time.Hour), false},
+		{"semanal otro lunes", weekly, monday.Add(14 * day), true},
+		{"semanal otro dia a la misma hora", weekly, monday.Add(3 * day), false},
+	}
+	for _, tt := range tests {
+		if got := tt.window.ActiveAt(tt.at); got != tt.want {
+			t.Errorf("%s: ActiveAt(%s) = %v, se esperaba %v", tt.name, tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestMaintenanceWindowNextStart(t *testing.T) {
+	once := MaintenanceWindow{Start: monday, Duration: time.Hour}
+	daily := MaintenanceWindow{Start: monday, Duration: 6 * time.Hour, Repeat: RepeatDaily}
+	weekly := MaintenanceWindow{Start: monday, Duration: 4 * time.Hour, Repeat: RepeatWeekly}
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name   string
+		window MaintenanceWindow
+		at     time.Time
+		want   time.Time
+		ok     bool
+	}{
+		{"unica antes de empezar", once, monday.Add(-day), monday, true},
+		{"unica en curso", once, monday.Add(30 * time.Minute), monday, true},
+		{"unica terminada", once, monday.Add(time.Hour), time.Time{}, false},
+		{"diaria antes del primer inicio", daily, monday.Add(-3 * day), monday, true},
+		// en curso devuelve el inicio de la ocurrencia actual, del dia anterior
+		{"diaria en curso tras la medianoche", daily, monday.Add(day + 4*time.Hour), monday.Add(day), true},
+		{"diaria entre ocurrencias", daily, monday.Add(10 * time.Hour), monday.Add(day), true},
+		{"diaria justo al terminar", daily, monday.Add(6 * time.Hour), monday.Add(day), true},
+		{"semanal en curso", weekly, monday.Add(7*day + time.Hour), monday.Add(7 * day), true},
+		{"semanal a mitad de semana", weekly, monday.Add(3 * day), monday.Add(7 * day), true},
+	}
+	for _, tt := range tests {
+		got, ok := tt.window.NextStart(tt.at)
+		if ok != tt.ok || (ok && !got.Equal(tt.want)) {
+			t.Errorf("%s: NextStart(%s) = %s, %v; se esperaba %s, %v", tt.name, tt.at, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestMaintenanceWindowCovers(t *testing.T) {
+	web := Target{ID: "web", Group: "frontend"}
+	api := Target{ID: "api", Group: "backend"}
+	loose := Target{ID: "cron"}
+
+	tests := []struct {
+		name   string
+		window MaintenanceWindow
+		target Target
+		want   bool
+	}{
+		{"sin filtros cubre todo", MaintenanceWindow{}, loose, true},
+		{"listas vacias cubren todo", MaintenanceWindow{TargetIDs: []string{}, Groups: []string{}}, web, true},
+		{"por id", MaintenanceWindow{TargetIDs: []string{"api", "web"}}, web, true},
+		{"otro id", MaintenanceWindow{TargetIDs: []string{"api"}}, web, false},
+		{"por grupo", MaintenanceWindow{Groups: []string{"frontend"}}, web, true},
+		{"otro grupo", MaintenanceWindow{Groups: []string{"frontend"}}, api, false},
+		{"grupo vacio no cubre targets sin grupo", MaintenanceWindow{Groups: []string{""}}, loose, false},
+		{"id o grupo", MaintenanceWindow{TargetIDs: []string{"cron"}, Groups: []string{"backend"}}, loose, true},
+		{"id o grupo, por grupo", MaintenanceWindow{TargetIDs: []string{"cron"}, Groups: []string{"backend"}}, api, true},
+		{"id o grupo, ninguno", MaintenanceWindow{TargetIDs: []string{"cron"}, Groups: []string{"backend"}}, web, false},
+	}
+	for _, tt := range tests {
+		if got := tt.window.Covers(tt.target); got != tt.want {
+			t.Errorf("%s: Covers(%s) = %v, se esperaba %v", tt.name, tt.target.ID, got, tt.want)
+		}
+	}
+}
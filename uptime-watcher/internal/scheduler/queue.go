@@ -0,0 +1,122 @@
+package scheduler
+
+import (
+	"context"
+	"hash/fnv"
+	"time"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/cron"
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+)
+
+// entry es el estado de planificacion de un target.
+type entry struct {
+	target model.Target
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// cron es target.Schedule interpretado; nil si el target usa Frequency.
+	cron *cron.Schedule
+
+	// next es el proximo evento (chequeo regular, reintento o Trigger) y
+	// ordena el heap; tick es el proximo chequeo regular, de la grilla
+	// target.Frequency o del cron, que no se corre con Trigger ni con los
+	// reintentos.
+	next time.Time
+	tick time.Time
+
+	index     int  // posicion en el heap; -1 si no esta encolado
+	running   bool // hay un intento en el pool, esperando worker o registrandose
+	attempts  int  // intentos fallidos del chequeo en curso
+	triggered bool // Trigger llego mientras corria: repetir al terminar
+}
+
+// firstRegular devuelve el primer chequeo regular a partir de now.
+func (e *entry) firstRegular(now time.Time) time.Time {
+	if e.cron != nil {
+		return e.cron.Next(now)
+	}
+	return firstTick(e.target.ID, e.target.Frequency, now)
+}
+
+// nextRegular devuelve el proximo chequeo regular posterior a now sin mover
+// uno que todavia no vencio.
+func (e *entry) nextRegular(now time.Time) time.Time {
+	if e.cron != nil {
+		if e.tick.After(now) {
+			return e.tick
+		}
+		return e.cron.Next(now)
+	}
+	return nextTick(e.tick, e.target.Frequency, now)
+}
+
+// queue es un min-heap de entries ordenado por next; implementa
+// container/heap.
+type queue []*entry
+
+func (q queue) Len() int { return len(q) }
+
+func (q queue) Less(i, j int) bool { return q[i].next.Before(q[j].next) }
+
+func (q queue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *queue) Push(x any) {
+	e := x.(*entry)
+	e.index = len(*q)
+	*q = append(*q, e)
+}
+
+func (q *queue) Pop() any {
+	old := *q
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	e.index = -1
+	*q = old[:n-1]
+	return e
+}
+
+// nextTick devuelve el primer instante de la grilla from + k*every posterior
+// a now. Los ticks perdidos (chequeos mas lentos que la frecuencia) se saltean
+// en lugar de acumularse.
+func nextTick(from time.Time, every time.Duration, now time.Time) time.Time {
+	if every <= 0 {
+		every = time.Second
+	}
+	if from.After(now) {
+		return from
+	}
+	k := now.Sub(from)/every + 1
+	return from.Add(k * every)
+}
+
+// phaseOffset devuelve el desfase fijo de un target dentro de su frecuencia,
+// derivado del hash de su id. Es estable entre reinicios y reparte los
+// targets de igual frecuencia a lo largo del intervalo.
+func phaseOffset(targetID string, every time.Duration) time.Duration {
+	if every <= 0 {
+		return 0
+	}
+	h := fnv.New64a()
+	h.Write([]byte(targetID))
+	return time.Duration(h.Sum64() % uint64(every))
+}
+
+// firstTick devuelve el primer chequeo regular de un target a partir de now:
+// el primer instante de la grilla k*every + phaseOffset que no es anterior a
+// now.
+func firstTick(targetID string, every time.Duration, now time.Time) time.Time {
+	if every <= 0 {
+		every = time.Second
+	}
+	tick := now.Truncate(every).Add(phaseOffset(targetID, every))
+	if tick.Before(now) {
+		tick = tick.Add(every)
+	}
+	return tick
+}
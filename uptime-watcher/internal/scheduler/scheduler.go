@@ -1,11 +1,17 @@
 package scheduler
 
 import (
+	"container/heap"
 	"context"
+	"maps"
+	"math/rand/v2"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"proyecto-leng-paradigmas/ejemplo/internal/check"
+	"proyecto-leng-paradigmas/ejemplo/internal/clock"
+	"proyecto-leng-paradigmas/ejemplo/internal/cron"
 	"proyecto-leng-paradigmas/ejemplo/internal/model"
 	"proyecto-leng-paradigmas/ejemplo/internal/store"
 )
@@ -15,21 +21,79 @@ type Logger interface {
 	Printf(format string, v ...any)
 }
 
-type worker struct {
-	trigger chan struct{}
-	cancel  context.CancelFunc
+// Observer recibe cada resultado una vez almacenado. Se invoca desde un
+// worker del pool, por lo que no debe bloquear por tiempos largos: mientras
+// tanto ese worker no ejecuta chequeos.
+type Observer interface {
+	Observe(ctx context.Context, target model.Target, result model.CheckResult)
 }
 
-// Scheduler coordina la ejecucion periodica de chequeos.
+// Forgetter es implementado por los Observer que guardan estado por target y
+// deben limpiarlo cuando el target se elimina.
+type Forgetter interface {
+	Forget(targetID string)
+}
+
+// Maintenance indica si un target esta en una ventana de mantenimiento.
+type Maintenance interface {
+	InMaintenance(target model.Target, at time.Time) bool
+}
+
+// DefaultRetryInterval es la espera entre reintentos cuando el target no
+// define RetryInterval.
+const DefaultRetryInterval = time.Second
+
+// RetryInterval devuelve la espera efectiva entre reintentos de un target.
+func RetryInterval(target model.Target) time.Duration {
+	if target.RetryInterval > 0 {
+		return target.RetryInterval
+	}
+	return DefaultRetryInterval
+}
+
+// DefaultConcurrency es la cantidad de chequeos simultaneos por defecto.
+const DefaultConcurrency = 64
+
+// job es un intento de chequeo listo para el pool de workers.
+type job struct {
+	entry   *entry
+	target  model.Target
+	attempt int
+}
+
+// Scheduler coordina la ejecucion periodica de chequeos. Un unico dispatcher
+// mantiene un min-heap con el proximo evento de cada target y entrega los
+// vencidos a un pool fijo de workers, que limita los chequeos simultaneos sin
+// importar cuantos targets haya.
 type Scheduler struct {
 	runner  *check.Runner
 	store   *store.Store
 	logger  Logger
+	clock   clock.Clock
 	baseCtx context.Context
 
-	mu      sync.RWMutex
-	workers map[string]*worker
+	mu          sync.Mutex
+	entries     map[string]*entry
+	queue       queue
+	observers   []Observer
+	maintenance Maintenance
+	concurrency int
+	jitter      time.Duration
+
+	jobs    chan job
+	wake    chan struct{}
+	timer   clock.Timer // del dispatcher; solo armado mientras espera
 	wg      sync.WaitGroup
+	running atomic.Int64
+	queued  atomic.Int64
+}
+
+// Stats resume el estado interno del scheduler.
+type Stats struct {
+	Targets int // targets planificados (no pausados)
+	Workers int // tamaño del pool
+	Running int // chequeos en ejecucion
+	Queued  int // chequeos vencidos esperando un worker libre
 }
 
 // New crea un scheduler listo para iniciar.
@@ -38,113 +102,455 @@ func New(runner *check.Runner, store *store.Store, logger Logger) *Scheduler {
 		logger = noopLogger{}
 	}
 	return &Scheduler{
-		runner:  runner,
-		store:   store,
-		logger:  logger,
-		workers: make(map[string]*worker),
+		runner:      runner,
+		store:       store,
+		logger:      logger,
+		clock:       clock.Real,
+		entries:     make(map[string]*entry),
+		concurrency: DefaultConcurrency,
+		jobs:        make(chan job),
+		wake:        make(chan struct{}, 1),
+	}
+}
+
+// AddObserver registra un Observer. Debe llamarse antes de Start.
+func (s *Scheduler) AddObserver(o Observer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.observers = append(s.observers, o)
+}
+
+// SetMaintenance define la fuente de ventanas de mantenimiento usada para
+// marcar los resultados. Debe llamarse antes de Start.
+func (s *Scheduler) SetMaintenance(m Maintenance) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.maintenance = m
+}
+
+// SetClock reemplaza el reloj del sistema, por ejemplo por un clock.Fake.
+// Debe llamarse antes de Start.
+func (s *Scheduler) SetClock(c clock.Clock) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clock = clock.Or(c)
+}
+
+// SetConcurrency fija el limite global de chequeos simultaneos. Debe
+// llamarse antes de Start; valores menores a 1 usan DefaultConcurrency.
+func (s *Scheduler) SetConcurrency(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n < 1 {
+		n = DefaultConcurrency
+	}
+	s.concurrency = n
+}
+
+// SetJitter agrega a cada chequeo regular una demora aleatoria de hasta d,
+// acotada a la frecuencia del target, por encima del desfase fijo por target.
+// No aplica a los targets con cron. Debe llamarse antes de Start; 0 la
+// desactiva.
+func (s *Scheduler) SetJitter(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if d < 0 {
+		d = 0
 	}
+	s.jitter = d
 }
 
-// Start almacena el contexto base y lanza workers para los targets existentes.
+// Start lanza el dispatcher y el pool de workers y planifica los targets
+// existentes. Su primer chequeo no es inmediato sino el primer tick de su
+// grilla desfasada, para que arrancar con muchos targets no los corra todos
+// juntos.
 func (s *Scheduler) Start(ctx context.Context) {
 	s.mu.Lock()
 	s.baseCtx = ctx
+	workers := s.concurrency
 	s.mu.Unlock()
 
+	s.wg.Add(1 + workers)
+	go s.dispatch(ctx)
+	for i := 0; i < workers; i++ {
+		go s.work(ctx)
+	}
+
+	s.mu.Lock()
 	for _, target := range s.store.Targets() {
-		s.UpsertTarget(target)
+		s.scheduleLocked(target, false)
 	}
+	s.mu.Unlock()
 }
 
-// UpsertTarget crea o reinicia el worker asociado a un target.
-func (s *Scheduler) UpsertTarget(target model.Target) {
+// UpsertTarget planifica un target nuevo o aplica la nueva configuracion de
+// uno existente, y devuelve si sus chequeos se (re)iniciaron. Si cambia algo
+// que altera el chequeo (ver probeChanged), el chequeo en curso se cancela y
+// se descarta, y el target se chequea enseguida (salvo con cron) y despues
+// sigue su grilla desfasada o su cron. Los demas cambios, incluidos
+// frecuencia y cron, se aplican sin correr chequeos extra. Los targets
+// pausados quedan fuera de la planificacion.
+func (s *Scheduler) UpsertTarget(target model.Target) bool {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if e, ok := s.entries[target.ID]; ok && !target.Paused && !probeChanged(e.target, target) {
+		s.reconfigureLocked(e, target)
+		return false
+	}
+	return s.scheduleLocked(target, true)
+}
+
+// parseSchedule interpreta el cron de un target; nil si usa Frequency.
+func parseSchedule(target model.Target) (*cron.Schedule, error) {
+	if target.Schedule == "" {
+		return nil, nil
+	}
+	return cron.Parse(target.Schedule, target.Timezone)
+}
+
+// cadenceChanged indica si cambio cuando corren los chequeos regulares.
+func cadenceChanged(prev, next model.Target) bool {
+	return prev.Frequency != next.Frequency ||
+		prev.Schedule != next.Schedule ||
+		prev.Timezone != next.Timezone
+}
+
+// probeChanged indica si entre prev y next cambio algun campo que altera
+// como se ejecuta el chequeo. Nombre, grupo, tags, visibilidad, frecuencia,
+// cron y reintentos no cuentan: solo afectan cuando corre o como se muestra.
+func probeChanged(prev, next model.Target) bool {
+	return prev.Kind != next.Kind ||
+		prev.URL != next.URL ||
+		prev.Host != next.Host ||
+		prev.Port != next.Port ||
+		prev.Timeout != next.Timeout ||
+		!maps.Equal(prev.Options, next.Options)
+}
 
-	if w, ok := s.workers[target.ID]; ok {
-		w.cancel()
-		delete(s.workers, target.ID)
+// reconfigureLocked aplica una configuracion que no requiere reiniciar el
+// chequeo. Si cambia la frecuencia, el proximo chequeo regular pasa a la
+// nueva grilla sin quedar a menos de la nueva frecuencia del anterior; si
+// cambia el cron, a su proxima ejecucion. Un chequeo en curso, un reintento o
+// un Trigger pendiente no se tocan.
+func (s *Scheduler) reconfigureLocked(e *entry, target model.Target) {
+	prev := e.target
+	if !cadenceChanged(prev, target) {
+		e.target = target
+		return
 	}
-	if s.baseCtx == nil {
+	sched, err := parseSchedule(target)
+	if err != nil {
+		s.logger.Printf("target %s: %v; queda sin planificar", target.ID, err)
+		s.removeLocked(target.ID)
 		return
 	}
-	s.spawnWorkerLocked(target)
+	pending := !e.running && e.attempts == 0 && !e.next.Before(e.tick)
+	now := s.clock.Now()
+	earliest := now
+	if sched == nil && prev.Schedule == "" {
+		if t := e.tick.Add(target.Frequency - prev.Frequency); t.After(now) {
+			earliest = t
+		}
+	}
+	e.target, e.cron = target, sched
+	e.tick = e.firstRegular(earliest)
+	if pending {
+		e.next = s.jittered(e)
+		heap.Fix(&s.queue, e.index)
+		s.notify()
+	}
 }
 
-// RemoveTarget detiene el worker asociado a un target.
+// scheduleLocked reemplaza el entry de un target y devuelve si quedo
+// planificado. Los chequeos regulares caen en la grilla k*Frequency +
+// phaseOffset o en las ejecuciones del cron; con immediate se agrega un
+// chequeo ya, fuera de la grilla, como un Trigger. Los targets con cron no lo
+// reciben: solo corren a sus horarios.
+func (s *Scheduler) scheduleLocked(target model.Target, immediate bool) bool {
+	s.removeLocked(target.ID)
+	if s.baseCtx == nil || target.Paused {
+		return false
+	}
+	sched, err := parseSchedule(target)
+	if err != nil {
+		s.logger.Printf("target %s: %v; queda sin planificar", target.ID, err)
+		return false
+	}
+	ctx, cancel := context.WithCancel(s.baseCtx)
+	now := s.clock.Now()
+	e := &entry{target: target, cron: sched, ctx: ctx, cancel: cancel, index: -1}
+	e.tick = e.firstRegular(now)
+	e.next = s.jittered(e)
+	if immediate && sched == nil {
+		e.next = now
+	}
+	s.entries[target.ID] = e
+	heap.Push(&s.queue, e)
+	s.notify()
+	return true
+}
+
+// jittered suma al proximo chequeo regular de e la demora aleatoria
+// configurada con SetJitter. Los targets con cron corren a horario exacto.
+func (s *Scheduler) jittered(e *entry) time.Time {
+	d := min(s.jitter, e.target.Frequency)
+	if e.cron != nil || d <= 0 {
+		return e.tick
+	}
+	return e.tick.Add(time.Duration(rand.Int64N(int64(d))))
+}
+
+// RemoveTarget quita un target de la planificacion.
 func (s *Scheduler) RemoveTarget(targetID string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	if w, ok := s.workers[targetID]; ok {
-		w.cancel()
-		delete(s.workers, targetID)
+	s.removeLocked(targetID)
+	for _, o := range s.observers {
+		if f, ok := o.(Forgetter); ok {
+			f.Forget(targetID)
+		}
 	}
 }
 
-// Trigger fuerza la ejecucion inmediata del chequeo de un target.
+// Trigger fuerza la ejecucion inmediata del chequeo de un target. Si el
+// chequeo ya esta corriendo se repite al terminar. No altera la grilla de
+// chequeos regulares.
 func (s *Scheduler) Trigger(targetID string) bool {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	w, ok := s.workers[targetID]
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[targetID]
 	if !ok {
 		return false
 	}
-	select {
-	case w.trigger <- struct{}{}:
-	default:
+	if e.running {
+		e.triggered = true
+		return true
 	}
+	e.next = s.clock.Now()
+	heap.Fix(&s.queue, e.index)
+	s.notify()
 	return true
 }
 
-// Wait bloquea hasta que todas las goroutines finalicen.
+// Stats devuelve el estado de la planificacion y del pool.
+func (s *Scheduler) Stats() Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return Stats{
+		Targets: len(s.entries),
+		Workers: s.concurrency,
+		Running: int(s.running.Load()),
+		Queued:  int(s.queued.Load()),
+	}
+}
+
+// Wait bloquea hasta que el dispatcher y los workers finalicen.
 func (s *Scheduler) Wait() {
 	s.wg.Wait()
 }
 
-func (s *Scheduler) spawnWorkerLocked(target model.Target) {
-	ctx, cancel := context.WithCancel(s.baseCtx)
-	trigger := make(chan struct{}, 1)
-	s.workers[target.ID] = &worker{
-		trigger: trigger,
-		cancel:  cancel,
+func (s *Scheduler) removeLocked(targetID string) {
+	e, ok := s.entries[targetID]
+	if !ok {
+		return
+	}
+	e.cancel()
+	if e.index >= 0 {
+		heap.Remove(&s.queue, e.index)
+	}
+	delete(s.entries, targetID)
+}
+
+// notify despierta al dispatcher para que recalcule su espera. Se llama con
+// s.mu tomado y detiene el timer, cuyo vencimiento puede haber quedado viejo.
+func (s *Scheduler) notify() {
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+	select {
+	case s.wake <- struct{}{}:
+	default:
 	}
-	s.wg.Add(1)
-	go s.runWorker(ctx, target, trigger)
 }
 
-func (s *Scheduler) runWorker(ctx context.Context, target model.Target, trigger <-chan struct{}) {
+// dispatch saca del heap los eventos vencidos y los entrega al pool. Si todos
+// los workers estan ocupados espera: los chequeos se demoran pero nunca
+// superan el limite de concurrencia. El timer solo queda armado mientras el
+// dispatcher espera con el heap vigente; con un clock.Fake, BlockUntil(1)
+// indica que ya calculo su espera y se puede mover el reloj.
+func (s *Scheduler) dispatch(ctx context.Context) {
 	defer s.wg.Done()
+	s.mu.Lock()
+	timer := s.clock.NewTimer(time.Hour)
+	s.timer = timer
+	s.mu.Unlock()
+	defer timer.Stop()
+
+	for {
+		s.mu.Lock()
+		timer.Stop()
+		now := s.clock.Now()
+		var due []job
+		for s.queue.Len() > 0 && !s.queue[0].next.After(now) {
+			e := heap.Pop(&s.queue).(*entry)
+			due = append(due, s.startLocked(e, now))
+		}
+		var wait <-chan time.Time
+		if len(due) == 0 && s.queue.Len() > 0 {
+			timer.Reset(s.queue[0].next.Sub(now))
+			wait = timer.C()
+		}
+		s.mu.Unlock()
 
-	s.execute(ctx, target)
+		if len(due) > 0 {
+			s.queued.Add(int64(len(due)))
+			for i, j := range due {
+				select {
+				case s.jobs <- j:
+				case <-ctx.Done():
+					s.queued.Add(-int64(len(due) - i))
+					return
+				}
+			}
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.wake:
+		case <-wait:
+		}
+	}
+}
 
-	ticker := time.NewTicker(target.Frequency)
-	defer ticker.Stop()
+// startLocked marca el entry como en ejecucion y arma su proximo intento.
+func (s *Scheduler) startLocked(e *entry, now time.Time) job {
+	e.running = true
+	if e.attempts == 0 && !e.tick.After(now) {
+		// se consume el chequeo regular: la grilla avanza
+		e.tick = e.nextRegular(now)
+	}
+	return job{entry: e, target: e.target, attempt: e.attempts + 1}
+}
 
+func (s *Scheduler) work(ctx context.Context) {
+	defer s.wg.Done()
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case <-ticker.C:
-			s.execute(ctx, target)
-		case <-trigger:
-			s.execute(ctx, target)
+		case j := <-s.jobs:
+			s.queued.Add(-1)
+			s.execute(j)
 		}
 	}
 }
 
-func (s *Scheduler) execute(ctx context.Context, target model.Target) {
+func (s *Scheduler) execute(j job) {
+	e := j.entry
+	if e.ctx.Err() != nil {
+		// el target se elimino o reinicio mientras esperaba un worker
+		return
+	}
+	result := s.runOnce(e.ctx, j.target)
+	result.Attempts = j.attempt
+	if e.ctx.Err() != nil {
+		// el target se elimino o reinicio durante el chequeo: el resultado
+		// no refleja el estado del servicio
+		return
+	}
+
+	s.mu.Lock()
+	if s.entries[j.target.ID] != e {
+		s.mu.Unlock()
+		return
+	}
+	// la configuracion pudo cambiar durante el chequeo sin reiniciarlo
+	// (nombre, frecuencia, reintentos): se usa la vigente
+	target := e.target
+	if !result.Success && j.attempt <= target.Retries {
+		e.attempts = j.attempt
+		s.requeueLocked(e, s.clock.Now().Add(RetryInterval(target)))
+		s.mu.Unlock()
+		s.logger.Printf("target %s intento %d/%d fallo: %s", target.ID, j.attempt, target.Retries+1, result.Message)
+		return
+	}
+	maintenance, observers := s.maintenance, s.observers
+	s.mu.Unlock()
+
+	// el entry sigue en ejecucion mientras se registra: un Trigger o el
+	// proximo tick no pueden correr otro chequeo del target y registrar su
+	// resultado antes que este
+	s.record(e.ctx, target, result, maintenance, observers)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.entries[j.target.ID] != e {
+		return
+	}
+	e.attempts = 0
+	if e.triggered {
+		e.triggered = false
+		s.requeueLocked(e, s.clock.Now())
+		return
+	}
+	s.requeueLocked(e, time.Time{})
+}
+
+// requeueLocked termina el intento en curso de e y lo devuelve al heap con
+// next como proximo evento; con next cero, el proximo chequeo regular. Los
+// ticks que vencieron durante el chequeo o sus reintentos se saltean.
+func (s *Scheduler) requeueLocked(e *entry, next time.Time) {
+	e.running = false
+	e.tick = e.nextRegular(s.clock.Now())
+	if next.IsZero() {
+		next = s.jittered(e)
+	}
+	e.next = next
+	heap.Push(&s.queue, e)
+	s.notify()
+}
+
+// record guarda un resultado final y lo notifica a los observers.
+func (s *Scheduler) record(ctx context.Context, target model.Target, result model.CheckResult, maintenance Maintenance, observers []Observer) {
+	if maintenance != nil && maintenance.InMaintenance(target, result.CheckedAt) {
+		result.Maintenance = true
+	}
+	// la persistencia no debe abortar si el target se reinicia a mitad de camino
+	persistCtx := context.WithoutCancel(ctx)
+	if err := s.store.Record(persistCtx, result); err != nil {
+		s.logger.Printf("target %s: no se pudo persistir resultado: %v", target.ID, err)
+	}
+	for _, o := range observers {
+		o.Observe(persistCtx, target, result)
+	}
+	switch {
+	case result.Maintenance:
+		s.logger.Printf("target %s en mantenimiento: %s", target.ID, outcome(result))
+	case result.Success:
+		s.logger.Printf("target %s OK (%.0fms)", target.ID, result.Duration.Seconds()*1000)
+	default:
+		s.logger.Printf("target %s fallo: %s", target.ID, result.Message)
+	}
+}
+
+func outcome(result model.CheckResult) string {
+	if result.Success {
+		return "OK"
+	}
+	return result.Message
+}
+
+func (s *Scheduler) runOnce(ctx context.Context, target model.Target) model.CheckResult {
 	checkCtx, cancel := context.WithTimeout(ctx, target.Timeout)
 	defer cancel()
+	s.running.Add(1)
+	defer s.running.Add(-1)
 	result := s.runner.Run(checkCtx, target)
 	if result.CheckedAt.IsZero() {
-		result.CheckedAt = time.Now()
-	}
-	s.store.Update(result)
-	if result.Success {
-		s.logger.Printf("target %s OK (%.0fms)", target.ID, result.Duration.Seconds()*1000)
-	} else {
-		s.logger.Printf("target %s fallo: %s", target.ID, result.Message)
+		result.CheckedAt = s.clock.Now()
 	}
+	return result
 }
 
 type noopLogger struct{}
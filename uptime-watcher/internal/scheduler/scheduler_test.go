@@ -0,0 +1,752 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/check"
+	"proyecto-leng-paradigmas/ejemplo/internal/clock"
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+	"proyecto-leng-paradigmas/ejemplo/internal/store"
+)
+
+var epoch = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Los escenarios corren sobre un clock.Fake: el tiempo avanza a mano y cada
+// uno verifica en que instante simulado corre cada chequeo. No hay esperas
+// reales: el harness se sincroniza con el timer del dispatcher a traves de
+// clock.Fake.BlockUntil.
+
+// TestWorkerLifecycle verifica que Start lance un pool fijo y que al cancelar
+// el contexto todas sus goroutines terminen.
+func TestWorkerLifecycle(t *testing.T) {
+	h := newHarness(t)
+	h.sched.SetConcurrency(4)
+	h.start(target("a", time.Minute), target("b", time.Minute), target("c", time.Minute))
+
+	if st := h.sched.Stats(); st.Workers != 4 || st.Targets != 3 {
+		t.Fatalf("stats = %+v, se esperaban 4 workers y 3 targets", st)
+	}
+	if got := schedulerGoroutines(); got != 5 {
+		t.Fatalf("Start lanzo %d goroutines, se esperaban 5 (dispatcher + 4 workers)", got)
+	}
+	// stop espera a que no quede ninguna
+	h.stop()
+}
+
+// TestConcurrencyLimit verifica que con mas chequeos vencidos que workers
+// los restantes esperen en cola.
+func TestConcurrencyLimit(t *testing.T) {
+	h := newHarness(t)
+	h.sched.SetConcurrency(2)
+	h.checker.block()
+	ids := []string{"a", "b", "c", "d", "e"}
+	var targets []model.Target
+	for _, id := range ids {
+		targets = append(targets, target(id, time.Minute))
+	}
+	h.start(targets...)
+	// un solo salto: los cinco vencen a la vez y el dispatcher los saca juntos
+	h.clock.Advance(time.Minute)
+	h.expectCall("")
+	h.expectCall("")
+	h.expectNoPendingCall()
+	if st := h.sched.Stats(); st.Running != 2 || st.Queued != 3 {
+		t.Fatalf("stats = %+v, se esperaban 2 en ejecucion y 3 en cola", st)
+	}
+	h.checker.release()
+	for range 3 {
+		h.expectCall("")
+	}
+	for range ids {
+		h.expectRecord()
+	}
+}
+
+// TestPhasedStart verifica que Start no corra los chequeos enseguida sino en
+// su tick desfasado, y que despues sigan cada Frequency exacto.
+func TestPhasedStart(t *testing.T) {
+	h := newHarness(t)
+	h.start(target("a", 10*time.Second))
+	h.expectNoCall()
+	h.advance(10 * time.Second)
+	first := h.expectCall("a")
+	if first.Before(epoch) || !first.Before(epoch.Add(10*time.Second)) {
+		t.Fatalf("primer chequeo en %s, fuera del primer intervalo", first.Sub(epoch))
+	}
+	h.advance(10 * time.Second)
+	if d := h.expectCall("a").Sub(first); d != 10*time.Second {
+		t.Fatalf("intervalo entre chequeos %s, se esperaba 10s", d)
+	}
+}
+
+// TestUpsertImmediate verifica que un target agregado despues de Start se
+// chequee sin esperar y luego siga su grilla.
+func TestUpsertImmediate(t *testing.T) {
+	h := newHarness(t)
+	h.start()
+	h.sched.UpsertTarget(target("a", 10*time.Second))
+	if at := h.expectCall("a"); !at.Equal(epoch) {
+		t.Fatalf("chequeo en %s, se esperaba inmediato", at.Sub(epoch))
+	}
+	h.expectRecord()
+	h.advance(10 * time.Second)
+	h.expectCall("a")
+}
+
+// TestTriggerKeepsGrid verifica que Trigger corra un chequeo en el instante
+// y que el siguiente regular siga en su lugar de la grilla.
+func TestTriggerKeepsGrid(t *testing.T) {
+	h := newHarness(t)
+	h.start(target("a", 10*time.Second))
+	h.advance(10 * time.Second)
+	first := h.expectCall("a")
+	h.expectRecord()
+
+	now := h.clock.Now()
+	if !h.sched.Trigger("a") {
+		t.Fatal("Trigger devolvio false para un target planificado")
+	}
+	if at := h.expectCall("a"); !at.Equal(now) {
+		t.Fatalf("Trigger corrio en %s, se esperaba %s", at.Sub(epoch), now.Sub(epoch))
+	}
+	h.expectRecord()
+
+	next := first.Add(10 * time.Second)
+	h.advance(next.Sub(h.clock.Now()) - time.Millisecond)
+	h.expectNoCall()
+	h.advance(time.Millisecond)
+	if at := h.expectCall("a"); !at.Equal(next) {
+		t.Fatalf("chequeo regular en %s, se esperaba %s", at.Sub(epoch), next.Sub(epoch))
+	}
+	if h.sched.Trigger("desconocido") {
+		t.Fatal("Trigger devolvio true para un target inexistente")
+	}
+}
+
+// TestTriggerWhileRunning verifica que un Trigger recibido durante el
+// chequeo no se pierda ni lo corra en paralelo: se repite al terminar.
+func TestTriggerWhileRunning(t *testing.T) {
+	h := newHarness(t)
+	h.checker.block()
+	h.start()
+	h.sched.UpsertTarget(target("a", time.Minute))
+	h.expectCall("a")
+	h.sched.Trigger("a")
+	h.expectNoPendingCall()
+	h.checker.release()
+	h.expectRecord()
+	h.expectCall("a")
+}
+
+// TestTriggerRecordsInOrder verifica que el chequeo repetido por un Trigger
+// no corra hasta que el resultado anterior termine de registrarse, asi store
+// y observers reciben los resultados del target en orden.
+func TestTriggerRecordsInOrder(t *testing.T) {
+	h := newHarness(t)
+	slow := &orderObserver{gate: make(chan struct{}), entered: make(chan bool)}
+	slow.sched = h.sched
+	h.sched.AddObserver(slow)
+	h.sched.SetConcurrency(4)
+	h.checker.block()
+	h.start()
+	h.sched.UpsertTarget(target("a", time.Minute))
+	h.expectCall("a")
+	h.sched.Trigger("a")
+	h.checker.release()
+
+	// el primer resultado queda registrandose hasta abrir gate
+	pending := <-slow.entered
+	select {
+	case <-h.checker.calls:
+		pending = false
+	default:
+	}
+	// se libera antes de fallar para que el scheduler pueda detenerse
+	close(slow.gate)
+	if !pending {
+		t.Fatal("el Trigger corrio antes de que se registrara el resultado anterior")
+	}
+	h.expectCall("a")
+	h.expectRecord()
+	h.expectRecord()
+	h.stop()
+	if want := []string{"chequeo 1", "chequeo 2"}; !slices.Equal(slow.order, want) {
+		t.Fatalf("resultados registrados en orden %v, se esperaba %v", slow.order, want)
+	}
+}
+
+// orderObserver anota el orden de los resultados. El primero espera a gate
+// antes de anotarse, e informa por entered si su target seguia en ejecucion
+// con el Trigger pendiente.
+type orderObserver struct {
+	sched   *Scheduler
+	gate    chan struct{}
+	entered chan bool
+
+	mu    sync.Mutex
+	calls int
+	order []string
+}
+
+func (o *orderObserver) Observe(_ context.Context, target model.Target, result model.CheckResult) {
+	o.mu.Lock()
+	o.calls++
+	first := o.calls == 1
+	o.mu.Unlock()
+	if first {
+		o.sched.mu.Lock()
+		e := o.sched.entries[target.ID]
+		pending := e != nil && e.running && e.triggered && e.index < 0
+		o.sched.mu.Unlock()
+		o.entered <- pending
+		<-o.gate
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.order = append(o.order, result.Message)
+}
+
+// TestRenameKeepsCadence verifica que un cambio que no altera el chequeo no
+// lo corra de nuevo, no mueva la grilla y se refleje en el siguiente
+// resultado.
+func TestRenameKeepsCadence(t *testing.T) {
+	h := newHarness(t)
+	h.start(target("a", 10*time.Second))
+	h.advance(10 * time.Second)
+	first := h.expectCall("a")
+	h.expectRecord()
+
+	renamed := target("a", 10*time.Second)
+	renamed.Name = "renombrado"
+	renamed.Tags = []string{"env:prod"}
+	if h.sched.UpsertTarget(renamed) {
+		t.Fatal("UpsertTarget reinicio el target por un cambio de nombre")
+	}
+	h.expectNoCall()
+	h.advance(first.Add(10 * time.Second).Sub(h.clock.Now()))
+	if d := h.expectCall("a").Sub(first); d != 10*time.Second {
+		t.Fatalf("intervalo entre chequeos %s, se esperaba 10s", d)
+	}
+	if got := h.nextRecorded().target.Name; got != "renombrado" {
+		t.Fatalf("el resultado se registro con el nombre %q", got)
+	}
+}
+
+// TestFrequencyHotApply verifica que al cambiar la frecuencia no corra un
+// chequeo extra y que el siguiente quede a no menos de la nueva frecuencia.
+func TestFrequencyHotApply(t *testing.T) {
+	h := newHarness(t)
+	h.start(target("a", 10*time.Second))
+	h.advance(10 * time.Second)
+	first := h.expectCall("a")
+	h.expectRecord()
+
+	if h.sched.UpsertTarget(target("a", time.Minute)) {
+		t.Fatal("UpsertTarget reinicio el target por un cambio de frecuencia")
+	}
+	h.advance(first.Add(time.Minute).Sub(h.clock.Now()) - time.Millisecond)
+	h.expectNoCall()
+	h.advance(time.Minute + time.Millisecond)
+	second := h.expectCall("a")
+	if d := second.Sub(first); d < time.Minute || d >= 2*time.Minute {
+		t.Fatalf("intervalo tras el cambio %s, se esperaba entre 1m y 2m", d)
+	}
+	h.advance(time.Minute)
+	if d := h.expectCall("a").Sub(second); d != time.Minute {
+		t.Fatalf("intervalo con la nueva frecuencia %s, se esperaba 1m", d)
+	}
+}
+
+// TestProbeChangeRestarts verifica que cambiar un campo del chequeo cancele
+// el chequeo en curso y corra uno nuevo enseguida con la nueva
+// configuracion.
+func TestProbeChangeRestarts(t *testing.T) {
+	h := newHarness(t)
+	h.checker.block()
+	h.start()
+	h.sched.UpsertTarget(target("a", time.Minute))
+	h.expectCall("a")
+
+	changed := target("a", time.Minute)
+	changed.Port = 8080
+	if !h.sched.UpsertTarget(changed) {
+		t.Fatal("UpsertTarget no reinicio el target al cambiar el puerto")
+	}
+	h.expectCall("a")
+	h.checker.release()
+	if port := h.nextRecorded().target.Port; port != 8080 {
+		t.Fatalf("se registro el chequeo con el puerto %d", port)
+	}
+	// detenido el scheduler, el chequeo cancelado ya no puede registrarse
+	h.stop()
+	h.expectNoRecord()
+}
+
+// TestCronRuns verifica que un target con cron no corra al agregarse y que
+// luego corra exactamente en los instantes de la expresion, en su zona.
+func TestCronRuns(t *testing.T) {
+	h := newHarness(t)
+	h.start()
+	h.sched.UpsertTarget(cronTarget("a", "0 9 * * *", "America/Argentina/Buenos_Aires"))
+	h.expectNoCall()
+	// 9:00 en Buenos Aires (UTC-3) son las 12:00 UTC
+	for day := range 2 {
+		want := epoch.Add(time.Duration(day)*24*time.Hour + 12*time.Hour)
+		h.advance(want.Sub(h.clock.Now()) - time.Millisecond)
+		h.expectNoCall()
+		h.advance(time.Millisecond)
+		if at := h.expectCall("a"); !at.Equal(want) {
+			t.Fatalf("chequeo en %s, se esperaba %s", at.Sub(epoch), want.Sub(epoch))
+		}
+		h.expectRecord()
+	}
+}
+
+// TestCronHotApply verifica que cambiar la expresion no corra un chequeo
+// extra y que el siguiente siga la expresion nueva.
+func TestCronHotApply(t *testing.T) {
+	h := newHarness(t)
+	h.start(cronTarget("a", "*/10 * * * *", ""))
+	h.advance(10 * time.Minute)
+	h.expectCall("a")
+	h.expectRecord()
+
+	if h.sched.UpsertTarget(cronTarget("a", "*/30 * * * *", "")) {
+		t.Fatal("UpsertTarget reinicio el target por un cambio de cron")
+	}
+	h.advance(20*time.Minute - time.Millisecond)
+	h.expectNoCall()
+	h.advance(time.Millisecond)
+	if at, want := h.expectCall("a"), epoch.Add(30*time.Minute); !at.Equal(want) {
+		t.Fatalf("chequeo en %s, se esperaba %s", at.Sub(epoch), want.Sub(epoch))
+	}
+}
+
+// TestRetryTiming verifica que los reintentos corran cada RetryInterval de
+// reloj simulado y que solo el intento final se registre.
+func TestRetryTiming(t *testing.T) {
+	h := newHarness(t)
+	a := target("a", time.Minute)
+	a.Retries = 2
+	a.RetryInterval = 3 * time.Second
+	h.checker.fail("a", 100)
+	h.start()
+	h.sched.UpsertTarget(a)
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		at := h.expectCall("a")
+		if want := epoch.Add(time.Duration(attempt-1) * 3 * time.Second); !at.Equal(want) {
+			t.Fatalf("intento %d en %s, se esperaba %s", attempt, at.Sub(epoch), want.Sub(epoch))
+		}
+		if attempt == 3 {
+			break
+		}
+		h.settle()
+		h.expectNoRecord()
+		h.advance(3*time.Second - time.Millisecond)
+		h.expectNoCall()
+		h.advance(time.Millisecond)
+	}
+	res := h.expectRecord()
+	if res.Success || res.Attempts != 3 {
+		t.Fatalf("resultado final success=%v attempts=%d, se esperaba fallo en 3 intentos", res.Success, res.Attempts)
+	}
+	h.expectNoCall()
+}
+
+// TestRetryRecovers verifica que un reintento exitoso cierre el chequeo.
+func TestRetryRecovers(t *testing.T) {
+	h := newHarness(t)
+	a := target("a", time.Minute)
+	a.Retries = 3
+	a.RetryInterval = 2 * time.Second
+	h.checker.fail("a", 1)
+	h.start()
+	h.sched.UpsertTarget(a)
+	h.expectCall("a")
+	h.advance(2 * time.Second)
+	h.expectCall("a")
+	res := h.expectRecord()
+	if !res.Success || res.Attempts != 2 {
+		t.Fatalf("resultado final success=%v attempts=%d, se esperaba exito en 2 intentos", res.Success, res.Attempts)
+	}
+	h.expectNoCall()
+}
+
+// harness arma scheduler, store y checker sobre un mismo clock.Fake.
+type harness struct {
+	t        *testing.T
+	clock    *clock.Fake
+	checker  *simChecker
+	store    *store.Store
+	sched    *Scheduler
+	recorded chan recorded
+
+	cancel context.CancelFunc
+}
+
+func newHarness(t *testing.T) *harness {
+	fake := clock.NewFake(epoch)
+	checker := &simChecker{clock: fake, calls: make(chan call, 64), failures: make(map[string]int)}
+	registry := check.NewRegistry()
+	registry.Register(checker)
+
+	st := store.New(nil)
+	st.SetClock(fake)
+	sched := New(&check.Runner{Registry: registry, Clock: fake}, st, testLogger{t, fake})
+	sched.SetClock(fake)
+	h := &harness{t: t, clock: fake, checker: checker, store: st, sched: sched, recorded: make(chan recorded, 64)}
+	sched.AddObserver(h)
+	t.Cleanup(h.stop)
+	return h
+}
+
+// recorded es un resultado notificado a los observers con la configuracion
+// del target con la que se registro.
+type recorded struct {
+	target model.Target
+	result model.CheckResult
+}
+
+func (h *harness) Observe(_ context.Context, target model.Target, result model.CheckResult) {
+	h.recorded <- recorded{target, result}
+}
+
+func (h *harness) start(targets ...model.Target) {
+	for _, t := range targets {
+		h.store.UpsertTarget(t)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	h.cancel = cancel
+	h.sched.Start(ctx)
+	if len(targets) > 0 {
+		h.settle()
+	}
+}
+
+// stop detiene el scheduler y espera a que terminen sus goroutines.
+func (h *harness) stop() {
+	if h.cancel == nil {
+		return
+	}
+	h.cancel()
+	h.cancel = nil
+	h.checker.release()
+	h.sched.Wait()
+	// Wait vuelve con los wg.Done diferidos; las goroutines salen enseguida
+	for schedulerGoroutines() > 0 {
+		runtime.Gosched()
+	}
+}
+
+// settle espera a que el scheduler quede quieto (ver idle): desde ahi nada
+// corre hasta mover el reloj. No sirve con el checker bloqueado.
+func (h *harness) settle() {
+	for {
+		h.clock.BlockUntil(1)
+		if h.idle() {
+			return
+		}
+		runtime.Gosched()
+	}
+}
+
+// idle indica si el dispatcher espera su timer, calculado con el heap
+// vigente, sin intentos en curso. El scheduler solo deja el timer armado
+// mientras espera, y los intentos se marcan al salir del heap.
+func (h *harness) idle() bool {
+	h.sched.mu.Lock()
+	defer h.sched.mu.Unlock()
+	if h.clock.Waiters() == 0 {
+		return false
+	}
+	for _, e := range h.sched.entries {
+		if e.running {
+			return false
+		}
+	}
+	return true
+}
+
+// advance mueve el reloj simulado d, deteniendose en cada vencimiento
+// intermedio para que el dispatcher despache a horario.
+func (h *harness) advance(d time.Duration) {
+	until := h.clock.Now().Add(d)
+	for {
+		h.settle()
+		next, ok := h.clock.Next()
+		if !ok || next.After(until) {
+			break
+		}
+		h.clock.Set(next)
+	}
+	h.clock.Set(until)
+}
+
+// expectCall espera el proximo chequeo (de id, si no es vacio) y devuelve la
+// hora simulada en que corrio. Falla si el scheduler queda quieto sin
+// correrlo.
+func (h *harness) expectCall(id string) time.Time {
+	h.t.Helper()
+	for {
+		// si estaba quieto, cualquier chequeo ya se habia registrado
+		idle := h.idle()
+		select {
+		case c := <-h.checker.calls:
+			if id != "" && c.targetID != id {
+				h.t.Fatalf("corrio %s, se esperaba %s", c.targetID, id)
+			}
+			return c.at
+		default:
+		}
+		if idle {
+			h.t.Fatalf("a las %s no corrio el chequeo esperado de %q", h.clock.Now().Sub(epoch), id)
+		}
+		runtime.Gosched()
+	}
+}
+
+// expectNoCall verifica que, una vez quieto el scheduler, no haya corrido
+// ningun chequeo.
+func (h *harness) expectNoCall() {
+	h.t.Helper()
+	h.settle()
+	h.expectNoPendingCall()
+}
+
+// expectNoPendingCall verifica que no haya chequeos sin leer, sin esperar a
+// que el scheduler quede quieto; sirve con el checker bloqueado.
+func (h *harness) expectNoPendingCall() {
+	h.t.Helper()
+	select {
+	case c := <-h.checker.calls:
+		h.t.Fatalf("chequeo inesperado de %s a las %s", c.targetID, c.at.Sub(epoch))
+	default:
+	}
+}
+
+func (h *harness) expectRecord() model.CheckResult {
+	h.t.Helper()
+	return h.nextRecorded().result
+}
+
+func (h *harness) nextRecorded() recorded {
+	return <-h.recorded
+}
+
+// expectNoRecord verifica que no haya resultados sin leer.
+func (h *harness) expectNoRecord() {
+	h.t.Helper()
+	select {
+	case r := <-h.recorded:
+		h.t.Fatalf("resultado inesperado de %s (intentos %d)", r.result.TargetID, r.result.Attempts)
+	default:
+	}
+}
+
+// schedulerGoroutines cuenta las goroutines vivas lanzadas por Start
+// (dispatchers y workers), hayan empezado a correr o no.
+func schedulerGoroutines() int {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return strings.Count(string(buf[:n]), "created by proyecto-leng-paradigmas/ejemplo/internal/scheduler.(*Scheduler).Start ")
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
+
+type testLogger struct {
+	t     *testing.T
+	clock *clock.Fake
+}
+
+func (l testLogger) Printf(format string, v ...any) {
+	l.t.Logf("[%s] %s", l.clock.Now().Sub(epoch), fmt.Sprintf(format, v...))
+}
+
+const simKind model.TargetKind = "sim"
+
+func target(id string, frequency time.Duration) model.Target {
+	return model.Target{ID: id, Name: id, Kind: simKind, Frequency: frequency, Timeout: time.Minute}
+}
+
+func cronTarget(id, schedule, timezone string) model.Target {
+	return model.Target{ID: id, Name: id, Kind: simKind, Schedule: schedule, Timezone: timezone, Timeout: time.Minute}
+}
+
+type call struct {
+	targetID string
+	at       time.Time
+}
+
+// simChecker registra cada chequeo con la hora simulada. Puede fallar una
+// cantidad de veces por target y bloquearse hasta release para simular
+// chequeos lentos.
+type simChecker struct {
+	clock *clock.Fake
+	calls chan call
+
+	mu       sync.Mutex
+	failures map[string]int
+	gate     chan struct{}
+	seq      int // chequeos corridos, para ordenar los resultados
+}
+
+func (c *simChecker) Spec() check.Spec { return check.Spec{Kind: simKind, Label: "Simulado"} }
+
+func (c *simChecker) Validate(model.Target) error { return nil }
+
+func (c *simChecker) Check(ctx context.Context, target model.Target) model.CheckResult {
+	now := c.clock.Now()
+	c.calls <- call{targetID: target.ID, at: now}
+
+	c.mu.Lock()
+	c.seq++
+	seq := c.seq
+	gate := c.gate
+	fail := c.failures[target.ID] > 0
+	if fail {
+		c.failures[target.ID]--
+	}
+	c.mu.Unlock()
+	if gate != nil {
+		select {
+		case <-gate:
+		case <-ctx.Done():
+		}
+	}
+	if fail {
+		return model.CheckResult{TargetID: target.ID, CheckedAt: now, Message: "fallo simulado"}
+	}
+	return model.CheckResult{TargetID: target.ID, CheckedAt: now, Success: true, Message: fmt.Sprintf("chequeo %d", seq)}
+}
+
+func (c *simChecker) fail(id string, times int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.failures[id] = times
+}
+
+// block hace que los chequeos esperen hasta release.
+func (c *simChecker) block() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gate = make(chan struct{})
+}
+
+func (c *simChecker) release() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.gate != nil {
+		close(c.gate)
+		c.gate = nil
+	}
+}
+
+const memKind model.TargetKind = "mem"
+
+// memChecker responde en memoria, sin red ni esperas.
+type memChecker struct{ clock clock.Clock }
+
+func (c memChecker) Spec() check.Spec { return check.Spec{Kind: memKind, Label: "Memoria"} }
+
+func (c memChecker) Validate(model.Target) error { return nil }
+
+func (c memChecker) Check(_ context.Context, target model.Target) model.CheckResult {
+	return model.CheckResult{TargetID: target.ID, CheckedAt: c.clock.Now(), Success: true}
+}
+
+// countObserver cuenta los resultados registrados y avisa cuando se llega a
+// la cantidad esperada.
+type countObserver struct {
+	mu   sync.Mutex
+	n    int
+	want int
+	done chan struct{}
+}
+
+func (o *countObserver) Observe(context.Context, model.Target, model.CheckResult) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.n++
+	if o.n == o.want {
+		o.done <- struct{}{}
+	}
+}
+
+// wait bloquea hasta que se hayan registrado want resultados en total.
+func (o *countObserver) wait(want int) {
+	o.mu.Lock()
+	o.want = want
+	reached := o.n >= want
+	o.mu.Unlock()
+	if !reached {
+		<-o.done
+	}
+}
+
+// BenchmarkScheduler10kTargets mide rondas completas de 10.000 targets de
+// igual frecuencia sobre un clock.Fake: cada iteracion adelanta el reloj una
+// frecuencia y espera los 10.000 resultados. El checker responde en memoria,
+// asi que se mide solo el costo del dispatcher, el pool y el store.
+func BenchmarkScheduler10kTargets(b *testing.B) {
+	const (
+		targets   = 10000
+		frequency = 10 * time.Second
+	)
+	fake := clock.NewFake(epoch)
+	registry := check.NewRegistry()
+	registry.Register(memChecker{fake})
+
+	list := make([]model.Target, targets)
+	// los targets con desfase 0 corren al iniciar, antes de la primera ronda
+	base := 0
+	for i := range list {
+		list[i] = model.Target{
+			ID:        fmt.Sprintf("t%05d", i),
+			Name:      fmt.Sprintf("target %d", i),
+			Kind:      memKind,
+			Frequency: frequency,
+			Timeout:   frequency,
+		}
+		if phaseOffset(list[i].ID, frequency) == 0 {
+			base++
+		}
+	}
+	st := store.New(list)
+	st.SetClock(fake)
+	sched := New(&check.Runner{Registry: registry, Clock: fake}, st, nil)
+	sched.SetClock(fake)
+	observer := &countObserver{done: make(chan struct{}, 1)}
+	sched.AddObserver(observer)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() {
+		cancel()
+		sched.Wait()
+	}()
+	sched.Start(ctx)
+	observer.wait(base)
+
+	b.ReportAllocs()
+	rounds := 0
+	for b.Loop() {
+		rounds++
+		// con s.mu tomado el dispatcher no puede estar entre leer la hora y
+		// armar su timer mientras el reloj avanza
+		sched.mu.Lock()
+		fake.Advance(frequency)
+		sched.mu.Unlock()
+		observer.wait(base + rounds*targets)
+	}
+	b.ReportMetric(float64(rounds*targets)/b.Elapsed().Seconds(), "checks/s")
+}
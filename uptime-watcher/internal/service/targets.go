@@ -4,21 +4,48 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 
+	"proyecto-leng-paradigmas/ejemplo/internal/check"
+	"proyecto-leng-paradigmas/ejemplo/internal/clock"
+	"proyecto-leng-paradigmas/ejemplo/internal/cron"
 	"proyecto-leng-paradigmas/ejemplo/internal/db"
 	"proyecto-leng-paradigmas/ejemplo/internal/model"
 	"proyecto-leng-paradigmas/ejemplo/internal/scheduler"
 	"proyecto-leng-paradigmas/ejemplo/internal/store"
 )
 
+const defaultHistoryLimit = 100
+
+const maxRetries = 10
+
+const (
+	maxTags      = 20
+	maxTagLength = 64
+)
+
+// cronSampleRuns es cuantas ejecuciones futuras de un cron se revisan para
+// estimar su menor separacion.
+const cronSampleRuns = 50
+
+// IncidentResolver cierra el incidente abierto de un target; lo implementa
+// incident.Tracker.
+type IncidentResolver interface {
+	Resolve(ctx context.Context, targetID string, at time.Time) error
+}
+
 // TargetService coordina repositorio, scheduler y store en memoria.
 type TargetService struct {
 	repo      *db.TargetRepository
 	store     *store.Store
 	scheduler *scheduler.Scheduler
+	clock     clock.Clock
+	incidents IncidentResolver
 }
 
 // NewTargetService crea una nueva instancia de TargetService.
@@ -27,9 +54,22 @@ func NewTargetService(repo *db.TargetRepository, store *store.Store, sched *sche
 		repo:      repo,
 		store:     store,
 		scheduler: sched,
+		clock:     clock.Real,
 	}
 }
 
+// SetClock reemplaza el reloj del sistema usado al validar los cron y al
+// resolver incidentes, por ejemplo por un clock.Fake. Debe llamarse antes de usar el servicio.
+func (s *TargetService) SetClock(c clock.Clock) {
+	s.clock = clock.Or(c)
+}
+
+// SetIncidents indica donde cerrar el incidente abierto de un target al
+// pausarlo. Debe llamarse antes de usar el servicio.
+func (s *TargetService) SetIncidents(r IncidentResolver) {
+	s.incidents = r
+}
+
 // Bootstrap carga los targets persistidos en memoria.
 func (s *TargetService) Bootstrap(ctx context.Context) error {
 	targets, err := s.repo.List(ctx)
@@ -39,12 +79,20 @@ func (s *TargetService) Bootstrap(ctx context.Context) error {
 	for _, target := range targets {
 		s.store.UpsertTarget(target)
 	}
-	return nil
+	return s.store.Preload(ctx)
 }
 
-// ListTargets retorna los targets conocidos actualmente.
-func (s *TargetService) ListTargets() []model.Target {
-	return s.store.Targets()
+// ListTargets retorna los targets conocidos actualmente que cumplen el filtro,
+// con los campos secretos enmascarados.
+func (s *TargetService) ListTargets(filter model.TargetFilter) []model.Target {
+	targets := s.store.Targets()
+	out := make([]model.Target, 0, len(targets))
+	for _, t := range targets {
+		if filter.Match(t) {
+			out = append(out, check.Redact(t))
+		}
+	}
+	return out
 }
 
 // CreateTarget inserta un nuevo servicio a monitorear.
@@ -52,7 +100,10 @@ func (s *TargetService) CreateTarget(ctx context.Context, target model.Target) (
 	if target.ID == "" {
 		target.ID = uuid.NewString()
 	}
-	if err := validateTarget(target); err != nil {
+	// sin version guardada solo se descartan mascaras y ClearSecret
+	check.KeepSecrets(&target, model.Target{})
+	target = normalizeTarget(target)
+	if err := validateTarget(target, s.clock.Now()); err != nil {
 		return model.Target{}, err
 	}
 	if err := s.repo.Create(ctx, target); err != nil {
@@ -60,23 +111,74 @@ func (s *TargetService) CreateTarget(ctx context.Context, target model.Target) (
 	}
 	s.store.UpsertTarget(target)
 	s.scheduler.UpsertTarget(target)
-	return target, nil
+	return check.Redact(target), nil
 }
 
-// UpdateTarget reemplaza la configuracion de un servicio.
-func (s *TargetService) UpdateTarget(ctx context.Context, target model.Target) (model.Target, error) {
+// TargetUpdate es el resultado de UpdateTarget.
+type TargetUpdate struct {
+	model.Target
+	// Restarted indica si el cambio reinicio los chequeos del target (y,
+	// salvo con cron, corrio uno enseguida); los cambios que no alteran el
+	// chequeo se aplican sin reiniciar.
+	Restarted bool `json:"restarted"`
+}
+
+// UpdateTarget reemplaza la configuracion de un servicio. Los campos secretos
+// que llegan vacios o enmascarados conservan el valor guardado y los que
+// llegan como check.ClearSecret se borran.
+func (s *TargetService) UpdateTarget(ctx context.Context, target model.Target) (TargetUpdate, error) {
 	if target.ID == "" {
+		return TargetUpdate{}, errors.New("id requerido")
+	}
+	current, err := s.repo.Get(ctx, target.ID)
+	if err != nil {
+		return TargetUpdate{}, err
+	}
+	check.KeepSecrets(&target, current)
+	target = normalizeTarget(target)
+	if err := validateTarget(target, s.clock.Now()); err != nil {
+		return TargetUpdate{}, err
+	}
+	// la pausa se cambia con PauseTarget/ResumeTarget, no al editar
+	target.Paused = current.Paused
+	if err := s.repo.Update(ctx, target); err != nil {
+		return TargetUpdate{}, err
+	}
+	s.store.UpsertTarget(target)
+	restarted := s.scheduler.UpsertTarget(target)
+	return TargetUpdate{Target: check.Redact(target), Restarted: restarted}, nil
+}
+
+// PauseTarget detiene los chequeos de un target conservando su historial y
+// resuelve su incidente abierto, si lo tiene.
+func (s *TargetService) PauseTarget(ctx context.Context, id string) (model.Target, error) {
+	return s.setPaused(ctx, id, true)
+}
+
+// ResumeTarget reanuda los chequeos de un target pausado.
+func (s *TargetService) ResumeTarget(ctx context.Context, id string) (model.Target, error) {
+	return s.setPaused(ctx, id, false)
+}
+
+func (s *TargetService) setPaused(ctx context.Context, id string, paused bool) (model.Target, error) {
+	if id == "" {
 		return model.Target{}, errors.New("id requerido")
 	}
-	if err := validateTarget(target); err != nil {
+	if err := s.repo.SetPaused(ctx, id, paused); err != nil {
 		return model.Target{}, err
 	}
-	if err := s.repo.Update(ctx, target); err != nil {
+	target, err := s.repo.Get(ctx, id)
+	if err != nil {
 		return model.Target{}, err
 	}
 	s.store.UpsertTarget(target)
 	s.scheduler.UpsertTarget(target)
-	return target, nil
+	if paused && s.incidents != nil {
+		if err := s.incidents.Resolve(ctx, id, s.clock.Now()); err != nil {
+			return model.Target{}, fmt.Errorf("target pausado, pero no se pudo resolver su incidente: %w", err)
+		}
+	}
+	return check.Redact(target), nil
 }
 
 // DeleteTarget elimina un servicio de la monitorizacion.
@@ -97,44 +199,139 @@ func (s *TargetService) Trigger(id string) bool {
 	return s.scheduler.Trigger(id)
 }
 
-// History obtiene el historial reciente desde memoria.
-func (s *TargetService) History(id string, limit int) ([]model.CheckResult, error) {
-	return s.store.History(id, limit)
+// History obtiene el historial de un target en el rango indicado. Sin rango ni
+// limite se devuelven los ultimos defaultHistoryLimit resultados.
+func (s *TargetService) History(ctx context.Context, id string, from, to time.Time, limit int) ([]model.CheckResult, error) {
+	if limit <= 0 && from.IsZero() && to.IsZero() {
+		limit = defaultHistoryLimit
+	}
+	return s.store.Query(ctx, id, from, to, limit)
 }
 
-// Status retorna el snapshot actual.
-func (s *TargetService) Status() []model.TargetStatus {
-	return s.store.Status()
+// HistoryFor combina el historial de todos los targets que cumplen el filtro,
+// del resultado mas reciente al mas antiguo.
+func (s *TargetService) HistoryFor(ctx context.Context, filter model.TargetFilter, from, to time.Time, limit int) ([]model.CheckResult, error) {
+	if limit <= 0 && from.IsZero() && to.IsZero() {
+		limit = defaultHistoryLimit
+	}
+	var results []model.CheckResult
+	for _, target := range s.ListTargets(filter) {
+		h, err := s.store.Query(ctx, target.ID, from, to, limit)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, h...)
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].CheckedAt.After(results[j].CheckedAt)
+	})
+	if limit > 0 && limit < len(results) {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// Status retorna el snapshot actual de los targets que cumplen el filtro, con
+// los campos secretos enmascarados.
+func (s *TargetService) Status(filter model.TargetFilter) []model.TargetStatus {
+	statuses := s.store.Status()
+	out := make([]model.TargetStatus, 0, len(statuses))
+	for _, st := range statuses {
+		if filter.Match(st.Target) {
+			st.Target = check.Redact(st.Target)
+			out = append(out, st)
+		}
+	}
+	return out
+}
+
+// Subscribe entrega los eventos en vivo del store. Quien los expone debe
+// enmascarar el target con check.Redact.
+func (s *TargetService) Subscribe(buffer int) (<-chan store.Event, func()) {
+	return s.store.Subscribe(buffer)
+}
+
+// normalizeTarget recorta los campos libres; sin cron la zona horaria no
+// aplica y se descarta.
+func normalizeTarget(target model.Target) model.Target {
+	target.Group = strings.TrimSpace(target.Group)
+	target.Tags = NormalizeTags(target.Tags)
+	target.Schedule = strings.TrimSpace(target.Schedule)
+	target.Timezone = strings.TrimSpace(target.Timezone)
+	if target.Schedule == "" {
+		target.Timezone = ""
+	}
+	return target
 }
 
-func validateTarget(target model.Target) error {
+// validateTarget revisa la configuracion de un target. La separacion minima
+// de un cron se estima con sus proximas ejecuciones a partir de now, que
+// pueden acortarse por un cambio de horario.
+func validateTarget(target model.Target, now time.Time) error {
 	if target.Name == "" {
 		return errors.New("nombre requerido")
 	}
-	switch target.Kind {
-	case model.TargetHTTP:
-		if target.URL == "" {
-			return errors.New("url requerida para targets http")
+	if err := check.Validate(target); err != nil {
+		return err
+	}
+	// period es la menor separacion entre dos chequeos regulares
+	period, periodName := target.Frequency, "frequency"
+	if target.Schedule != "" {
+		if target.Frequency != 0 {
+			return errors.New("frequency y schedule son excluyentes")
 		}
-	case model.TargetTCP:
-		if target.Host == "" || target.Port == 0 {
-			return errors.New("host y port requeridos para targets tcp")
+		sched, err := cron.Parse(target.Schedule, target.Timezone)
+		if err != nil {
+			return err
 		}
-	default:
-		return fmt.Errorf("tipo de target desconocido: %s", target.Kind)
-	}
-	if target.Frequency <= 0 {
+		period = sched.MinInterval(now, cronSampleRuns)
+		periodName = fmt.Sprintf("la separacion minima entre ejecuciones del cron (%s)", period)
+	} else if target.Frequency <= 0 {
 		return errors.New("frequency debe ser mayor a 0")
 	}
 	if target.Timeout <= 0 {
 		return errors.New("timeout debe ser mayor a 0")
 	}
-	if target.Timeout > target.Frequency {
-		return errors.New("timeout no puede ser mayor que frequency")
+	if target.Timeout > period {
+		return fmt.Errorf("timeout no puede ser mayor que %s", periodName)
+	}
+	if len(target.Tags) > maxTags {
+		return fmt.Errorf("como maximo %d tags", maxTags)
+	}
+	for _, tag := range target.Tags {
+		if len(tag) > maxTagLength || strings.ContainsAny(tag, ", ") {
+			return fmt.Errorf("tag invalida %q: sin comas ni espacios, hasta %d caracteres", tag, maxTagLength)
+		}
+	}
+	if target.Retries < 0 || target.Retries > maxRetries {
+		return fmt.Errorf("retries debe estar entre 0 y %d", maxRetries)
+	}
+	if target.RetryInterval < 0 {
+		return errors.New("retry_interval no puede ser negativo")
+	}
+	// todos los intentos deben caber dentro de un periodo
+	if target.Retries > 0 {
+		worst := time.Duration(target.Retries+1)*target.Timeout + time.Duration(target.Retries)*scheduler.RetryInterval(target)
+		if worst > period {
+			return fmt.Errorf("los reintentos pueden tardar hasta %s, mas que %s", worst, periodName)
+		}
 	}
 	return nil
 }
 
+// PreviewRuns es cuantas ejecuciones muestra la vista previa de un cron.
+const PreviewRuns = 5
+
+// NextRuns devuelve las proximas n ejecuciones de un cron a partir de ahora,
+// en su zona horaria.
+func NextRuns(schedule, timezone string, n int) ([]time.Time, error) {
+	sched, err := cron.Parse(schedule, strings.TrimSpace(timezone))
+	if err != nil {
+		return nil, err
+	}
+	return sched.NextN(time.Now(), n), nil
+}
+
 // ParseDurations ayuda a convertir strings en duraciones.
 func ParseDurations(freqStr, timeoutStr string) (time.Duration, time.Duration, error) {
 	freq, err := time.ParseDuration(freqStr)
@@ -147,3 +344,45 @@ func ParseDurations(freqStr, timeoutStr string) (time.Duration, time.Duration, e
 	}
 	return freq, timeout, nil
 }
+
+// ParseTags separa una lista de tags escrita como "env:prod, team:pagos".
+func ParseTags(raw string) []string {
+	return NormalizeTags(strings.Split(raw, ","))
+}
+
+// NormalizeTags recorta espacios y descarta tags vacias o repetidas,
+// conservando el orden.
+func NormalizeTags(tags []string) []string {
+	var out []string
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		out = append(out, tag)
+	}
+	return out
+}
+
+// ParseRetries convierte los campos de reintento de un formulario. Strings
+// vacios equivalen a sin reintentos.
+func ParseRetries(retriesStr, intervalStr string) (int, time.Duration, error) {
+	var (
+		retries  int
+		interval time.Duration
+		err      error
+	)
+	if retriesStr != "" {
+		if retries, err = strconv.Atoi(retriesStr); err != nil {
+			return 0, 0, fmt.Errorf("retries invalido: %w", err)
+		}
+	}
+	if intervalStr != "" {
+		if interval, err = time.ParseDuration(intervalStr); err != nil {
+			return 0, 0, fmt.Errorf("retry_interval invalido: %w", err)
+		}
+	}
+	return retries, interval, nil
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	_ "proyecto-leng-paradigmas/ejemplo/internal/check/all"
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+)
+
+// TestValidateTargetCronUsesNow verifica que la separacion minima de un cron
+// se mida desde el instante recibido y no desde la hora real: el mismo target
+// es invalido si sus proximas ejecuciones cruzan un cambio de horario.
+func TestValidateTargetCronUsesNow(t *testing.T) {
+	target := model.Target{
+		Name:     "cron",
+		Kind:     model.TargetHTTP,
+		URL:      "https://example.com",
+		Schedule: "0 1,3 * * *",
+		Timezone: "America/New_York",
+		Timeout:  90 * time.Minute,
+	}
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// sin cambio de horario entre 1:00 y 3:00 pasan 2h
+	if err := validateTarget(target, time.Date(2026, 4, 1, 0, 0, 0, 0, ny)); err != nil {
+		t.Fatalf("validateTarget en abril = %v", err)
+	}
+	// el 8 de marzo de 2026 el reloj salta de 2:00 a 3:00 y pasa 1h
+	err = validateTarget(target, time.Date(2026, 3, 1, 0, 0, 0, 0, ny))
+	if err == nil || !strings.Contains(err.Error(), "1h0m0s") {
+		t.Fatalf("validateTarget antes del cambio de horario = %v, se esperaba un error por la separacion de 1h", err)
+	}
+}
@@ -0,0 +1,249 @@
+// Package statuspage arma la vista publica del estado de los servicios: solo
+// incluye los targets marcados como publicos y nunca expone URLs, hosts ni
+// mensajes de error.
+package statuspage
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/db"
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+)
+
+// Days es la cantidad de dias que cubren las barras de uptime.
+const Days = 90
+
+// DefaultTTL es cuanto se reutiliza una pagina ya calculada.
+const DefaultTTL = time.Minute
+
+const (
+	incidentDays = 30
+	maxIncidents = 10
+)
+
+// Estados publicos de componentes, grupos y del sistema.
+const (
+	StateOperational   = "operational"
+	StatePartialOutage = "partial_outage"
+	StateMajorOutage   = "major_outage"
+	StateMaintenance   = "maintenance"
+	StateUnknown       = "unknown"
+)
+
+// StatusSource entrega el estado actual de los targets.
+type StatusSource interface {
+	Status() []model.TargetStatus
+}
+
+// DailySource entrega el uptime diario persistido de un target.
+type DailySource interface {
+	Daily(ctx context.Context, targetID string, since time.Time) ([]model.DailyUptime, error)
+}
+
+// IncidentSource entrega los incidentes registrados.
+type IncidentSource interface {
+	List(ctx context.Context, filter db.IncidentFilter) ([]model.Incident, error)
+}
+
+// Page es la vista publica completa.
+type Page struct {
+	State     string     `json:"state"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	Groups    []Group    `json:"groups"`
+	Incidents []Incident `json:"incidents"`
+}
+
+// Group reune componentes bajo un nombre (el grupo del target).
+type Group struct {
+	Name       string      `json:"name"`
+	State      string      `json:"state"`
+	Components []Component `json:"components"`
+}
+
+// Component es un target publico.
+type Component struct {
+	Name   string  `json:"name"`
+	State  string  `json:"state"`
+	Uptime float64 `json:"uptime_90d"`
+	Days   []Day   `json:"days"`
+}
+
+// Day es una barra de uptime; Checks en 0 indica que no hubo datos.
+type Day struct {
+	Date   string  `json:"date"`
+	Checks int     `json:"checks"`
+	Uptime float64 `json:"uptime"`
+}
+
+// Incident es la version publica de un incidente, sin el error original.
+type Incident struct {
+	Component  string        `json:"component"`
+	StartedAt  time.Time     `json:"started_at"`
+	ResolvedAt *time.Time    `json:"resolved_at,omitempty"`
+	Duration   time.Duration `json:"duration"`
+}
+
+// Builder calcula la pagina publica y la mantiene en cache durante TTL.
+type Builder struct {
+	status    StatusSource
+	daily     DailySource
+	incidents IncidentSource
+	TTL       time.Duration
+
+	mu      sync.Mutex
+	cached  Page
+	builtAt time.Time
+}
+
+// NewBuilder crea un Builder con DefaultTTL.
+func NewBuilder(status StatusSource, daily DailySource, incidents IncidentSource) *Builder {
+	return &Builder{status: status, daily: daily, incidents: incidents, TTL: DefaultTTL}
+}
+
+// Page devuelve la pagina publica, recalculandola si la cache vencio.
+func (b *Builder) Page(ctx context.Context) (Page, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	if !b.builtAt.IsZero() && now.Sub(b.builtAt) < b.TTL {
+		return b.cached, nil
+	}
+	page, err := b.build(ctx, now)
+	if err != nil {
+		return Page{}, err
+	}
+	b.cached, b.builtAt = page, now
+	return page, nil
+}
+
+func (b *Builder) build(ctx context.Context, now time.Time) (Page, error) {
+	today := now.UTC().Truncate(24 * time.Hour)
+	since := today.AddDate(0, 0, -(Days - 1))
+
+	page := Page{UpdatedAt: now, Groups: []Group{}, Incidents: []Incident{}}
+	names := make(map[string]string)
+	index := make(map[string]int)
+	var states []string
+	for _, st := range b.status.Status() {
+		if !st.Target.Public || st.Target.Paused {
+			continue
+		}
+		daily, err := b.daily.Daily(ctx, st.Target.ID, since)
+		if err != nil {
+			return Page{}, err
+		}
+		comp := Component{Name: st.Target.Name, State: componentState(st)}
+		comp.Days, comp.Uptime = dayBars(daily, since)
+		names[st.Target.ID] = comp.Name
+		states = append(states, comp.State)
+
+		i, ok := index[st.Target.Group]
+		if !ok {
+			i = len(page.Groups)
+			index[st.Target.Group] = i
+			page.Groups = append(page.Groups, Group{Name: st.Target.Group})
+		}
+		page.Groups[i].Components = append(page.Groups[i].Components, comp)
+	}
+	for i := range page.Groups {
+		var groupStates []string
+		for _, c := range page.Groups[i].Components {
+			groupStates = append(groupStates, c.State)
+		}
+		page.Groups[i].State = overallState(groupStates)
+	}
+	sort.SliceStable(page.Groups, func(i, j int) bool {
+		if (page.Groups[i].Name == "") != (page.Groups[j].Name == "") {
+			return page.Groups[j].Name == ""
+		}
+		return page.Groups[i].Name < page.Groups[j].Name
+	})
+	page.State = overallState(states)
+
+	if len(names) == 0 {
+		return page, nil
+	}
+	incidents, err := b.incidents.List(ctx, db.IncidentFilter{From: now.AddDate(0, 0, -incidentDays)})
+	if err != nil {
+		return Page{}, err
+	}
+	for _, inc := range incidents {
+		name, ok := names[inc.TargetID]
+		if !ok {
+			continue
+		}
+		page.Incidents = append(page.Incidents, Incident{
+			Component:  name,
+			StartedAt:  inc.StartedAt,
+			ResolvedAt: inc.ResolvedAt,
+			Duration:   inc.Duration,
+		})
+		if len(page.Incidents) == maxIncidents {
+			break
+		}
+	}
+	return page, nil
+}
+
+// dayBars completa los Days dias desde since y calcula el uptime del periodo.
+func dayBars(daily []model.DailyUptime, since time.Time) ([]Day, float64) {
+	byDate := make(map[string]model.DailyUptime, len(daily))
+	for _, d := range daily {
+		byDate[d.Date.Format(time.DateOnly)] = d
+	}
+	days := make([]Day, Days)
+	var checks, successes int
+	for i := range days {
+		date := since.AddDate(0, 0, i).Format(time.DateOnly)
+		days[i].Date = date
+		if d, ok := byDate[date]; ok && d.Checks > 0 {
+			days[i].Checks = d.Checks
+			days[i].Uptime = float64(d.Successes) / float64(d.Checks) * 100
+			checks += d.Checks
+			successes += d.Successes
+		}
+	}
+	if checks == 0 {
+		return days, 0
+	}
+	return days, float64(successes) / float64(checks) * 100
+}
+
+func componentState(st model.TargetStatus) string {
+	switch {
+	case st.LastCheck == nil:
+		return StateUnknown
+	case st.LastCheck.Maintenance:
+		return StateMaintenance
+	case st.LastCheck.Success:
+		return StateOperational
+	default:
+		return StateMajorOutage
+	}
+}
+
+// overallState combina estados de componentes: una caida total si todos estan
+// caidos, parcial si alguno lo esta.
+func overallState(states []string) string {
+	counts := make(map[string]int)
+	for _, s := range states {
+		counts[s]++
+	}
+	switch {
+	case len(states) == 0:
+		return StateUnknown
+	case counts[StateMajorOutage] == len(states):
+		return StateMajorOutage
+	case counts[StateMajorOutage] > 0:
+		return StatePartialOutage
+	case counts[StateMaintenance] > 0:
+		return StateMaintenance
+	case counts[StateOperational] > 0:
+		return StateOperational
+	default:
+		return StateUnknown
+	}
+}
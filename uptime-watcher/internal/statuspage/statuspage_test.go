@@ -0,0 +1,125 @@
+package statuspage
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/db"
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+)
+
+type staticStatus []model.TargetStatus
+
+func (s staticStatus) Status() []model.TargetStatus { return s }
+
+// staticDaily devuelve un dia de hoy con 4 chequeos y 3 exitos para
+// cualquier target, y registra los ids consultados.
+type staticDaily struct{ asked []string }
+
+func (d *staticDaily) Daily(_ context.Context, targetID string, since time.Time) ([]model.DailyUptime, error) {
+	d.asked = append(d.asked, targetID)
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	return []model.DailyUptime{{Date: today, Checks: 4, Successes: 3}}, nil
+}
+
+type staticIncidents []model.Incident
+
+func (s staticIncidents) List(context.Context, db.IncidentFilter) ([]model.Incident, error) {
+	return s, nil
+}
+
+// secretos y datos internos que nunca deben llegar a la pagina publica
+var leaks = []string{
+	"api.interna.example", "10.0.0.7", "s3cr3t-token", "hunter2",
+	"connection refused", "x509: certificado vencido", "env:prod",
+}
+
+func TestPageOnlyPublicTargets(t *testing.T) {
+	failed := &model.CheckResult{Success: false, Message: "dial tcp 10.0.0.7:5432: connection refused"}
+	ok := &model.CheckResult{Success: true, Message: "200 OK", StatusCode: 200}
+	statuses := staticStatus{
+		{
+			Target: model.Target{
+				ID: "web", Name: "Sitio", Kind: model.TargetHTTP, Group: "frontend", Public: true,
+				URL:     "https://api.interna.example/health",
+				Tags:    []string{"env:prod"},
+				Options: map[string]string{"auth_token": "s3cr3t-token", "header.X-Key": "hunter2"},
+			},
+			LastCheck: ok,
+		},
+		{
+			Target:    model.Target{ID: "db", Name: "Base", Kind: model.TargetTCP, Group: "backend", Public: true, Host: "10.0.0.7", Port: 5432},
+			LastCheck: failed,
+		},
+		{
+			Target:    model.Target{ID: "admin", Name: "Panel interno", Kind: model.TargetHTTP, Group: "frontend", URL: "https://api.interna.example/admin"},
+			LastCheck: failed,
+		},
+		{
+			Target:    model.Target{ID: "old", Name: "Legado", Kind: model.TargetHTTP, Public: true, Paused: true},
+			LastCheck: failed,
+		},
+	}
+	started := time.Now().Add(-time.Hour)
+	incidents := staticIncidents{
+		{ID: 1, TargetID: "admin", StartedAt: started, FirstError: "x509: certificado vencido"},
+		{ID: 2, TargetID: "db", StartedAt: started, Duration: time.Hour, FirstError: "dial tcp 10.0.0.7:5432: connection refused"},
+	}
+	daily := &staticDaily{}
+	page, err := NewBuilder(statuses, daily, incidents).Page(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, g := range page.Groups {
+		for _, c := range g.Components {
+			names = append(names, g.Name+"/"+c.Name)
+		}
+	}
+	if got := strings.Join(names, ","); got != "backend/Base,frontend/Sitio" {
+		t.Errorf("componentes = %s, se esperaban solo los publicos sin pausar", got)
+	}
+	if got := strings.Join(daily.asked, ","); got != "web,db" {
+		t.Errorf("uptime consultado para %s, se esperaba solo web y db", got)
+	}
+	if page.State != StatePartialOutage {
+		t.Errorf("estado = %s, se esperaba %s: el target privado caido no cuenta", page.State, StatePartialOutage)
+	}
+	if len(page.Incidents) != 1 || page.Incidents[0].Component != "Base" {
+		t.Errorf("incidentes = %+v, se esperaba solo el de Base", page.Incidents)
+	}
+	if c := page.Groups[1].Components[0]; c.Uptime != 75 || len(c.Days) != Days {
+		t.Errorf("uptime = %v con %d dias, se esperaba 75 con %d", c.Uptime, len(c.Days), Days)
+	}
+
+	raw, err := json.Marshal(page)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, leak := range leaks {
+		if strings.Contains(string(raw), leak) {
+			t.Errorf("la pagina publica expone %q: %s", leak, raw)
+		}
+	}
+	for _, internal := range []string{"Panel interno", "Legado", `"options"`, `"url"`, `"host"`, `"tags"`, `"first_error"`, `"message"`} {
+		if strings.Contains(string(raw), internal) {
+			t.Errorf("la pagina publica contiene %q", internal)
+		}
+	}
+}
+
+func TestPageWithoutPublicTargets(t *testing.T) {
+	statuses := staticStatus{{Target: model.Target{ID: "admin", Name: "Panel"}, LastCheck: &model.CheckResult{}}}
+	incidents := staticIncidents{{TargetID: "admin", StartedAt: time.Now()}}
+	page, err := NewBuilder(statuses, &staticDaily{}, incidents).Page(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Groups) != 0 || len(page.Incidents) != 0 || page.State != StateUnknown {
+		t.Errorf("pagina = %+v, se esperaba vacia y en %s", page, StateUnknown)
+	}
+}
@@ -0,0 +1,94 @@
+package store
+
+import (
+	"sync"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+)
+
+// Tipos de evento publicados por el store.
+const (
+	EventResult     = "result"
+	EventTransition = "transition"
+)
+
+// Estados usados en las transiciones.
+const (
+	StateUnknown = "unknown"
+	StateUp      = "up"
+	StateDown    = "down"
+	// StateMaintenance corresponde a resultados dentro de una ventana de
+	// mantenimiento.
+	StateMaintenance = "maintenance"
+)
+
+// Event se publica con cada resultado y con cada cambio de estado.
+type Event struct {
+	Type   string             `json:"type"`
+	Status model.TargetStatus `json:"status"`
+	From   string             `json:"from,omitempty"`
+	To     string             `json:"to,omitempty"`
+}
+
+// broker reparte eventos a los suscriptores sin bloquear al publicador: si el
+// buffer de un suscriptor esta lleno el evento se descarta para ese
+// suscriptor.
+type broker struct {
+	mu     sync.Mutex
+	nextID int
+	subs   map[int]chan Event
+}
+
+func (b *broker) subscribe(buffer int) (<-chan Event, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subs == nil {
+		b.subs = make(map[int]chan Event)
+	}
+	id := b.nextID
+	b.nextID++
+	ch := make(chan Event, buffer)
+	b.subs[id] = ch
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.subs, id)
+			close(ch)
+		})
+	}
+	return ch, cancel
+}
+
+func (b *broker) publish(ev Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, ch := range b.subs {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+// Subscribe devuelve un canal con los eventos del store y una funcion para
+// cancelar la suscripcion. Los suscriptores lentos pierden eventos en lugar de
+// frenar a los workers del scheduler.
+func (s *Store) Subscribe(buffer int) (<-chan Event, func()) {
+	return s.events.subscribe(buffer)
+}
+
+func stateOf(res model.CheckResult, ok bool) string {
+	switch {
+	case !ok:
+		return StateUnknown
+	case res.Maintenance:
+		return StateMaintenance
+	case res.Success:
+		return StateUp
+	default:
+		return StateDown
+	}
+}
@@ -0,0 +1,174 @@
+package store
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+)
+
+// Window es una ventana fija sobre la que se calculan estadisticas. Refresh
+// es cada cuanto se recalcula: las ventanas largas cambian poco de un minuto
+// al siguiente y recorrerlas es lo mas caro, asi que se refrescan con menos
+// frecuencia.
+type Window struct {
+	Name     string
+	Duration time.Duration
+	Refresh  time.Duration
+}
+
+// Windows son las ventanas reportadas en TargetStatus, de menor a mayor.
+var Windows = []Window{
+	{"1h", time.Hour, DefaultStatsInterval},
+	{"24h", 24 * time.Hour, DefaultStatsInterval},
+	{"7d", 7 * 24 * time.Hour, 15 * time.Minute},
+	{"30d", 30 * 24 * time.Hour, time.Hour},
+	{"90d", 90 * 24 * time.Hour, time.Hour},
+}
+
+// uptimeWindow es la ventana que alimenta TargetStatus.UptimePerc.
+const uptimeWindow = "24h"
+
+// DefaultStatsInterval es cada cuanto se revisa que ventanas recalcular.
+const DefaultStatsInterval = time.Minute
+
+// RefreshStats recalcula las ventanas cuyo Refresh vencio; la primera llamada
+// las calcula todas. Con repositorio cada ventana se agrega en SQL para todos
+// los targets en una sola consulta; sin el, se usa el historial en memoria.
+func (s *Store) RefreshStats(ctx context.Context) error {
+	s.mu.RLock()
+	now := s.clock.Now()
+	repo := s.repo
+	var due []int
+	for i, w := range Windows {
+		if last, ok := s.statsAt[w.Name]; !ok || now.Sub(last) >= w.Refresh {
+			due = append(due, i)
+		}
+	}
+	var memory map[string][]model.Sample
+	if repo == nil {
+		memory = make(map[string][]model.Sample, len(s.targets))
+		for id := range s.targets {
+			memory[id] = toSamples(s.history[id])
+		}
+	}
+	s.mu.RUnlock()
+	if len(due) == 0 {
+		return nil
+	}
+
+	// computed[i] tiene las estadisticas de Windows[due[i]] por target
+	computed := make([]map[string]model.WindowStats, len(due))
+	for i, wi := range due {
+		w := Windows[wi]
+		from := now.Add(-w.Duration)
+		if repo != nil {
+			stats, err := repo.WindowStats(ctx, w.Name, from)
+			if err != nil {
+				return err
+			}
+			computed[i] = stats
+			continue
+		}
+		computed[i] = make(map[string]model.WindowStats, len(memory))
+		for id, samples := range memory {
+			computed[i][id] = windowStats(w.Name, since(samples, from))
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id := range s.targets {
+		// se reemplaza el slice entero: Status puede estar leyendo el anterior
+		stats := make([]model.WindowStats, len(Windows))
+		copy(stats, s.windows[id])
+		for i, w := range Windows {
+			if stats[i].Window == "" {
+				stats[i].Window = w.Name
+			}
+		}
+		for i, wi := range due {
+			st, ok := computed[i][id]
+			if !ok {
+				st = model.WindowStats{Window: Windows[wi].Name}
+			}
+			stats[wi] = st
+		}
+		s.windows[id] = stats
+	}
+	for _, wi := range due {
+		s.statsAt[Windows[wi].Name] = now
+	}
+	return nil
+}
+
+// RunStats recalcula las estadisticas cada interval hasta que ctx se cancele.
+func (s *Store) RunStats(ctx context.Context, interval time.Duration, logger Logger) {
+	s.mu.RLock()
+	ticker := s.clock.NewTicker(interval)
+	s.mu.RUnlock()
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+			if err := s.RefreshStats(ctx); err != nil && ctx.Err() == nil {
+				logger.Printf("no se pudieron recalcular estadisticas: %v", err)
+			}
+		}
+	}
+}
+
+func toSamples(history []model.CheckResult) []model.Sample {
+	out := make([]model.Sample, len(history))
+	for i, res := range history {
+		out[i] = model.Sample{CheckedAt: res.CheckedAt, Duration: res.Duration, Success: res.Success, Maintenance: res.Maintenance}
+	}
+	return out
+}
+
+// since filtra las muestras desde from, sin las tomadas en mantenimiento.
+func since(samples []model.Sample, from time.Time) []model.Sample {
+	out := make([]model.Sample, 0, len(samples))
+	for _, s := range samples {
+		if !s.Maintenance && !s.CheckedAt.Before(from) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func windowStats(name string, samples []model.Sample) model.WindowStats {
+	stats := model.WindowStats{Window: name, Checks: len(samples)}
+	if len(samples) == 0 {
+		return stats
+	}
+	var latencies []time.Duration
+	for _, s := range samples {
+		if s.Success {
+			latencies = append(latencies, s.Duration)
+		}
+	}
+	stats.UptimePerc = float64(len(latencies)) / float64(len(samples)) * 100
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	stats.P50 = percentile(latencies, 50)
+	stats.P90 = percentile(latencies, 90)
+	stats.P95 = percentile(latencies, 95)
+	stats.P99 = percentile(latencies, 99)
+	return stats
+}
+
+// percentile usa el metodo nearest-rank sobre una lista ordenada.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
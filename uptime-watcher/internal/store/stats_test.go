@@ -0,0 +1,119 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/clock"
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+)
+
+var epoch = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func windowChecks(t *testing.T, s *Store, id string) map[string]int {
+	t.Helper()
+	for _, st := range s.Status() {
+		if st.Target.ID != id {
+			continue
+		}
+		out := make(map[string]int, len(st.Windows))
+		for _, w := range st.Windows {
+			out[w.Window] = w.Checks
+		}
+		return out
+	}
+	t.Fatalf("target %s sin estado", id)
+	return nil
+}
+
+func TestRefreshStatsLongWindowsLessOften(t *testing.T) {
+	fake := clock.NewFake(epoch)
+	s := New([]model.Target{{ID: "a", Name: "a"}})
+	s.SetClock(fake)
+	ctx := context.Background()
+
+	record := func() {
+		t.Helper()
+		if err := s.Record(ctx, model.CheckResult{TargetID: "a", CheckedAt: fake.Now(), Success: true}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	record()
+	if err := s.RefreshStats(ctx); err != nil {
+		t.Fatal(err)
+	}
+	got := windowChecks(t, s, "a")
+	for _, w := range Windows {
+		if got[w.Name] != 1 {
+			t.Fatalf("primer calculo: %v, se esperaba 1 chequeo en todas las ventanas", got)
+		}
+	}
+
+	fake.Advance(DefaultStatsInterval)
+	record()
+	if err := s.RefreshStats(ctx); err != nil {
+		t.Fatal(err)
+	}
+	got = windowChecks(t, s, "a")
+	if got["1h"] != 2 || got["24h"] != 2 {
+		t.Errorf("ventanas cortas = %v, se esperaban 2 chequeos en 1h y 24h", got)
+	}
+	if got["7d"] != 1 || got["90d"] != 1 {
+		t.Errorf("ventanas largas = %v, no deberian recalcularse antes de su Refresh", got)
+	}
+
+	fake.Advance(15 * time.Minute)
+	if err := s.RefreshStats(ctx); err != nil {
+		t.Fatal(err)
+	}
+	got = windowChecks(t, s, "a")
+	if got["7d"] != 2 || got["90d"] != 1 {
+		t.Errorf("tras 15m = %v, se esperaba 7d recalculada y 90d no", got)
+	}
+}
+
+// TestRefreshStatsUsesStoreClock verifica que las ventanas se calculen
+// respecto del reloj del store y no de la hora real.
+func TestRefreshStatsUsesStoreClock(t *testing.T) {
+	fake := clock.NewFake(epoch)
+	s := New([]model.Target{{ID: "a", Name: "a"}})
+	s.SetClock(fake)
+	ctx := context.Background()
+	for _, r := range []model.CheckResult{
+		{TargetID: "a", CheckedAt: epoch.Add(-2 * time.Hour), Duration: time.Millisecond},
+		{TargetID: "a", CheckedAt: epoch.Add(-30 * time.Minute), Duration: time.Millisecond, Success: true},
+	} {
+		if err := s.Record(ctx, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expect := func(want map[string]int) {
+		t.Helper()
+		if err := s.RefreshStats(ctx); err != nil {
+			t.Fatal(err)
+		}
+		got := windowChecks(t, s, "a")
+		for name, n := range want {
+			if got[name] != n {
+				t.Errorf("a las %s la ventana %s tiene %d chequeos, se esperaban %d", fake.Now().Sub(epoch), name, got[name], n)
+			}
+		}
+	}
+	expect(map[string]int{"1h": 1, "24h": 2})
+	fake.Advance(23 * time.Hour)
+	expect(map[string]int{"1h": 0, "24h": 1, "7d": 2})
+}
+
+func TestWindowStatsPercentiles(t *testing.T) {
+	var samples []model.Sample
+	for i := 1; i <= 10; i++ {
+		samples = append(samples, model.Sample{Duration: time.Duration(i) * time.Millisecond, Success: true})
+	}
+	samples = append(samples, model.Sample{Duration: time.Second})
+	st := windowStats("1h", samples)
+	if st.Checks != 11 || st.P50 != 5*time.Millisecond || st.P90 != 9*time.Millisecond || st.P99 != 10*time.Millisecond {
+		t.Errorf("windowStats = %+v", st)
+	}
+}
@@ -1,15 +1,35 @@
 package store
 
 import (
+	"context"
 	"errors"
 	"sort"
 	"sync"
+	"time"
 
+	"proyecto-leng-paradigmas/ejemplo/internal/clock"
 	"proyecto-leng-paradigmas/ejemplo/internal/model"
 )
 
 const historyLimit = 100
 
+// ErrTargetNotFound se retorna al consultar un target que no esta registrado.
+var ErrTargetNotFound = errors.New("target no encontrado")
+
+// HistoryRepository persiste resultados para que sobrevivan reinicios.
+type HistoryRepository interface {
+	Insert(ctx context.Context, result model.CheckResult) error
+	List(ctx context.Context, targetID string, from, to time.Time, limit int) ([]model.CheckResult, error)
+	// WindowStats agrega la ventana name, desde since, para todos los
+	// targets con resultados en ella.
+	WindowStats(ctx context.Context, name string, since time.Time) (map[string]model.WindowStats, error)
+}
+
+// Logger define interfaz minima para registrar eventos.
+type Logger interface {
+	Printf(format string, v ...any)
+}
+
 // Store mantiene en memoria los resultados de los chequeos.
 type Store struct {
 	mu       sync.RWMutex
@@ -17,6 +37,11 @@ type Store struct {
 	last     map[string]model.CheckResult
 	history  map[string][]model.CheckResult
 	failures map[string]int
+	windows  map[string][]model.WindowStats
+	statsAt  map[string]time.Time // ultimo calculo de cada ventana
+	repo     HistoryRepository
+	clock    clock.Clock
+	events   broker
 }
 
 // New crea un store pre-cargado con los targets configurados.
@@ -30,7 +55,55 @@ func New(targets []model.Target) *Store {
 		last:     make(map[string]model.CheckResult),
 		history:  make(map[string][]model.CheckResult),
 		failures: make(map[string]int),
+		windows:  make(map[string][]model.WindowStats),
+		statsAt:  make(map[string]time.Time),
+		clock:    clock.Real,
+	}
+}
+
+// SetRepository configura el almacenamiento persistente del historial.
+func (s *Store) SetRepository(repo HistoryRepository) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.repo = repo
+}
+
+// SetClock reemplaza el reloj del sistema usado para las ventanas de
+// estadisticas, por ejemplo por un clock.Fake.
+func (s *Store) SetClock(c clock.Clock) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clock = clock.Or(c)
+}
+
+// Preload recupera desde el repositorio los ultimos resultados de cada target
+// para que el estado en memoria no parta vacio tras un reinicio.
+func (s *Store) Preload(ctx context.Context) error {
+	s.mu.RLock()
+	repo := s.repo
+	ids := make([]string, 0, len(s.targets))
+	for id := range s.targets {
+		ids = append(ids, id)
+	}
+	s.mu.RUnlock()
+	if repo == nil {
+		return nil
+	}
+
+	for _, id := range ids {
+		h, err := repo.List(ctx, id, time.Time{}, time.Time{}, historyLimit)
+		if err != nil {
+			return err
+		}
+		s.mu.Lock()
+		s.history[id] = h
+		if len(h) > 0 {
+			s.last[id] = h[0]
+		}
+		s.failures[id] = countFailures(h)
+		s.mu.Unlock()
 	}
+	return nil
 }
 
 // Targets devuelve la lista de servicios registrados.
@@ -72,13 +145,16 @@ func (s *Store) RemoveTarget(id string) {
 	delete(s.last, id)
 	delete(s.history, id)
 	delete(s.failures, id)
+	delete(s.windows, id)
 }
 
-// Update almacena un nuevo resultado y actualiza estadisticas basicas.
+// Update almacena un nuevo resultado, actualiza estadisticas basicas y
+// publica los eventos correspondientes.
 func (s *Store) Update(result model.CheckResult) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	prev, hadPrev := s.last[result.TargetID]
 	s.last[result.TargetID] = result
 	h := append([]model.CheckResult{result}, s.history[result.TargetID]...)
 	if len(h) > historyLimit {
@@ -86,11 +162,38 @@ func (s *Store) Update(result model.CheckResult) {
 	}
 	s.history[result.TargetID] = h
 
-	if result.Success {
+	switch {
+	case result.Maintenance:
+		// los fallos en mantenimiento no cuentan como consecutivos
+	case result.Success:
 		s.failures[result.TargetID] = 0
-	} else {
+	default:
 		s.failures[result.TargetID]++
 	}
+
+	target, ok := s.targets[result.TargetID]
+	if !ok {
+		return
+	}
+	status := s.statusLocked(target)
+	s.events.publish(Event{Type: EventResult, Status: status})
+	from, to := stateOf(prev, hadPrev), stateOf(result, true)
+	if from != to {
+		s.events.publish(Event{Type: EventTransition, Status: status, From: from, To: to})
+	}
+}
+
+// Record almacena el resultado en memoria y lo persiste si hay repositorio.
+func (s *Store) Record(ctx context.Context, result model.CheckResult) error {
+	s.Update(result)
+
+	s.mu.RLock()
+	repo := s.repo
+	s.mu.RUnlock()
+	if repo == nil {
+		return nil
+	}
+	return repo.Insert(ctx, result)
 }
 
 // Status devuelve el estado actual de todos los targets.
@@ -99,21 +202,8 @@ func (s *Store) Status() []model.TargetStatus {
 	defer s.mu.RUnlock()
 
 	results := make([]model.TargetStatus, 0, len(s.targets))
-	for id, target := range s.targets {
-		last := s.last[id]
-		history := s.history[id]
-		uptime := calculateUptime(history)
-		status := model.TargetStatus{
-			Target:              target,
-			UptimePerc:          uptime,
-			ConsecutiveFailures: s.failures[id],
-		}
-		if !last.CheckedAt.IsZero() {
-			// creamos una copia para evitar data races
-			copy := last
-			status.LastCheck = &copy
-		}
-		results = append(results, status)
+	for _, target := range s.targets {
+		results = append(results, s.statusLocked(target))
 	}
 	sort.Slice(results, func(i, j int) bool {
 		return results[i].Target.ID < results[j].Target.ID
@@ -121,13 +211,36 @@ func (s *Store) Status() []model.TargetStatus {
 	return results
 }
 
+// statusLocked arma el estado de un target; requiere s.mu tomado.
+func (s *Store) statusLocked(target model.Target) model.TargetStatus {
+	status := model.TargetStatus{
+		Target:              target,
+		UptimePerc:          calculateUptime(s.history[target.ID]),
+		ConsecutiveFailures: s.failures[target.ID],
+	}
+	if windows := s.windows[target.ID]; len(windows) > 0 {
+		status.Windows = append([]model.WindowStats(nil), windows...)
+		for _, w := range windows {
+			if w.Window == uptimeWindow && w.Checks > 0 {
+				status.UptimePerc = w.UptimePerc
+			}
+		}
+	}
+	if last, ok := s.last[target.ID]; ok && !last.CheckedAt.IsZero() {
+		// creamos una copia para evitar data races
+		copy := last
+		status.LastCheck = &copy
+	}
+	return status
+}
+
 // History entrega los ultimos chequeos del target.
 func (s *Store) History(targetID string, limit int) ([]model.CheckResult, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	if _, ok := s.targets[targetID]; !ok {
-		return nil, errors.New("target no encontrado")
+		return nil, ErrTargetNotFound
 	}
 
 	h := s.history[targetID]
@@ -140,15 +253,75 @@ func (s *Store) History(targetID string, limit int) ([]model.CheckResult, error)
 	return out, nil
 }
 
-func calculateUptime(history []model.CheckResult) float64 {
-	if len(history) == 0 {
-		return 0
+// Query devuelve resultados del target dentro del rango [from, to]. Si hay
+// repositorio la consulta se resuelve en disco; si no, sobre la memoria.
+func (s *Store) Query(ctx context.Context, targetID string, from, to time.Time, limit int) ([]model.CheckResult, error) {
+	s.mu.RLock()
+	_, ok := s.targets[targetID]
+	repo := s.repo
+	s.mu.RUnlock()
+	if !ok {
+		return nil, ErrTargetNotFound
+	}
+	if repo != nil {
+		return repo.List(ctx, targetID, from, to, limit)
+	}
+
+	h, err := s.History(targetID, 0)
+	if err != nil {
+		return nil, err
+	}
+	out := filterRange(h, from, to)
+	if limit > 0 && limit < len(out) {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+func filterRange(history []model.CheckResult, from, to time.Time) []model.CheckResult {
+	out := make([]model.CheckResult, 0, len(history))
+	for _, res := range history {
+		if !from.IsZero() && res.CheckedAt.Before(from) {
+			continue
+		}
+		if !to.IsZero() && res.CheckedAt.After(to) {
+			continue
+		}
+		out = append(out, res)
+	}
+	return out
+}
+
+// countFailures cuenta los fallos consecutivos al inicio de un historial
+// ordenado del mas reciente al mas antiguo.
+func countFailures(history []model.CheckResult) int {
+	n := 0
+	for _, res := range history {
+		switch {
+		case res.Maintenance:
+			// igual que en Update, los fallos en mantenimiento no cuentan
+		case res.Success:
+			return n
+		default:
+			n++
+		}
 	}
-	successes := 0
+	return n
+}
+
+func calculateUptime(history []model.CheckResult) float64 {
+	total, successes := 0, 0
 	for _, res := range history {
+		if res.Maintenance {
+			continue
+		}
+		total++
 		if res.Success {
 			successes++
 		}
 	}
-	return float64(successes) / float64(len(history)) * 100
+	if total == 0 {
+		return 0
+	}
+	return float64(successes) / float64(total) * 100
 }
@@ -0,0 +1,56 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+)
+
+func TestCountFailuresMatchesUpdate(t *testing.T) {
+	const (
+		ok    = "ok"
+		fail  = "fail"
+		maint = "maint"
+	)
+	tests := []struct {
+		name string
+		// del mas antiguo al mas reciente
+		results []string
+		want    int
+	}{
+		{"vacio", nil, 0},
+		{"ultimo exitoso", []string{fail, fail, ok}, 0},
+		{"racha", []string{ok, fail, fail}, 2},
+		{"mantenimiento en el medio", []string{ok, fail, maint, maint, fail}, 2},
+		{"mantenimiento al final", []string{ok, fail, maint}, 1},
+		{"solo mantenimiento", []string{maint, maint}, 0},
+		{"mantenimiento antes de un exito", []string{fail, maint, ok, fail}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New([]model.Target{{ID: "a"}})
+			var history []model.CheckResult
+			for i, kind := range tt.results {
+				res := model.CheckResult{
+					TargetID:    "a",
+					CheckedAt:   epoch.Add(time.Duration(i) * time.Minute),
+					Success:     kind == ok,
+					Maintenance: kind == maint,
+				}
+				s.Update(res)
+				history = append([]model.CheckResult{res}, history...)
+			}
+			if got := countFailures(history); got != tt.want {
+				t.Errorf("countFailures = %d, se esperaba %d", got, tt.want)
+			}
+			// tras un reinicio Preload debe reconstruir el mismo valor que Update
+			s.mu.RLock()
+			live := s.failures["a"]
+			s.mu.RUnlock()
+			if live != tt.want {
+				t.Errorf("Update dejo %d fallos consecutivos, se esperaba %d", live, tt.want)
+			}
+		})
+	}
+}
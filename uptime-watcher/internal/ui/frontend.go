@@ -1,13 +1,18 @@
 package ui
 
 import (
+	"fmt"
 	"html/template"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"proyecto-leng-paradigmas/ejemplo/internal/auth"
+	"proyecto-leng-paradigmas/ejemplo/internal/check"
+	"proyecto-leng-paradigmas/ejemplo/internal/maintenance"
 	"proyecto-leng-paradigmas/ejemplo/internal/model"
 	"proyecto-leng-paradigmas/ejemplo/internal/service"
 	"proyecto-leng-paradigmas/ejemplo/internal/store"
@@ -15,13 +20,153 @@ import (
 
 // Frontend renderiza una vista HTML simple con el estado de los servicios y expone formularios CRUD.
 type Frontend struct {
-	store *store.Store
-	svc   *service.TargetService
-	tpl   *template.Template
+	store       *store.Store
+	svc         *service.TargetService
+	maintenance *maintenance.Schedule
+	tpl         *template.Template
+}
+
+// fieldArgs agrupa los datos que necesita la plantilla "field".
+type fieldArgs struct {
+	Kind  model.TargetKind
+	Field check.Field
+	Value string
+}
+
+// statusClass resume el estado de un target en la clase CSS de su badge.
+func statusClass(status model.TargetStatus) string {
+	if status.Target.Paused {
+		return "paused"
+	}
+	if status.LastCheck == nil {
+		return "unknown"
+	}
+	if status.LastCheck.Maintenance {
+		return "maintenance"
+	}
+	if status.LastCheck.Success {
+		return "up"
+	}
+	return "down"
+}
+
+// stateLabels traduce las clases de estado a los textos de los badges.
+var stateLabels = map[string]string{
+	"up":          "UP",
+	"down":        "DOWN",
+	"maintenance": "MANTENIMIENTO",
+	"paused":      "PAUSADO",
+	"unknown":     "Sin datos",
+}
+
+// statusGroup agrupa las filas del dashboard con su estado agregado.
+type statusGroup struct {
+	Name     string
+	Statuses []model.TargetStatus
+	Counts   map[string]int
+}
+
+// Count devuelve cuantos targets del grupo estan en el estado indicado.
+func (g statusGroup) Count(state string) int {
+	return g.Counts[state]
+}
+
+// State es el peor estado del grupo: down si algun target esta caido, up si
+// todos los activos estan arriba.
+func (g statusGroup) State() string {
+	switch {
+	case g.Counts["down"] > 0:
+		return "down"
+	case g.Counts["up"] > 0 && g.Counts["unknown"] == 0:
+		return "up"
+	case g.Counts["maintenance"] > 0:
+		return "maintenance"
+	case g.Counts["paused"] == len(g.Statuses):
+		return "paused"
+	default:
+		return "unknown"
+	}
+}
+
+// groupStatuses arma los grupos ordenados por nombre; los targets sin grupo
+// quedan al final.
+func groupStatuses(statuses []model.TargetStatus) []statusGroup {
+	index := make(map[string]int)
+	var groups []statusGroup
+	for _, st := range statuses {
+		i, ok := index[st.Target.Group]
+		if !ok {
+			i = len(groups)
+			index[st.Target.Group] = i
+			groups = append(groups, statusGroup{Name: st.Target.Group, Counts: make(map[string]int)})
+		}
+		groups[i].Statuses = append(groups[i].Statuses, st)
+		groups[i].Counts[statusClass(st)]++
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if (groups[i].Name == "") != (groups[j].Name == "") {
+			return groups[j].Name == ""
+		}
+		return groups[i].Name < groups[j].Name
+	})
+	return groups
+}
+
+// groupNames devuelve los grupos existentes, para sugerencias y filtros.
+func groupNames(targets []model.Target) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, t := range targets {
+		if t.Group != "" && !seen[t.Group] {
+			seen[t.Group] = true
+			names = append(names, t.Group)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// timingSegment es un tramo de la barra apilada de tiempos HTTP.
+type timingSegment struct {
+	Name    string
+	Class   string
+	Percent float64
+	Value   time.Duration
+}
+
+// timingSegments reparte las fases de un chequeo HTTP en porcentajes del total.
+func timingSegments(res *model.CheckResult) []timingSegment {
+	if res == nil || res.Timing == nil {
+		return nil
+	}
+	t := res.Timing
+	segments := []timingSegment{
+		{Name: "DNS", Class: "dns", Value: t.DNS},
+		{Name: "Conexión", Class: "connect", Value: t.Connect},
+		{Name: "TLS", Class: "tls", Value: t.TLS},
+		{Name: "Espera (TTFB)", Class: "ttfb", Value: t.TTFB},
+		{Name: "Transferencia", Class: "transfer", Value: t.Transfer},
+	}
+	var total time.Duration
+	for _, seg := range segments {
+		total += seg.Value
+	}
+	if total <= 0 {
+		return nil
+	}
+	out := segments[:0]
+	for _, seg := range segments {
+		if seg.Value <= 0 {
+			continue
+		}
+		seg.Percent = float64(seg.Value) / float64(total) * 100
+		out = append(out, seg)
+	}
+	return out
 }
 
 // New crea una instancia lista para usar.
-func New(store *store.Store, svc *service.TargetService) (*Frontend, error) {
+func New(store *store.Store, svc *service.TargetService, windows *maintenance.Schedule) (*Frontend, error) {
 	funcs := template.FuncMap{
 		"since": func(t *model.CheckResult) string {
 			if t == nil {
@@ -35,32 +180,69 @@ func New(store *store.Store, svc *service.TargetService) (*Frontend, error) {
 			}
 			return t.Duration.Round(time.Millisecond).String()
 		},
-		"statusClass": func(status model.TargetStatus) string {
-			if status.LastCheck == nil {
-				return "unknown"
-			}
-			if status.LastCheck.Success {
-				return "up"
-			}
-			return "down"
+		"statusClass": statusClass,
+		"stateLabel": func(state string) string {
+			return stateLabels[state]
 		},
+		"join": strings.Join,
 		"formatDuration": func(d time.Duration) string {
 			if d <= 0 {
 				return ""
 			}
 			return d.String()
 		},
-		"isHTTP": func(kind model.TargetKind) bool {
-			return kind == model.TargetHTTP
+		"certExpiry": func(info *model.TLSInfo) string {
+			switch {
+			case info.DaysLeft < 0:
+				return fmt.Sprintf("certificado expirado hace %d días", -info.DaysLeft)
+			case info.DaysLeft == 1:
+				return "certificado expira en 1 día"
+			default:
+				return fmt.Sprintf("certificado expira en %d días", info.DaysLeft)
+			}
 		},
-		"isTCP": func(kind model.TargetKind) bool {
-			return kind == model.TargetTCP
+		"timing": timingSegments,
+		"retried": func(res *model.CheckResult) bool {
+			return res != nil && res.Attempts > 1
 		},
-		"portAsString": func(port int) string {
-			if port == 0 {
-				return ""
+		"window": func(status model.TargetStatus, name string) *model.WindowStats {
+			for i := range status.Windows {
+				if status.Windows[i].Window == name && status.Windows[i].Checks > 0 {
+					return &status.Windows[i]
+				}
 			}
-			return strconv.Itoa(port)
+			return nil
+		},
+		"roundLatency": func(d time.Duration) string {
+			return d.Round(time.Millisecond).String()
+		},
+		"roundDuration": func(d time.Duration) string {
+			return d.Round(time.Microsecond).String()
+		},
+		"fieldValue": func(target model.Target, name string) string {
+			return check.FieldValue(target, name)
+		},
+		"fieldArgs": func(kind model.TargetKind, field check.Field, value string) fieldArgs {
+			return fieldArgs{Kind: kind, Field: field, Value: value}
+		},
+		"nextRuns": func(target model.Target) []time.Time {
+			if target.Schedule == "" {
+				return nil
+			}
+			runs, err := service.NextRuns(target.Schedule, target.Timezone, service.PreviewRuns)
+			if err != nil {
+				return nil
+			}
+			return runs
+		},
+		"endpoint": func(target model.Target) string {
+			if target.URL != "" {
+				return target.URL
+			}
+			if target.Port != 0 {
+				return target.Host + ":" + strconv.Itoa(target.Port)
+			}
+			return target.Host
 		},
 	}
 	tpl, err := template.New("index").Funcs(funcs).Parse(indexTemplate)
@@ -68,9 +250,10 @@ func New(store *store.Store, svc *service.TargetService) (*Frontend, error) {
 		return nil, err
 	}
 	return &Frontend{
-		store: store,
-		svc:   svc,
-		tpl:   tpl,
+		store:       store,
+		svc:         svc,
+		maintenance: windows,
+		tpl:         tpl,
 	}, nil
 }
 
@@ -81,17 +264,41 @@ func (f *Frontend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	query := r.URL.Query()
+	filter := model.TargetFilter{
+		Group: strings.TrimSpace(query.Get("group")),
+		Tags:  service.ParseTags(query.Get("tag")),
+	}
+	statuses := f.svc.Status(filter)
+	targets := f.store.Targets()
 	data := struct {
 		GeneratedAt time.Time
+		User        string
+		CSRF        string
 		Statuses    []model.TargetStatus
+		Groups      []statusGroup
+		GroupNames  []string
+		Targets     []model.Target
+		Filter      model.TargetFilter
+		Kinds       []check.Spec
+		Maintenance []maintenanceRow
 		Flash       struct {
 			Success string
 			Error   string
 		}
 	}{
 		GeneratedAt: time.Now(),
-		Statuses:    f.store.Status(),
+		Statuses:    statuses,
+		Groups:      groupStatuses(statuses),
+		GroupNames:  groupNames(targets),
+		Targets:     targets,
+		Filter:      filter,
+		Kinds:       check.Specs(),
+		Maintenance: f.maintenanceRows(time.Now()),
 	}
+	if p, ok := auth.FromContext(r.Context()); ok {
+		data.User = p.Name()
+	}
+	data.CSRF = auth.CSRFToken(auth.SessionValue(r))
 	data.Flash.Success = query.Get("success")
 	data.Flash.Error = query.Get("error")
 
@@ -99,10 +306,23 @@ func (f *Frontend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	_ = f.tpl.Execute(w, data)
 }
 
-// HandleCreate procesa el formulario de creacion desde la UI.
-func (f *Frontend) HandleCreate(w http.ResponseWriter, r *http.Request) {
+// verifyCSRF rechaza formularios sin el token CSRF de la sesion, por ejemplo
+// los enviados desde otro sitio.
+func verifyCSRF(w http.ResponseWriter, r *http.Request) bool {
 	if r.Method != http.MethodPost {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return false
+	}
+	if !auth.ValidCSRF(r) {
+		http.Error(w, "Token CSRF ausente o inválido: recargar la página e intentar de nuevo", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// HandleCreate procesa el formulario de creacion desde la UI.
+func (f *Frontend) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	if !verifyCSRF(w, r) {
 		return
 	}
 	target, err := parseTargetForm(r, "")
@@ -119,8 +339,7 @@ func (f *Frontend) HandleCreate(w http.ResponseWriter, r *http.Request) {
 
 // HandleUpdate procesa el formulario de edicion.
 func (f *Frontend) HandleUpdate(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	if !verifyCSRF(w, r) {
 		return
 	}
 	targetID := strings.TrimSpace(r.FormValue("id"))
@@ -129,17 +348,51 @@ func (f *Frontend) HandleUpdate(w http.ResponseWriter, r *http.Request) {
 		redirectWithFlash(w, r, "", err.Error())
 		return
 	}
-	if _, err := f.svc.UpdateTarget(r.Context(), target); err != nil {
+	res, err := f.svc.UpdateTarget(r.Context(), target)
+	if err != nil {
 		redirectWithFlash(w, r, "", err.Error())
 		return
 	}
+	if res.Restarted {
+		redirectWithFlash(w, r, "Servicio actualizado; se chequea nuevamente con la nueva configuración", "")
+		return
+	}
 	redirectWithFlash(w, r, "Servicio actualizado", "")
 }
 
+// HandlePause pausa un servicio desde la UI.
+func (f *Frontend) HandlePause(w http.ResponseWriter, r *http.Request) {
+	f.handleSetPaused(w, r, true)
+}
+
+// HandleResume reanuda un servicio pausado desde la UI.
+func (f *Frontend) HandleResume(w http.ResponseWriter, r *http.Request) {
+	f.handleSetPaused(w, r, false)
+}
+
+func (f *Frontend) handleSetPaused(w http.ResponseWriter, r *http.Request, paused bool) {
+	if !verifyCSRF(w, r) {
+		return
+	}
+	id := strings.TrimSpace(r.FormValue("id"))
+	if paused {
+		if _, err := f.svc.PauseTarget(r.Context(), id); err != nil {
+			redirectWithFlash(w, r, "", err.Error())
+			return
+		}
+		redirectWithFlash(w, r, "Servicio pausado", "")
+		return
+	}
+	if _, err := f.svc.ResumeTarget(r.Context(), id); err != nil {
+		redirectWithFlash(w, r, "", err.Error())
+		return
+	}
+	redirectWithFlash(w, r, "Servicio reanudado", "")
+}
+
 // HandleDelete elimina un servicio desde la UI.
 func (f *Frontend) HandleDelete(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	if !verifyCSRF(w, r) {
 		return
 	}
 	id := strings.TrimSpace(r.FormValue("id"))
@@ -165,13 +418,15 @@ func parseTargetForm(r *http.Request, forcedID string) (model.Target, error) {
 	}
 	name := strings.TrimSpace(formValue(form, "name"))
 	kind := model.TargetKind(strings.ToLower(strings.TrimSpace(formValue(form, "kind"))))
-	urlValue := strings.TrimSpace(formValue(form, "url"))
-	host := strings.TrimSpace(formValue(form, "host"))
-	portStr := strings.TrimSpace(formValue(form, "port"))
 	freqStr := strings.TrimSpace(formValue(form, "frequency"))
 	timeoutStr := strings.TrimSpace(formValue(form, "timeout"))
+	schedule := strings.TrimSpace(formValue(form, "schedule"))
 
-	if freqStr == "" {
+	// con cron la frecuencia no se usa
+	switch {
+	case schedule != "":
+		freqStr = "0s"
+	case freqStr == "":
 		freqStr = "30s"
 	}
 	if timeoutStr == "" {
@@ -182,25 +437,43 @@ func parseTargetForm(r *http.Request, forcedID string) (model.Target, error) {
 	if err != nil {
 		return model.Target{}, err
 	}
-
-	var port int
-	if portStr != "" {
-		p, err := strconv.Atoi(portStr)
-		if err != nil {
-			return model.Target{}, err
-		}
-		port = p
+	retries, retryInterval, err := service.ParseRetries(
+		strings.TrimSpace(formValue(form, "retries")),
+		strings.TrimSpace(formValue(form, "retry_interval")),
+	)
+	if err != nil {
+		return model.Target{}, err
 	}
 
 	target := model.Target{
 		ID:        id,
 		Name:      name,
 		Kind:      kind,
-		URL:       urlValue,
-		Host:      host,
-		Port:      port,
 		Frequency: freq,
 		Timeout:   timeout,
+		Schedule:  schedule,
+		Timezone:  strings.TrimSpace(formValue(form, "timezone")),
+
+		Retries:       retries,
+		RetryInterval: retryInterval,
+		Group:         strings.TrimSpace(formValue(form, "group")),
+		Tags:          service.ParseTags(formValue(form, "tags")),
+		Public:        formValue(form, "public") != "",
+	}
+
+	// cada tipo declara sus campos; en el formulario llegan como "<kind>.<campo>"
+	checker, ok := check.Lookup(kind)
+	if !ok {
+		return model.Target{}, fmt.Errorf("tipo de target desconocido: %s", kind)
+	}
+	for _, field := range checker.Spec().Fields {
+		value := strings.TrimSpace(formValue(form, string(kind)+"."+field.Name))
+		if field.IsSecret() && formValue(form, string(kind)+"."+field.Name+".clear") != "" {
+			value = check.ClearSecret
+		}
+		if err := check.SetField(&target, field.Name, value); err != nil {
+			return model.Target{}, err
+		}
 	}
 	return target, nil
 }
@@ -232,7 +505,8 @@ const indexTemplate = `
   <title>Monitor de Servicios</title>
   <style>
 	body { font-family: Helvetica, Arial, sans-serif; background: #0f172a; color: #e2e8f0; margin: 0; padding: 0; }
-	header { padding: 1.5rem; background: #1e293b; box-shadow: 0 2px 6px rgba(0,0,0,0.3); }
+	header { padding: 1.5rem; background: #1e293b; box-shadow: 0 2px 6px rgba(0,0,0,0.3); position: relative; }
+	header .session { position: absolute; top: 1.5rem; right: 1.5rem; display: flex; gap: 0.75rem; align-items: center; color: #94a3b8; font-size: 0.85rem; }
 	h1 { margin: 0; font-size: 1.6rem; }
 	main { padding: 1.5rem; display: grid; gap: 1.5rem; }
 	table { width: 100%; border-collapse: collapse; background: #1e293b; border-radius: 12px; overflow: hidden; }
@@ -242,6 +516,8 @@ const indexTemplate = `
 	.status-badge { padding: 0.25rem 0.6rem; border-radius: 999px; font-size: 0.85rem; text-transform: uppercase; letter-spacing: 0.08em; }
 	.status-badge.up { background: rgba(34,197,94,0.2); color: #22c55e; }
 	.status-badge.down { background: rgba(239,68,68,0.2); color: #ef4444; }
+	.status-badge.maintenance { background: rgba(251,191,36,0.2); color: #fbbf24; }
+	.status-badge.paused { background: rgba(148,163,184,0.12); color: #94a3b8; border: 1px dashed #64748b; }
 	.status-badge.unknown { background: rgba(148,163,184,0.2); color: #cbd5f5; }
 	.footer { color: #94a3b8; font-size: 0.85rem; }
 	a { color: #38bdf8; }
@@ -249,22 +525,57 @@ const indexTemplate = `
 	.card h2 { margin-top: 0; font-size: 1.2rem; }
 	.form-grid { display: grid; gap: 0.75rem; grid-template-columns: repeat(auto-fit, minmax(180px, 1fr)); }
 	.form-grid label { display: flex; flex-direction: column; gap: 0.35rem; font-size: 0.85rem; color: #cbd5f5; }
-	input, select { background: #0f172a; border: 1px solid #334155; border-radius: 8px; padding: 0.5rem 0.65rem; color: #e2e8f0; }
-	input:focus, select:focus { outline: none; border-color: #38bdf8; box-shadow: 0 0 0 2px rgba(56,189,248,0.2); }
+	input, select, textarea { background: #0f172a; border: 1px solid #334155; border-radius: 8px; padding: 0.5rem 0.65rem; color: #e2e8f0; }
+	input:focus, select:focus, textarea:focus { outline: none; border-color: #38bdf8; box-shadow: 0 0 0 2px rgba(56,189,248,0.2); }
 	button { padding: 0.55rem 1rem; border-radius: 999px; border: none; cursor: pointer; font-weight: 600; }
 	.button-primary { background: linear-gradient(135deg, #38bdf8, #0ea5e9); color: #0f172a; }
+	.button-secondary { background: #334155; color: #e2e8f0; }
 	.button-danger { background: rgba(239,68,68,0.2); color: #ef4444; border: 1px solid rgba(239,68,68,0.4); }
 	.actions { display: flex; gap: 0.5rem; flex-wrap: wrap; }
 	.flash { padding: 0.75rem 1rem; border-radius: 10px; font-size: 0.95rem; }
 	.flash.success { background: rgba(34,197,94,0.18); color: #4ade80; border: 1px solid rgba(34,197,94,0.3); }
 	.flash.error { background: rgba(239,68,68,0.18); color: #f87171; border: 1px solid rgba(239,68,68,0.3); }
 	details summary { cursor: pointer; color: #38bdf8; }
+	[hidden] { display: none !important; }
+	.cert { color: #94a3b8; }
+	.timing { display: flex; width: 140px; height: 8px; margin-top: 0.35rem; border-radius: 4px; overflow: hidden; background: #334155; }
+	.dns { background: #a78bfa; }
+	.connect { background: #f59e0b; }
+	.tls { background: #f472b6; }
+	.ttfb { background: #38bdf8; }
+	.transfer { background: #22c55e; }
+	.live { color: #22c55e; font-size: 0.85rem; margin-left: 0.5rem; }
+	tr.changed { animation: flash 2s ease-out; }
+	@keyframes flash { from { background: rgba(56,189,248,0.35); } to { background: transparent; } }
+	.legend span { display: inline-block; width: 10px; height: 10px; border-radius: 2px; margin: 0 0.25rem 0 0.75rem; }
+	.cert.bad { color: #f87171; }
+	.form-grid label.checkbox { flex-direction: row; align-items: center; gap: 0.5rem; }
+	.tags { display: flex; flex-wrap: wrap; gap: 0.25rem; margin-top: 0.35rem; }
+	.tag { font-size: 0.72rem; padding: 0.05rem 0.45rem; border-radius: 999px; background: #334155; color: #cbd5f5; text-decoration: none; }
+	tr.group-header td { background: #0f172a; cursor: pointer; padding: 0.6rem 1rem; }
+	tr.group-header small { color: #94a3b8; margin-left: 0.5rem; }
+	tr.group-header .status-badge { margin-left: 0.5rem; font-size: 0.7rem; }
+	tbody.collapsed tr:not(.group-header) { display: none; }
+	tbody.collapsed .caret { display: inline-block; transform: rotate(-90deg); }
+	.filters { display: flex; gap: 0.75rem; align-items: end; flex-wrap: wrap; margin-bottom: 1rem; }
+	.filters label { display: flex; flex-direction: column; gap: 0.35rem; font-size: 0.85rem; color: #cbd5f5; }
+	.attempts { color: #fbbf24; margin-left: 0.35rem; }
+	.windows, .percentiles { display: flex; flex-wrap: wrap; gap: 0.15rem 0.6rem; margin-top: 0.35rem; color: #94a3b8; font-size: 0.75rem; }
+	.schedule-preview { grid-column: 1 / -1; color: #94a3b8; font-size: 0.8rem; }
+	.schedule-preview ol { display: flex; flex-wrap: wrap; gap: 0.2rem 1rem; margin: 0.25rem 0 0; padding-left: 1.2rem; }
   </style>
 </head>
 <body>
   <header>
 	<h1>Monitor de Servicios</h1>
-	<p>Actualizado: {{ .GeneratedAt.Format "2006-01-02 15:04:05" }}</p>
+	<p>Actualizado: {{ .GeneratedAt.Format "2006-01-02 15:04:05" }} · <a href="/status">Página pública</a> <span id="live" class="live" hidden>● en vivo</span></p>
+	{{ if .User }}
+	<form method="post" action="/logout" class="session">
+	  <input type="hidden" name="csrf_token" value="{{ $.CSRF }}">
+	  <span>{{ .User }}</span>
+	  <button type="submit" class="button-secondary">Cerrar sesión</button>
+	</form>
+	{{ end }}
   </header>
   <main>
 	{{ if .Flash.Success }}<div class="flash success">{{ .Flash.Success }}</div>{{ end }}
@@ -273,6 +584,7 @@ const indexTemplate = `
 	<section class="card">
 	  <h2>Agregar nuevo servicio</h2>
 	  <form class="form-grid" action="/ui/targets/create" method="post">
+		<input type="hidden" name="csrf_token" value="{{ $.CSRF }}">
 		<label>ID (opcional)
 		  <input name="id" placeholder="uuid o slug" autocomplete="off">
 		</label>
@@ -280,26 +592,46 @@ const indexTemplate = `
 		  <input name="name" required placeholder="Nombre descriptivo">
 		</label>
 		<label>Tipo
-		  <select name="kind">
-			<option value="http">HTTP</option>
-			<option value="tcp">TCP</option>
+		  <select name="kind" data-kind-select>
+			{{- range .Kinds }}
+			<option value="{{ .Kind }}">{{ .Label }}</option>
+			{{- end }}
 		  </select>
 		</label>
-		<label>URL (HTTP)
-		  <input name="url" placeholder="https://example.com/healthz">
-		</label>
-		<label>Host (TCP)
-		  <input name="host" placeholder="localhost">
-		</label>
-		<label>Puerto (TCP)
-		  <input name="port" type="number" min="1" max="65535" placeholder="5432">
-		</label>
+		{{- range .Kinds }}
+		{{- $kind := .Kind }}
+		{{- range .Fields }}
+		{{ template "field" (fieldArgs $kind . "") }}
+		{{- end }}
+		{{- end }}
 		<label>Frecuencia
 		  <input name="frequency" value="30s" placeholder="ej: 30s, 1m">
 		</label>
+		<label>Cron (opcional, reemplaza la frecuencia)
+		  <input name="schedule" placeholder="ej: */5 * * * *, @hourly" autocomplete="off">
+		</label>
+		<label>Zona horaria del cron
+		  <input name="timezone" placeholder="ej: America/Argentina/Buenos_Aires (UTC por defecto)" autocomplete="off">
+		</label>
+		<div class="schedule-preview" data-schedule-preview hidden></div>
 		<label>Timeout
 		  <input name="timeout" value="5s" placeholder="ej: 5s">
 		</label>
+		<label>Reintentos
+		  <input name="retries" type="number" min="0" max="10" value="0">
+		</label>
+		<label>Grupo
+		  <input name="group" list="group-names" placeholder="ej: pagos" autocomplete="off">
+		</label>
+		<label>Tags
+		  <input name="tags" placeholder="ej: env:prod, team:pagos">
+		</label>
+		<label class="checkbox">
+		  <input type="checkbox" name="public" value="1"> Mostrar en la página pública
+		</label>
+		<label>Espera entre reintentos
+		  <input name="retry_interval" placeholder="ej: 2s (1s por defecto)">
+		</label>
 		<div class="actions">
 		  <button type="submit" class="button-primary">Crear servicio</button>
 		</div>
@@ -308,6 +640,28 @@ const indexTemplate = `
 
 	<section class="card">
 	  <h2>Estado de los servicios</h2>
+	  <form class="filters" method="get" action="/">
+		<label>Grupo
+		  <select name="group">
+			<option value="">Todos</option>
+			{{- range .GroupNames }}
+			<option value="{{ . }}" {{ if eq . $.Filter.Group }}selected{{ end }}>{{ . }}</option>
+			{{- end }}
+		  </select>
+		</label>
+		<label>Tags
+		  <input name="tag" value="{{ join .Filter.Tags ", " }}" placeholder="ej: env:prod">
+		</label>
+		<div class="actions">
+		  <button type="submit" class="button-secondary">Filtrar</button>
+		  {{- if or .Filter.Group .Filter.Tags }}<a href="/">Limpiar</a>{{ end }}
+		</div>
+	  </form>
+	  <datalist id="group-names">
+		{{- range .GroupNames }}
+		<option value="{{ . }}">
+		{{- end }}
+	  </datalist>
 	  <table>
 		<thead>
 		  <tr>
@@ -315,59 +669,127 @@ const indexTemplate = `
 			<th>Estado</th>
 			<th>Último chequeo</th>
 			<th>Latencia</th>
-			<th>Uptime %</th>
+			<th>Uptime % (24h)</th>
 			<th>Frecuencia</th>
 			<th>Timeout</th>
 			<th>Acciones</th>
 		  </tr>
 		</thead>
-		<tbody>
+		{{- range .Groups }}
+		<tbody data-group="{{ .Name }}">
+		  <tr class="group-header" data-group-toggle>
+			<td colspan="8">
+			  <span class="caret">▾</span>
+			  <strong>{{ if .Name }}{{ .Name }}{{ else }}Sin grupo{{ end }}</strong>
+			  <span class="status-badge {{ .State }}" data-field="group-state">{{ stateLabel .State }}</span>
+			  <small data-field="group-counts">{{ .Count "up" }} arriba · {{ .Count "down" }} caídos · {{ len .Statuses }} en total</small>
+			</td>
+		  </tr>
 		  {{- range .Statuses }}
-		  <tr>
+		  {{- $target := .Target }}
+		  <tr data-target="{{ .Target.ID }}"{{ with .LastCheck }} data-checked="{{ .CheckedAt.Format "2006-01-02T15:04:05.000Z07:00" }}"{{ end }}>
+			<td>
+			  <strong>{{ .Target.Name }}</strong>{{ if .Target.Public }} <small class="tag" title="Visible en la página pública">público</small>{{ end }}<br>
+			  <small>{{ .Target.Kind }} • {{ endpoint .Target }}</small>
+			  {{- if .Target.Tags }}
+			  <div class="tags">{{ range .Target.Tags }}<a class="tag" href="/?tag={{ . }}">{{ . }}</a>{{ end }}</div>
+			  {{- end }}
+			  {{- with .LastCheck }}{{ with .TLS }}
+			  <br><small class="cert {{ if or (lt .DaysLeft 0) (not .ChainValid) }}bad{{ end }}" title="{{ .Issuer }}">🔒 {{ certExpiry . }}</small>
+			  {{- end }}{{ end }}
+			</td>
+			<td><span class="status-badge {{ statusClass . }}" data-field="status">{{ if .Target.Paused }}PAUSADO{{ else if .LastCheck }}{{ if .LastCheck.Maintenance }}MANTENIMIENTO{{ else if .LastCheck.Success }}UP{{ else }}DOWN{{ end }}{{ else }}Sin datos{{ end }}</span></td>
+			<td data-field="since">{{ since .LastCheck }}</td>
+			<td>
+			  <span data-field="latency">{{ latency .LastCheck }}</span>
+			  <small class="attempts" data-field="attempts"{{ if not (retried .LastCheck) }} hidden{{ end }}>{{ with .LastCheck }}{{ .Attempts }} intentos{{ end }}</small>
+			  <div class="timing" data-field="timing"{{ if not (timing .LastCheck) }} hidden{{ end }}>
+				{{- range timing .LastCheck }}<span class="{{ .Class }}" style="width: {{ printf "%.1f" .Percent }}%" title="{{ .Name }}: {{ roundDuration .Value }}"></span>{{- end }}
+			  </div>
+			  {{- with window . "24h" }}
+			  <div class="percentiles" title="Percentiles de latencia en 24h">
+				<span>p50 {{ roundLatency .P50 }}</span><span>p90 {{ roundLatency .P90 }}</span><span>p95 {{ roundLatency .P95 }}</span><span>p99 {{ roundLatency .P99 }}</span>
+			  </div>
+			  {{- end }}
+			</td>
 			<td>
-			  <strong>{{ .Target.Name }}</strong><br>
-			  <small>{{ .Target.Kind }} • {{ if eq .Target.Kind "http" }}{{ .Target.URL }}{{ else }}{{ .Target.Host }}:{{ .Target.Port }}{{ end }}</small>
+			  <span data-field="uptime">{{ printf "%.1f" .UptimePerc }}</span>
+			  {{- if .Windows }}
+			  <div class="windows">
+				{{- range .Windows }}
+				<span title="{{ .Checks }} chequeos">{{ .Window }} {{ if .Checks }}{{ printf "%.2f" .UptimePerc }}{{ else }}-{{ end }}</span>
+				{{- end }}
+			  </div>
+			  {{- end }}
 			</td>
-			<td><span class="status-badge {{ statusClass . }}">{{ if .LastCheck }}{{ if .LastCheck.Success }}UP{{ else }}DOWN{{ end }}{{ else }}Sin datos{{ end }}</span></td>
-			<td>{{ since .LastCheck }}</td>
-			<td>{{ latency .LastCheck }}</td>
-			<td>{{ printf "%.1f" .UptimePerc }}</td>
-			<td>{{ formatDuration .Target.Frequency }}</td>
+			<td>{{ if .Target.Schedule }}<code>{{ .Target.Schedule }}</code>{{ with .Target.Timezone }}<br><small>{{ . }}</small>{{ end }}{{ else }}{{ formatDuration .Target.Frequency }}{{ end }}</td>
 			<td>{{ formatDuration .Target.Timeout }}</td>
 			<td>
+			  <form action="/ui/targets/{{ if .Target.Paused }}resume{{ else }}pause{{ end }}" method="post" style="margin-bottom: 0.5rem;">
+				<input type="hidden" name="csrf_token" value="{{ $.CSRF }}">
+				<input type="hidden" name="id" value="{{ .Target.ID }}">
+				<button type="submit" class="button-secondary">{{ if .Target.Paused }}Reanudar{{ else }}Pausar{{ end }}</button>
+			  </form>
 			  <details>
 				<summary>Editar</summary>
 				<form class="form-grid" action="/ui/targets/update" method="post" style="margin-top: 0.75rem;">
+				  <input type="hidden" name="csrf_token" value="{{ $.CSRF }}">
 				  <input type="hidden" name="id" value="{{ .Target.ID }}">
 				  <label>Nombre
 					<input name="name" required value="{{ .Target.Name }}">
 				  </label>
 				  <label>Tipo
-					<select name="kind">
-					  <option value="http" {{ if isHTTP .Target.Kind }}selected{{ end }}>HTTP</option>
-					  <option value="tcp" {{ if isTCP .Target.Kind }}selected{{ end }}>TCP</option>
+					<select name="kind" data-kind-select>
+					  {{- range $.Kinds }}
+					  <option value="{{ .Kind }}" {{ if eq .Kind $target.Kind }}selected{{ end }}>{{ .Label }}</option>
+					  {{- end }}
 					</select>
 				  </label>
-				  <label>URL (HTTP)
-					<input name="url" value="{{ .Target.URL }}">
-				  </label>
-				  <label>Host (TCP)
-					<input name="host" value="{{ .Target.Host }}">
-				  </label>
-				  <label>Puerto (TCP)
-					<input name="port" type="number" min="1" max="65535" value="{{ portAsString .Target.Port }}">
-				  </label>
+				  {{- range $.Kinds }}
+				  {{- $kind := .Kind }}
+				  {{- range .Fields }}
+				  {{ template "field" (fieldArgs $kind . (fieldValue $target .Name)) }}
+				  {{- end }}
+				  {{- end }}
 				  <label>Frecuencia
 					<input name="frequency" value="{{ formatDuration .Target.Frequency }}">
 				  </label>
+				  <label>Cron (opcional, reemplaza la frecuencia)
+					<input name="schedule" value="{{ .Target.Schedule }}" autocomplete="off">
+				  </label>
+				  <label>Zona horaria del cron
+					<input name="timezone" value="{{ .Target.Timezone }}" placeholder="UTC por defecto" autocomplete="off">
+				  </label>
+				  <div class="schedule-preview" data-schedule-preview{{ if not .Target.Schedule }} hidden{{ end }}>
+					{{- with nextRuns .Target }}
+					<small>Próximas ejecuciones:</small>
+					<ol>{{ range . }}<li>{{ .Format "2006-01-02 15:04 MST" }}</li>{{ end }}</ol>
+					{{- end }}
+				  </div>
 				  <label>Timeout
 					<input name="timeout" value="{{ formatDuration .Target.Timeout }}">
 				  </label>
+				  <label>Reintentos
+					<input name="retries" type="number" min="0" max="10" value="{{ .Target.Retries }}">
+				  </label>
+				  <label>Grupo
+					<input name="group" list="group-names" value="{{ .Target.Group }}" autocomplete="off">
+				  </label>
+				  <label>Tags
+					<input name="tags" value="{{ join .Target.Tags ", " }}">
+				  </label>
+				  <label class="checkbox">
+					<input type="checkbox" name="public" value="1"{{ if .Target.Public }} checked{{ end }}> Mostrar en la página pública
+				  </label>
+				  <label>Espera entre reintentos
+					<input name="retry_interval" value="{{ formatDuration .Target.RetryInterval }}" placeholder="1s por defecto">
+				  </label>
 				  <div class="actions">
 					<button type="submit" class="button-primary">Guardar</button>
 				  </div>
 				</form>
 				<form action="/ui/targets/delete" method="post" style="margin-top: 0.5rem;">
+				  <input type="hidden" name="csrf_token" value="{{ $.CSRF }}">
 				  <input type="hidden" name="id" value="{{ .Target.ID }}">
 				  <button type="submit" class="button-danger" onclick="return confirm('¿Eliminar {{ .Target.Name }}?');">Eliminar</button>
 				</form>
@@ -376,10 +798,270 @@ const indexTemplate = `
 		  </tr>
 		  {{- end }}
 		</tbody>
+		{{- end }}
 	  </table>
+	  <p class="footer legend">
+		<span class="dns"></span>DNS <span class="connect"></span>Conexión <span class="tls"></span>TLS <span class="ttfb"></span>Espera <span class="transfer"></span>Transferencia
+	  </p>
 	  <p class="footer">API disponible en <a href="/api/status">/api/status</a></p>
 	</section>
+
+	<section class="card">
+	  <h2>Ventanas de mantenimiento</h2>
+	  {{- if .Maintenance }}
+	  <table>
+		<thead>
+		  <tr>
+			<th>Nombre</th>
+			<th>Servicios</th>
+			<th>Próximo inicio (UTC)</th>
+			<th>Duración</th>
+			<th>Repetición</th>
+			<th></th>
+		  </tr>
+		</thead>
+		<tbody>
+		  {{- range .Maintenance }}
+		  <tr>
+			<td><strong>{{ .Window.Name }}</strong>{{ if .Active }} <span class="status-badge maintenance">en curso</span>{{ end }}</td>
+			<td>{{ .Targets }}</td>
+			<td>{{ .Next.UTC.Format "2006-01-02 15:04" }}</td>
+			<td>{{ formatDuration .Window.Duration }}</td>
+			<td>{{ if eq .Window.Repeat "daily" }}diaria{{ else if eq .Window.Repeat "weekly" }}semanal{{ else }}única{{ end }}</td>
+			<td>
+			  <form action="/ui/maintenance/delete" method="post">
+				<input type="hidden" name="csrf_token" value="{{ $.CSRF }}">
+				<input type="hidden" name="id" value="{{ .Window.ID }}">
+				<button type="submit" class="button-danger" onclick="return confirm('¿Eliminar la ventana {{ .Window.Name }}?');">Eliminar</button>
+			  </form>
+			</td>
+		  </tr>
+		  {{- end }}
+		</tbody>
+	  </table>
+	  {{- else }}
+	  <p class="footer">No hay ventanas programadas.</p>
+	  {{- end }}
+	  <form class="form-grid" action="/ui/maintenance/create" method="post" style="margin-top: 1rem;">
+		<input type="hidden" name="csrf_token" value="{{ $.CSRF }}">
+		<label>Nombre
+		  <input name="name" required placeholder="ej: actualizacion de base">
+		</label>
+		<label>Servicios (sin servicios ni grupos = todos)
+		  <select name="target_ids" multiple>
+			{{- range .Targets }}
+			<option value="{{ .ID }}">{{ .Name }}</option>
+			{{- end }}
+		  </select>
+		</label>
+		<label>Grupos
+		  <select name="groups" multiple>
+			{{- range .GroupNames }}
+			<option value="{{ . }}">{{ . }}</option>
+			{{- end }}
+		  </select>
+		</label>
+		<label>Inicio (UTC)
+		  <input name="start" type="datetime-local" required>
+		</label>
+		<label>Duración
+		  <input name="duration" required placeholder="ej: 1h, 30m">
+		</label>
+		<label>Repetición
+		  <select name="repeat">
+			<option value="">única</option>
+			<option value="daily">diaria</option>
+			<option value="weekly">semanal</option>
+		  </select>
+		</label>
+		<div class="actions">
+		  <button type="submit" class="button-primary">Programar</button>
+		</div>
+	  </form>
+	</section>
   </main>
+  <script>
+	// muestra solo los campos del tipo seleccionado en cada formulario
+	document.querySelectorAll("[data-kind-select]").forEach(function (sel) {
+	  var form = sel.form;
+	  function sync() {
+		form.querySelectorAll("[data-kind]").forEach(function (el) {
+		  el.hidden = el.getAttribute("data-kind") !== sel.value;
+		});
+	  }
+	  sel.addEventListener("change", sync);
+	  sync();
+	});
+
+	// vista previa de las proximas ejecuciones del cron mientras se escribe
+	document.querySelectorAll("[data-schedule-preview]").forEach(function (box) {
+	  var form = box.closest("form");
+	  var schedule = form.querySelector('[name="schedule"]');
+	  var timezone = form.querySelector('[name="timezone"]');
+	  var frequency = form.querySelector('[name="frequency"]');
+	  var timer, seq = 0;
+	  function render(msg, runs) {
+		box.innerHTML = "";
+		var small = document.createElement("small");
+		small.textContent = msg;
+		box.appendChild(small);
+		if (!runs) { return; }
+		var list = document.createElement("ol");
+		runs.forEach(function (r) {
+		  var li = document.createElement("li");
+		  li.textContent = r.replace("T", " ").replace(/:\d\d(\.\d+)?(?=[Z+-])/, " ");
+		  list.appendChild(li);
+		});
+		box.appendChild(list);
+	  }
+	  function refresh() {
+		var expr = schedule.value.trim();
+		frequency.disabled = expr !== "";
+		box.hidden = expr === "";
+		if (!expr) { return; }
+		var mine = ++seq;
+		var q = "schedule=" + encodeURIComponent(expr) + "&timezone=" + encodeURIComponent(timezone.value.trim());
+		fetch("/api/schedule/preview?" + q, { credentials: "same-origin" })
+		  .then(function (res) { return res.json().then(function (body) { return { ok: res.ok, body: body }; }); })
+		  .then(function (r) {
+			if (mine !== seq) { return; }
+			if (r.ok) { render("Próximas ejecuciones:", r.body.next); } else { render(r.body.error || "Expresión inválida"); }
+		  })
+		  .catch(function () { if (mine === seq) { render("No se pudo calcular la vista previa"); } });
+	  }
+	  function later() { clearTimeout(timer); timer = setTimeout(refresh, 300); }
+	  schedule.addEventListener("input", later);
+	  timezone.addEventListener("input", later);
+	  frequency.disabled = schedule.value.trim() !== "";
+	});
+
+	// grupos colapsables; el estado se recuerda en el navegador
+	(function () {
+	  var key = "collapsed-groups";
+	  var collapsed = JSON.parse(localStorage.getItem(key) || "[]");
+	  document.querySelectorAll("tbody[data-group]").forEach(function (body) {
+		var name = body.getAttribute("data-group");
+		if (collapsed.indexOf(name) >= 0) { body.classList.add("collapsed"); }
+		body.querySelector("[data-group-toggle]").addEventListener("click", function () {
+		  body.classList.toggle("collapsed");
+		  collapsed = collapsed.filter(function (g) { return g !== name; });
+		  if (body.classList.contains("collapsed")) { collapsed.push(name); }
+		  localStorage.setItem(key, JSON.stringify(collapsed));
+		});
+	  });
+	})();
+
+	// actualizacion en vivo de las filas via Server-Sent Events
+	(function () {
+	  if (!window.EventSource) { return; }
+	  var phases = [["dns", "DNS"], ["connect", "Conexión"], ["tls", "TLS"], ["ttfb", "Espera (TTFB)"], ["transfer", "Transferencia"]];
+	  function fmt(ns) {
+		var ms = ns / 1e6;
+		if (ms < 1) { return ms > 0 ? (ns / 1e3).toFixed(0) + "µs" : "0s"; }
+		return ms < 1000 ? Math.round(ms) + "ms" : (ms / 1000).toFixed(3) + "s";
+	  }
+	  function field(row, name) { return row.querySelector('[data-field="' + name + '"]'); }
+	  function renderTiming(el, timing) {
+		el.innerHTML = "";
+		var total = 0;
+		phases.forEach(function (p) { total += (timing && timing[p[0]]) || 0; });
+		el.hidden = total <= 0;
+		if (total <= 0) { return; }
+		phases.forEach(function (p) {
+		  var v = timing[p[0]] || 0;
+		  if (v <= 0) { return; }
+		  var span = document.createElement("span");
+		  span.className = p[0];
+		  span.style.width = (v / total * 100).toFixed(1) + "%";
+		  span.title = p[1] + ": " + fmt(v);
+		  el.appendChild(span);
+		});
+	  }
+	  // recalcula el estado agregado del grupo a partir de los badges de sus filas
+	  var labels = { up: "UP", down: "DOWN", maintenance: "MANTENIMIENTO", paused: "PAUSADO", unknown: "Sin datos" };
+	  function refreshGroup(body) {
+		var counts = { up: 0, down: 0, maintenance: 0, paused: 0, unknown: 0 }, total = 0;
+		body.querySelectorAll('tr[data-target] [data-field="status"]').forEach(function (badge) {
+		  total++;
+		  Object.keys(counts).forEach(function (c) { if (badge.classList.contains(c)) { counts[c]++; } });
+		});
+		var state = "unknown";
+		if (counts.down > 0) { state = "down"; }
+		else if (counts.up > 0 && counts.unknown === 0) { state = "up"; }
+		else if (counts.maintenance > 0) { state = "maintenance"; }
+		else if (counts.paused === total) { state = "paused"; }
+		var badge = body.querySelector('[data-field="group-state"]');
+		badge.className = "status-badge " + state;
+		badge.textContent = labels[state];
+		body.querySelector('[data-field="group-counts"]').textContent = counts.up + " arriba · " + counts.down + " caídos · " + total + " en total";
+	  }
+	  function refreshSince() {
+		document.querySelectorAll("tr[data-checked]").forEach(function (row) {
+		  var secs = Math.max(0, Math.round((Date.now() - Date.parse(row.getAttribute("data-checked"))) / 1000));
+		  field(row, "since").textContent = secs < 60 ? secs + "s" : Math.floor(secs / 60) + "m" + (secs % 60) + "s";
+		});
+	  }
+	  function update(status, flash) {
+		var row = document.querySelector('tr[data-target="' + CSS.escape(status.target.id) + '"]');
+		var last = status.last_check;
+		if (!row || !last) { return; }
+		row.setAttribute("data-checked", last.checked_at);
+		var badge = field(row, "status");
+		var state = last.maintenance ? "maintenance" : (last.success ? "up" : "down");
+		badge.className = "status-badge " + state;
+		badge.textContent = last.maintenance ? "MANTENIMIENTO" : (last.success ? "UP" : "DOWN");
+		field(row, "latency").textContent = last.duration > 0 ? fmt(last.duration) : "-";
+		var attempts = field(row, "attempts");
+		attempts.hidden = !(last.attempts > 1);
+		attempts.textContent = last.attempts + " intentos";
+		field(row, "uptime").textContent = status.uptime_perc.toFixed(1);
+		renderTiming(field(row, "timing"), last.timing);
+		refreshGroup(row.closest("tbody"));
+		if (flash) {
+		  row.classList.remove("changed");
+		  void row.offsetWidth;
+		  row.classList.add("changed");
+		}
+		refreshSince();
+	  }
+	  var source = new EventSource("/api/events");
+	  var live = document.getElementById("live");
+	  source.onopen = function () { live.hidden = false; };
+	  source.onerror = function () { live.hidden = true; };
+	  source.addEventListener("result", function (e) { update(JSON.parse(e.data).status, false); });
+	  source.addEventListener("transition", function (e) { update(JSON.parse(e.data).status, true); });
+	  setInterval(refreshSince, 1000);
+	})();
+  </script>
 </body>
 </html>
+{{ define "field" }}
+<label data-kind="{{ .Kind }}">{{ .Field.Label }}
+  {{- if .Field.IsSecret }}
+  {{- /* los secretos nunca se envian al navegador; vacio conserva el guardado */}}
+  {{- $placeholder := .Field.Placeholder }}{{ if .Value }}{{ $placeholder = "configurado; vacío lo conserva" }}{{ end }}
+  {{- if eq .Field.Type "textarea" }}
+  <textarea name="{{ .Kind }}.{{ .Field.Name }}" placeholder="{{ $placeholder }}"></textarea>
+  {{- else }}
+  <input name="{{ .Kind }}.{{ .Field.Name }}" type="password" placeholder="{{ $placeholder }}" value="" autocomplete="new-password">
+  {{- end }}
+  {{- else if eq .Field.Type "textarea" }}
+  <textarea name="{{ .Kind }}.{{ .Field.Name }}" placeholder="{{ .Field.Placeholder }}">{{ .Value }}</textarea>
+  {{- else if eq .Field.Type "select" }}
+  <select name="{{ .Kind }}.{{ .Field.Name }}">
+	{{- $value := .Value }}
+	{{- range .Field.Choices }}
+	<option value="{{ . }}" {{ if eq . $value }}selected{{ end }}>{{ . }}</option>
+	{{- end }}
+  </select>
+  {{- else }}
+  <input name="{{ .Kind }}.{{ .Field.Name }}" type="{{ if .Field.Type }}{{ .Field.Type }}{{ else }}text{{ end }}" placeholder="{{ .Field.Placeholder }}" value="{{ .Value }}">
+  {{- end }}
+</label>
+{{- if and .Field.IsSecret .Value }}
+<label class="checkbox" data-kind="{{ .Kind }}">
+  <input type="checkbox" name="{{ .Kind }}.{{ .Field.Name }}.clear" value="1"> Borrar el valor guardado
+</label>
+{{- end }}
+{{ end }}
 `
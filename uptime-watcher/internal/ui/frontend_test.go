@@ -0,0 +1,176 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/auth"
+	"proyecto-leng-paradigmas/ejemplo/internal/check"
+	_ "proyecto-leng-paradigmas/ejemplo/internal/check/all"
+	"proyecto-leng-paradigmas/ejemplo/internal/db"
+	"proyecto-leng-paradigmas/ejemplo/internal/maintenance"
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+	"proyecto-leng-paradigmas/ejemplo/internal/scheduler"
+	"proyecto-leng-paradigmas/ejemplo/internal/service"
+	"proyecto-leng-paradigmas/ejemplo/internal/store"
+)
+
+// testUI arma el dashboard sobre una base temporal, con el scheduler sin
+// arrancar: los handlers persisten los cambios pero no se corren chequeos.
+type testUI struct {
+	frontend *Frontend
+	login    *Login
+	auth     *auth.Manager
+	targets  *db.TargetRepository
+	session  string
+}
+
+func newTestUI(t *testing.T) *testUI {
+	t.Helper()
+	ctx := context.Background()
+	sqlDB, err := db.OpenSQLite(filepath.Join(t.TempDir(), "ui.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	if _, err := db.Migrate(ctx, sqlDB); err != nil {
+		t.Fatal(err)
+	}
+
+	authn := auth.NewManager(db.NewAuthRepository(sqlDB))
+	if _, err := authn.CreateUser(ctx, "ana", "password-ana"); err != nil {
+		t.Fatal(err)
+	}
+	session, _, err := authn.Login(ctx, "ana", "password-ana")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targets := db.NewTargetRepository(sqlDB)
+	st := store.New(nil)
+	svc := service.NewTargetService(targets, st, scheduler.New(check.NewRunner(), st, nil))
+	frontend, err := New(st, svc, maintenance.NewSchedule(db.NewMaintenanceRepository(sqlDB)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	login, err := NewLogin(authn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testUI{frontend: frontend, login: login, auth: authn, targets: targets, session: session}
+}
+
+// post envia un formulario con la cookie de sesion del usuario de prueba.
+func (u *testUI) post(handler http.HandlerFunc, path string, form url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.AddCookie(&http.Cookie{Name: auth.SessionCookie, Value: u.session})
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	return rec
+}
+
+// invalidTokens son los valores de csrf_token que deben rechazarse.
+func invalidTokens(session string) map[string][]string {
+	return map[string][]string{
+		"sin token":          nil,
+		"token vacio":        {""},
+		"token ajeno":        {auth.CSRFToken("otra-sesion")},
+		"la sesion en crudo": {session},
+	}
+}
+
+func TestCSRFCreate(t *testing.T) {
+	u := newTestUI(t)
+	form := func(id string) url.Values {
+		return url.Values{"id": {id}, "name": {id}, "kind": {"http"}, "http.url": {"https://example.com"}}
+	}
+	for name, token := range invalidTokens(u.session) {
+		f := form("rechazado")
+		f[auth.CSRFField] = token
+		if rec := u.post(u.frontend.HandleCreate, "/ui/targets/create", f); rec.Code != http.StatusForbidden {
+			t.Errorf("%s: codigo %d, se esperaba 403", name, rec.Code)
+		}
+	}
+	if _, err := u.targets.Get(context.Background(), "rechazado"); !errors.Is(err, db.ErrNotFound) {
+		t.Fatalf("se creo un target sin token CSRF valido (err = %v)", err)
+	}
+
+	f := form("web")
+	f.Set(auth.CSRFField, auth.CSRFToken(u.session))
+	rec := u.post(u.frontend.HandleCreate, "/ui/targets/create", f)
+	if rec.Code != http.StatusSeeOther || strings.Contains(rec.Header().Get("Location"), "error") {
+		t.Fatalf("con token valido: codigo %d, Location %q", rec.Code, rec.Header().Get("Location"))
+	}
+	if _, err := u.targets.Get(context.Background(), "web"); err != nil {
+		t.Fatalf("el target no se creo: %v", err)
+	}
+}
+
+func TestCSRFDelete(t *testing.T) {
+	u := newTestUI(t)
+	ctx := context.Background()
+	if err := u.targets.Create(ctx, model.Target{ID: "web", Name: "web", Kind: model.TargetHTTP, URL: "https://example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	for name, token := range invalidTokens(u.session) {
+		f := url.Values{"id": {"web"}, auth.CSRFField: token}
+		if rec := u.post(u.frontend.HandleDelete, "/ui/targets/delete", f); rec.Code != http.StatusForbidden {
+			t.Errorf("%s: codigo %d, se esperaba 403", name, rec.Code)
+		}
+	}
+	if _, err := u.targets.Get(ctx, "web"); err != nil {
+		t.Fatalf("se borro el target sin token CSRF valido: %v", err)
+	}
+
+	f := url.Values{"id": {"web"}, auth.CSRFField: {auth.CSRFToken(u.session)}}
+	if rec := u.post(u.frontend.HandleDelete, "/ui/targets/delete", f); rec.Code != http.StatusSeeOther {
+		t.Fatalf("con token valido: codigo %d", rec.Code)
+	}
+	if _, err := u.targets.Get(ctx, "web"); !errors.Is(err, db.ErrNotFound) {
+		t.Fatalf("el target no se borro (err = %v)", err)
+	}
+}
+
+func TestCSRFLogout(t *testing.T) {
+	u := newTestUI(t)
+	ctx := context.Background()
+	for name, token := range invalidTokens(u.session) {
+		f := url.Values{auth.CSRFField: token}
+		if rec := u.post(u.login.HandleLogout, "/logout", f); rec.Code != http.StatusForbidden {
+			t.Errorf("%s: codigo %d, se esperaba 403", name, rec.Code)
+		}
+	}
+	if _, err := u.auth.SessionUser(ctx, u.session); err != nil {
+		t.Fatalf("la sesion se cerro sin token CSRF valido: %v", err)
+	}
+
+	f := url.Values{auth.CSRFField: {auth.CSRFToken(u.session)}}
+	rec := u.post(u.login.HandleLogout, "/logout", f)
+	if rec.Code != http.StatusSeeOther || rec.Header().Get("Location") != "/login" {
+		t.Fatalf("con token valido: codigo %d, Location %q", rec.Code, rec.Header().Get("Location"))
+	}
+	if _, err := u.auth.SessionUser(ctx, u.session); !errors.Is(err, auth.ErrInvalidCredentials) {
+		t.Fatalf("la sesion sigue abierta tras el logout (err = %v)", err)
+	}
+}
+
+// TestCSRFRequiresPost verifica que los formularios no se acepten por GET,
+// donde un enlace o una imagen de otro sitio podrian dispararlos.
+func TestCSRFRequiresPost(t *testing.T) {
+	u := newTestUI(t)
+	query := url.Values{"id": {"web"}, auth.CSRFField: {auth.CSRFToken(u.session)}}
+	req := httptest.NewRequest(http.MethodGet, "/ui/targets/delete?"+query.Encode(), nil)
+	req.AddCookie(&http.Cookie{Name: auth.SessionCookie, Value: u.session})
+	rec := httptest.NewRecorder()
+	u.frontend.HandleDelete(rec, req)
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("GET: codigo %d, se esperaba 405", rec.Code)
+	}
+}
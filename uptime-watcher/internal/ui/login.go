@@ -0,0 +1,130 @@
+package ui
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/auth"
+)
+
+// Login sirve el formulario de inicio de sesion del dashboard.
+type Login struct {
+	auth *auth.Manager
+	tpl  *template.Template
+}
+
+// NewLogin crea el handler de /login.
+func NewLogin(manager *auth.Manager) (*Login, error) {
+	tpl, err := template.New("login").Parse(loginTemplate)
+	if err != nil {
+		return nil, err
+	}
+	return &Login{auth: manager, tpl: tpl}, nil
+}
+
+// ServeHTTP muestra el formulario (GET) o inicia la sesion (POST).
+func (l *Login) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		l.render(w, r, http.StatusOK, "", "")
+	case http.MethodPost:
+		username := strings.TrimSpace(r.FormValue("username"))
+		session, expires, err := l.auth.Login(r.Context(), username, r.FormValue("password"))
+		if errors.Is(err, auth.ErrInvalidCredentials) {
+			l.render(w, r, http.StatusUnauthorized, username, "Usuario o contraseña incorrectos")
+			return
+		}
+		if err != nil {
+			l.render(w, r, http.StatusInternalServerError, username, "No se pudo iniciar sesión")
+			return
+		}
+		auth.SetSessionCookie(w, r, session, expires)
+		http.Redirect(w, r, safeNext(r.FormValue("next")), http.StatusSeeOther)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (l *Login) render(w http.ResponseWriter, r *http.Request, code int, username, errMsg string) {
+	hasUsers, err := l.auth.HasUsers(r.Context())
+	if err != nil {
+		hasUsers = true
+	}
+	data := struct {
+		Username string
+		Next     string
+		Error    string
+		NoUsers  bool
+	}{
+		Username: username,
+		Next:     safeNext(r.FormValue("next")),
+		Error:    errMsg,
+		NoUsers:  !hasUsers,
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(code)
+	_ = l.tpl.Execute(w, data)
+}
+
+// HandleLogout cierra la sesion actual.
+func (l *Login) HandleLogout(w http.ResponseWriter, r *http.Request) {
+	if !verifyCSRF(w, r) {
+		return
+	}
+	_ = l.auth.Logout(r.Context(), auth.SessionValue(r))
+	auth.ClearSessionCookie(w, r)
+	http.Redirect(w, r, "/login", http.StatusSeeOther)
+}
+
+// safeNext evita redirecciones abiertas: solo se aceptan rutas locales.
+func safeNext(next string) string {
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		return "/"
+	}
+	return next
+}
+
+const loginTemplate = `
+<!DOCTYPE html>
+<html lang="es">
+<head>
+  <meta charset="UTF-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <title>Iniciar sesión · Monitor de Servicios</title>
+  <style>
+	body { font-family: Helvetica, Arial, sans-serif; background: #0f172a; color: #e2e8f0; margin: 0; min-height: 100vh; display: flex; align-items: center; justify-content: center; }
+	.card { background: #1e293b; border-radius: 12px; padding: 1.75rem; box-shadow: 0 10px 30px rgba(15,23,42,0.4); width: 100%; max-width: 340px; }
+	h1 { margin: 0 0 1.25rem; font-size: 1.3rem; }
+	form { display: grid; gap: 0.85rem; }
+	label { display: flex; flex-direction: column; gap: 0.35rem; font-size: 0.85rem; color: #cbd5f5; }
+	input { background: #0f172a; border: 1px solid #334155; border-radius: 8px; padding: 0.55rem 0.65rem; color: #e2e8f0; }
+	input:focus { outline: none; border-color: #38bdf8; box-shadow: 0 0 0 2px rgba(56,189,248,0.2); }
+	button { padding: 0.6rem 1rem; border-radius: 999px; border: none; cursor: pointer; font-weight: 600; background: linear-gradient(135deg, #38bdf8, #0ea5e9); color: #0f172a; }
+	.flash { padding: 0.65rem 0.9rem; border-radius: 10px; font-size: 0.9rem; background: rgba(239,68,68,0.18); color: #f87171; border: 1px solid rgba(239,68,68,0.3); margin-bottom: 1rem; }
+	.hint { color: #94a3b8; font-size: 0.8rem; margin: 1rem 0 0; }
+	code { color: #cbd5f5; }
+	a { color: #38bdf8; }
+  </style>
+</head>
+<body>
+  <div class="card">
+	<h1>Monitor de Servicios</h1>
+	{{ if .Error }}<div class="flash">{{ .Error }}</div>{{ end }}
+	<form method="post" action="/login">
+	  <input type="hidden" name="next" value="{{ .Next }}">
+	  <label>Usuario
+		<input type="text" name="username" value="{{ .Username }}" autocomplete="username" required autofocus>
+	  </label>
+	  <label>Contraseña
+		<input type="password" name="password" autocomplete="current-password" required>
+	  </label>
+	  <button type="submit">Ingresar</button>
+	</form>
+	{{ if .NoUsers }}<p class="hint">No hay usuarios todavía: crear uno con <code>monitor user add -name admin</code>.</p>{{ end }}
+	<p class="hint"><a href="/status">Ver página de estado pública</a></p>
+  </div>
+</body>
+</html>
+`
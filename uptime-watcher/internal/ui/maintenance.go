@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/model"
+)
+
+// datetimeLocal es el formato de los inputs datetime-local; se interpreta en UTC.
+const datetimeLocal = "2006-01-02T15:04"
+
+// maintenanceRow es una ventana lista para mostrar en la tabla.
+type maintenanceRow struct {
+	Window  model.MaintenanceWindow
+	Active  bool
+	Next    time.Time
+	Targets string
+}
+
+func (f *Frontend) maintenanceRows(now time.Time) []maintenanceRow {
+	if f.maintenance == nil {
+		return nil
+	}
+	var rows []maintenanceRow
+	for _, w := range f.maintenance.Upcoming(now) {
+		next, _ := w.NextStart(now)
+		targets := "todos"
+		if len(w.TargetIDs) > 0 || len(w.Groups) > 0 {
+			scope := append([]string(nil), w.TargetIDs...)
+			for _, g := range w.Groups {
+				scope = append(scope, "grupo "+g)
+			}
+			targets = strings.Join(scope, ", ")
+		}
+		rows = append(rows, maintenanceRow{Window: w, Active: w.ActiveAt(now), Next: next, Targets: targets})
+	}
+	return rows
+}
+
+// HandleMaintenanceCreate procesa el formulario de ventanas de mantenimiento.
+func (f *Frontend) HandleMaintenanceCreate(w http.ResponseWriter, r *http.Request) {
+	if !verifyCSRF(w, r) {
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		redirectWithFlash(w, r, "", err.Error())
+		return
+	}
+	start, err := time.ParseInLocation(datetimeLocal, strings.TrimSpace(r.Form.Get("start")), time.UTC)
+	if err != nil {
+		redirectWithFlash(w, r, "", fmt.Sprintf("inicio invalido: %v", err))
+		return
+	}
+	duration, err := time.ParseDuration(strings.TrimSpace(r.Form.Get("duration")))
+	if err != nil {
+		redirectWithFlash(w, r, "", fmt.Sprintf("duracion invalida: %v", err))
+		return
+	}
+	var targetIDs []string
+	for _, id := range r.Form["target_ids"] {
+		if id = strings.TrimSpace(id); id != "" {
+			targetIDs = append(targetIDs, id)
+		}
+	}
+	var groups []string
+	for _, g := range r.Form["groups"] {
+		if g = strings.TrimSpace(g); g != "" {
+			groups = append(groups, g)
+		}
+	}
+	_, err = f.maintenance.Save(r.Context(), model.MaintenanceWindow{
+		Name:      strings.TrimSpace(r.Form.Get("name")),
+		TargetIDs: targetIDs,
+		Groups:    groups,
+		Start:     start,
+		Duration:  duration,
+		Repeat:    model.MaintenanceRepeat(r.Form.Get("repeat")),
+	})
+	if err != nil {
+		redirectWithFlash(w, r, "", err.Error())
+		return
+	}
+	redirectWithFlash(w, r, "Ventana de mantenimiento creada", "")
+}
+
+// HandleMaintenanceDelete elimina una ventana de mantenimiento.
+func (f *Frontend) HandleMaintenanceDelete(w http.ResponseWriter, r *http.Request) {
+	if !verifyCSRF(w, r) {
+		return
+	}
+	id := strings.TrimSpace(r.FormValue("id"))
+	if id == "" {
+		redirectWithFlash(w, r, "", "id requerido para eliminar")
+		return
+	}
+	if err := f.maintenance.Delete(r.Context(), id); err != nil {
+		redirectWithFlash(w, r, "", err.Error())
+		return
+	}
+	redirectWithFlash(w, r, "Ventana de mantenimiento eliminada", "")
+}
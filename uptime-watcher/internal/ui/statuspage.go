@@ -0,0 +1,175 @@
+package ui
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"time"
+
+	"proyecto-leng-paradigmas/ejemplo/internal/statuspage"
+)
+
+// StatusPage renderiza la pagina de estado publica: solo lectura, sin
+// formularios ni datos de conexion de los targets.
+type StatusPage struct {
+	builder *statuspage.Builder
+	tpl     *template.Template
+}
+
+// stateTexts traduce los estados publicos.
+var stateTexts = map[string]string{
+	statuspage.StateOperational:   "Operativo",
+	statuspage.StatePartialOutage: "Interrupción parcial",
+	statuspage.StateMajorOutage:   "Interrupción",
+	statuspage.StateMaintenance:   "En mantenimiento",
+	statuspage.StateUnknown:       "Sin datos",
+}
+
+// overallTexts describen el estado general del sistema.
+var overallTexts = map[string]string{
+	statuspage.StateOperational:   "Todos los sistemas operativos",
+	statuspage.StatePartialOutage: "Algunos sistemas presentan problemas",
+	statuspage.StateMajorOutage:   "Interrupción general del servicio",
+	statuspage.StateMaintenance:   "Mantenimiento en curso",
+	statuspage.StateUnknown:       "Estado no disponible",
+}
+
+// NewStatusPage crea el handler de la pagina publica.
+func NewStatusPage(builder *statuspage.Builder) (*StatusPage, error) {
+	funcs := template.FuncMap{
+		"stateText":   func(state string) string { return stateTexts[state] },
+		"overallText": func(state string) string { return overallTexts[state] },
+		"dayClass": func(d statuspage.Day) string {
+			switch {
+			case d.Checks == 0:
+				return "nodata"
+			case d.Uptime >= 99.9:
+				return "good"
+			case d.Uptime >= 95:
+				return "warn"
+			default:
+				return "bad"
+			}
+		},
+		"dayTitle": func(d statuspage.Day) string {
+			if d.Checks == 0 {
+				return d.Date + ": sin datos"
+			}
+			return fmt.Sprintf("%s: %.2f%%", d.Date, d.Uptime)
+		},
+		"incidentDuration": func(d time.Duration) string {
+			return d.Round(time.Minute).String()
+		},
+	}
+	tpl, err := template.New("status").Funcs(funcs).Parse(statusTemplate)
+	if err != nil {
+		return nil, err
+	}
+	return &StatusPage{builder: builder, tpl: tpl}, nil
+}
+
+// ServeHTTP implementa http.Handler.
+func (p *StatusPage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	page, err := p.builder.Page(r.Context())
+	if err != nil {
+		http.Error(w, "estado no disponible", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "public, max-age=60")
+	_ = p.tpl.Execute(w, page)
+}
+
+const statusTemplate = `
+<!DOCTYPE html>
+<html lang="es">
+<head>
+  <meta charset="UTF-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <meta http-equiv="refresh" content="60">
+  <title>Estado de los servicios</title>
+  <style>
+	body { font-family: Helvetica, Arial, sans-serif; background: #0f172a; color: #e2e8f0; margin: 0; }
+	main { max-width: 860px; margin: 0 auto; padding: 2rem 1.25rem; display: grid; gap: 1.5rem; }
+	h1 { margin: 0; font-size: 1.6rem; }
+	h2 { margin: 0 0 1rem; font-size: 1.1rem; }
+	.card { background: #1e293b; border-radius: 12px; padding: 1.25rem; }
+	.banner { font-size: 1.15rem; font-weight: 600; }
+	.banner.operational { background: rgba(34,197,94,0.2); color: #4ade80; }
+	.banner.partial_outage, .banner.maintenance { background: rgba(251,191,36,0.2); color: #fbbf24; }
+	.banner.major_outage { background: rgba(239,68,68,0.2); color: #f87171; }
+	.banner.unknown { background: rgba(148,163,184,0.2); color: #cbd5f5; }
+	.component { padding: 0.9rem 0; border-top: 1px solid #334155; }
+	.component:first-of-type { border-top: none; }
+	.component-head { display: flex; justify-content: space-between; gap: 1rem; }
+	.state.operational { color: #4ade80; }
+	.state.partial_outage, .state.maintenance { color: #fbbf24; }
+	.state.major_outage { color: #f87171; }
+	.state.unknown { color: #94a3b8; }
+	.bars { display: flex; gap: 2px; height: 28px; margin: 0.6rem 0 0.3rem; }
+	.bars span { flex: 1; border-radius: 2px; }
+	.good { background: #22c55e; }
+	.warn { background: #f59e0b; }
+	.bad { background: #ef4444; }
+	.nodata { background: #334155; }
+	.meta { display: flex; justify-content: space-between; color: #94a3b8; font-size: 0.8rem; }
+	.incident { padding: 0.6rem 0; border-top: 1px solid #334155; }
+	.incident:first-of-type { border-top: none; }
+	.footer { color: #94a3b8; font-size: 0.85rem; }
+  </style>
+</head>
+<body>
+  <main>
+	<h1>Estado de los servicios</h1>
+	<div class="card banner {{ .State }}">{{ overallText .State }}</div>
+
+	{{- range .Groups }}
+	<section class="card">
+	  <h2>{{ if .Name }}{{ .Name }}{{ else }}Servicios{{ end }} <span class="state {{ .State }}">· {{ stateText .State }}</span></h2>
+	  {{- range .Components }}
+	  <div class="component">
+		<div class="component-head">
+		  <strong>{{ .Name }}</strong>
+		  <span class="state {{ .State }}">{{ stateText .State }}</span>
+		</div>
+		<div class="bars">
+		  {{- range .Days }}<span class="{{ dayClass . }}" title="{{ dayTitle . }}"></span>{{- end }}
+		</div>
+		<div class="meta"><span>hace 90 días</span><span>{{ printf "%.2f" .Uptime }}% de uptime</span><span>hoy</span></div>
+	  </div>
+	  {{- end }}
+	</section>
+	{{- else }}
+	<section class="card"><p class="footer">No hay componentes publicados.</p></section>
+	{{- end }}
+
+	<section class="card">
+	  <h2>Incidentes recientes</h2>
+	  {{- range .Incidents }}
+	  <div class="incident">
+		<strong>{{ .Component }}</strong>
+		{{- if .ResolvedAt }}
+		<span class="state operational">resuelto</span>
+		{{- else }}
+		<span class="state major_outage">en curso</span>
+		{{- end }}
+		<div class="footer">
+		  Desde {{ .StartedAt.UTC.Format "2006-01-02 15:04" }} UTC
+		  {{- with .ResolvedAt }} hasta {{ .UTC.Format "2006-01-02 15:04" }} UTC{{ end }}
+		  · {{ incidentDuration .Duration }}
+		</div>
+	  </div>
+	  {{- else }}
+	  <p class="footer">Sin incidentes en los últimos 30 días.</p>
+	  {{- end }}
+	</section>
+
+	<p class="footer">Actualizado: {{ .UpdatedAt.UTC.Format "2006-01-02 15:04:05" }} UTC</p>
+  </main>
+</body>
+</html>
+`